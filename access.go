@@ -0,0 +1,81 @@
+package glsymbol
+
+// Accessibility export: the text of a frame as a tree, which can be given
+// to screen readers or UI automation tools. The tree is built from the
+// records of a Recorder, so it holds exactly the text on the screen.
+
+// recordGroup is a group of records, see Recorder.BeginGroup.
+type recordGroup struct {
+	name   string
+	parent int // index of the parent group plus one
+}
+
+// BeginGroup starts a named group of text drawn until the matching
+// EndGroup, for example a panel or a widget. Groups may be nested and
+// become nodes of the tree returned by Tree.
+func (r *Recorder) BeginGroup(name string) {
+	r.groups = append(r.groups, recordGroup{name: name, parent: r.group})
+	r.group = len(r.groups)
+}
+
+// EndGroup ends the current group.
+func (r *Recorder) EndGroup() {
+	if r.group != 0 {
+		r.group = r.groups[r.group-1].parent
+	}
+}
+
+// An AccessNode is a node of the tree of drawn text. Leaves hold drawn
+// strings, other nodes are groups. Box is in pixels of the viewport
+// with Y going down, the box of a group holds boxes of its children.
+type AccessNode struct {
+	Name     string        `json:"name,omitempty"`
+	Text     string        `json:"text,omitempty"`
+	Box      Rect          `json:"box"`
+	Children []*AccessNode `json:"children,omitempty"`
+}
+
+// Tree returns the recorded text as a tree of groups in order of drawing.
+// Usually it is called at the end of a frame, before Reset.
+func (r *Recorder) Tree() *AccessNode {
+	nodes := make([]*AccessNode, len(r.groups)+1)
+	nodes[0] = &AccessNode{}
+	node := func(group int) *AccessNode {
+		var path []int
+		for g := group; nodes[g] == nil; g = r.groups[g-1].parent {
+			path = append(path, g)
+		}
+		for i := len(path) - 1; 0 <= i; i-- {
+			g := path[i]
+			n := &AccessNode{Name: r.groups[g-1].name}
+			p := nodes[r.groups[g-1].parent]
+			p.Children = append(p.Children, n)
+			nodes[g] = n
+		}
+		return nodes[group]
+	}
+	for _, rec := range r.Records {
+		n := node(rec.group)
+		n.Children = append(n.Children, &AccessNode{Text: rec.Text, Box: rec.Box})
+	}
+	nodes[0].bounds()
+	return nodes[0]
+}
+
+// bounds sets boxes of groups to the union of boxes of their children.
+func (n *AccessNode) bounds() Rect {
+	if len(n.Children) == 0 {
+		return n.Box
+	}
+	first := true
+	for _, c := range n.Children {
+		b := c.bounds()
+		if first {
+			n.Box = b
+			first = false
+			continue
+		}
+		n.Box = n.Box.union(b)
+	}
+	return n.Box
+}
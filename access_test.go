@@ -0,0 +1,49 @@
+package glsymbol
+
+import "testing"
+
+func TestRecorderTree(t *testing.T) {
+	SetOrigin(OriginTopLeft)
+	defer SetOrigin(OriginBottomLeft)
+
+	f := testFont()
+	r := new(Recorder)
+	add := func(x, y float32, s string) {
+		rec := f.newRecord(x, y, s, 100)
+		rec.group = r.group
+		r.Records = append(r.Records, rec)
+	}
+	add(0, 0, "title")
+	r.BeginGroup("panel")
+	add(10, 20, "ab")
+	r.BeginGroup("empty")
+	r.EndGroup()
+	r.BeginGroup("button")
+	add(10, 40, "ok")
+	r.EndGroup()
+	r.EndGroup()
+	add(0, 80, "status")
+
+	root := r.Tree()
+	if len(root.Children) != 3 {
+		t.Fatalf("%d children of root", len(root.Children))
+	}
+	if root.Box != (Rect{0, 0, 48, 96}) {
+		t.Errorf("root box %v", root.Box)
+	}
+	panel := root.Children[1]
+	if panel.Name != "panel" || len(panel.Children) != 2 || panel.Box != (Rect{10, 20, 16, 36}) {
+		t.Errorf("panel %+v", panel)
+	}
+	if b := panel.Children[1]; b.Name != "button" || b.Children[0].Text != "ok" {
+		t.Errorf("button %+v", b)
+	}
+	if root.Children[2].Text != "status" {
+		t.Errorf("last child %+v", root.Children[2])
+	}
+
+	r.Reset()
+	if root := r.Tree(); len(root.Children) != 0 {
+		t.Errorf("tree after reset %+v", root)
+	}
+}
@@ -0,0 +1,220 @@
+package glsymbol
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Mat4 is a 4x4 matrix in the column-major order of OpenGL.
+type Mat4 [16]float32
+
+// transform returns the product of the matrix and the point p with w = 1.
+func (m *Mat4) transform(p [3]float32) (x, y, z, w float32) {
+	x = m[0]*p[0] + m[4]*p[1] + m[8]*p[2] + m[12]
+	y = m[1]*p[0] + m[5]*p[1] + m[9]*p[2] + m[13]
+	z = m[2]*p[0] + m[6]*p[1] + m[10]*p[2] + m[14]
+	w = m[3]*p[0] + m[7]*p[1] + m[11]*p[2] + m[15]
+	return
+}
+
+// Project returns the window coordinates of the point transformed by
+// the model-view-projection matrix into the viewport, as gluProject:
+// x and y in pixels from the bottom left corner of the window and the
+// depth from 0 at the near plane to 1 at the far plane. It reports false
+// for points outside of the view frustum.
+func Project(mvp *Mat4, p [3]float32, viewport [4]int32) (x, y, depth float32, ok bool) {
+	cx, cy, cz, cw := mvp.transform(p)
+	if cw <= 0 {
+		return 0, 0, 0, false
+	}
+	nx, ny, nz := cx/cw, cy/cw, cz/cw
+	ok = -1 <= nx && nx <= 1 && -1 <= ny && ny <= 1 && -1 <= nz && nz <= 1
+	x = float32(viewport[0]) + (nx+1)/2*float32(viewport[2])
+	y = float32(viewport[1]) + (ny+1)/2*float32(viewport[3])
+	return x, y, (nz + 1) / 2, ok
+}
+
+// An Annotation is a label anchored at a point of a 3D scene.
+type Annotation struct {
+	Pos  [3]float32
+	Text string
+
+	// Height is the height of the text in units of the scene, used to
+	// skip labels which are too small on the screen. Zero means a label
+	// of constant size on the screen.
+	Height float32
+
+	// Short is an abbreviation of Text drawn below ShortPixels.
+	Short string
+
+	// Color is the color of the label, or the text color of the theme
+	// if nil. The label is drawn by the current color if both are nil
+	// and it does not fade.
+	Color color.Color
+}
+
+// Stats counts labels of a frame.
+type Stats struct {
+	Labels   int // all labels
+	Drawn    int // labels given to the batch
+	Frustum  int // skipped outside of the view frustum
+	Occluded int // skipped behind geometry
+	Small    int // skipped smaller than MinPixels
+	Short    int // drawn abbreviated below ShortPixels
+	Faded    int // drawn transparent below FadePixels
+}
+
+// An Annotator places labels of a 3D scene on the screen. Labels are
+// culled before layout: outside of the view frustum, behind geometry
+// if DepthTest is set, and smaller than MinPixels.
+//
+// Labels with Height have a level of detail by their projected height:
+// below ShortPixels they are drawn by the abbreviation, below FadePixels
+// they fade out linearly down to MinPixels, so zoomed out views have no
+// shimmering unreadable text. Fading requires blending, for example
+//
+//	gl.Enable(gl.BLEND)
+//	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+type Annotator struct {
+	MVP      Mat4     // model-view-projection matrix of the scene
+	Viewport [4]int32 // x, y, width and height as gl.Viewport
+
+	// DepthTest skips labels whose anchors are behind the depth buffer
+	// read by ReadDepth, with the tolerance DepthBias.
+	DepthTest bool
+	DepthBias float32
+
+	MinPixels   float32 // smallest projected height of labels with Height
+	ShortPixels float32 // projected height below which Short is drawn
+	FadePixels  float32 // projected height below which labels fade
+
+	Stats Stats // counts since the last Reset
+
+	depth []float32 // depth buffer of the viewport
+}
+
+// Reset zeroes the statistics, usually at the beginning of a frame.
+func (a *Annotator) Reset() {
+	a.Stats = Stats{}
+}
+
+// ReadDepth reads the depth buffer of the viewport for DepthTest. It
+// must be called after the scene is drawn and before labels are added.
+func (a *Annotator) ReadDepth() error {
+	w, h := a.Viewport[2], a.Viewport[3]
+	if w <= 0 || h <= 0 {
+		a.depth = a.depth[:0]
+		return nil
+	}
+	if n := int(w * h); cap(a.depth) < n {
+		a.depth = make([]float32, n)
+	} else {
+		a.depth = a.depth[:n]
+	}
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 4)
+	gl.ReadPixels(a.Viewport[0], a.Viewport[1], w, h, gl.DEPTH_COMPONENT, gl.FLOAT, gl.Ptr(a.depth))
+	return checkGLError("ReadDepth")
+}
+
+// occluded reports whether the point of the window is behind the depth buffer.
+func (a *Annotator) occluded(x, y, depth float32) bool {
+	w, h := int(a.Viewport[2]), int(a.Viewport[3])
+	if len(a.depth) != w*h {
+		return false
+	}
+	px, py := int(x)-int(a.Viewport[0]), int(y)-int(a.Viewport[1])
+	if px < 0 || py < 0 || w <= px || h <= py {
+		return false
+	}
+	return a.depth[py*w+px]+a.DepthBias < depth
+}
+
+// projection is an annotation projected into the window.
+type projection struct {
+	x, y, depth float32 // window coordinates of the anchor
+	pixels      float32 // projected height, zero for labels without Height
+	inside      bool    // inside of the view frustum
+}
+
+// project projects the annotation into the window.
+func (a *Annotator) project(ann *Annotation) (p projection) {
+	p.x, p.y, p.depth, p.inside = Project(&a.MVP, ann.Pos, a.Viewport)
+	if p.inside && 0 < ann.Height {
+		top := ann.Pos
+		top[1] += ann.Height
+		tx, ty, _, _ := Project(&a.MVP, top, a.Viewport)
+		p.pixels = float32(math.Hypot(float64(tx-p.x), float64(ty-p.y)))
+	}
+	return
+}
+
+// visible reports whether the projected annotation is visible.
+func (a *Annotator) visible(ann *Annotation, p *projection) bool {
+	a.Stats.Labels++
+	switch {
+	case !p.inside:
+		a.Stats.Frustum++
+	case 0 < ann.Height && p.pixels < a.MinPixels:
+		a.Stats.Small++
+	case a.DepthTest && a.occluded(p.x, p.y, p.depth):
+		a.Stats.Occluded++
+	default:
+		return true
+	}
+	return false
+}
+
+// windowToDrawing converts the Y coordinate of the window to the
+// coordinates of SetupOrtho for the viewport.
+func (a *Annotator) windowToDrawing(y float32) float32 {
+	if origin == OriginTopLeft {
+		return float32(a.Viewport[3]) - y
+	}
+	return y
+}
+
+// Add adds the label of the annotation to the batch, if it is visible.
+// The label is centered over its anchor. It reports whether the label
+// is added.
+func (a *Annotator) Add(b *Batch, layer int, f *Font, ann Annotation) bool {
+	p := a.project(&ann)
+	return a.add(b, layer, f, &ann, &p)
+}
+
+// add adds the label of the projected annotation to the batch.
+func (a *Annotator) add(b *Batch, layer int, f *Font, ann *Annotation, p *projection) bool {
+	if !a.visible(ann, p) {
+		return false
+	}
+	text, c := a.detail(ann, p.pixels)
+	w := f.advanceSize(text)
+	b.Printf(layer, f, p.x-float32(w)/2+float32(f.anchor(w)), a.windowToDrawing(p.y), text, c)
+	a.Stats.Drawn++
+	return true
+}
+
+// detail returns the text and the color of the annotation with the
+// projected height in pixels.
+func (a *Annotator) detail(ann *Annotation, pixels float32) (text string, c color.Color) {
+	text, c = ann.Text, ann.Color
+	if c == nil {
+		c = theme.color(ColorText)
+	}
+	if ann.Height <= 0 {
+		return
+	}
+	if ann.Short != "" && pixels < a.ShortPixels {
+		text = ann.Short
+		a.Stats.Short++
+	}
+	if pixels < a.FadePixels && a.MinPixels < a.FadePixels {
+		if c == nil {
+			c = color.White
+		}
+		c = fadeColor(c, (pixels-a.MinPixels)/(a.FadePixels-a.MinPixels))
+		a.Stats.Faded++
+	}
+	return
+}
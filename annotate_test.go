@@ -0,0 +1,95 @@
+package glsymbol
+
+import (
+	"image/color"
+	"testing"
+)
+
+// testMVP returns an orthographic projection of the box -10..10.
+func testMVP() Mat4 {
+	return Mat4{
+		0.1, 0, 0, 0,
+		0, 0.1, 0, 0,
+		0, 0, -0.1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func TestProject(t *testing.T) {
+	mvp := testMVP()
+	vp := [4]int32{0, 0, 200, 100}
+	x, y, depth, ok := Project(&mvp, [3]float32{5, -5, 0}, vp)
+	if !ok || x != 150 || y != 25 || depth != 0.5 {
+		t.Errorf("project %v %v %v %v", x, y, depth, ok)
+	}
+	if _, _, _, ok := Project(&mvp, [3]float32{11, 0, 0}, vp); ok {
+		t.Errorf("point outside of the frustum")
+	}
+}
+
+func TestAnnotatorCulling(t *testing.T) {
+	f := testFont()
+	a := Annotator{
+		MVP:       testMVP(),
+		Viewport:  [4]int32{0, 0, 20, 10},
+		DepthTest: true,
+		MinPixels: 2,
+	}
+	// depth buffer with geometry at the right half
+	a.depth = make([]float32, 20*10)
+	for i := range a.depth {
+		a.depth[i] = 1
+		if 10 <= i%20 {
+			a.depth[i] = 0.25
+		}
+	}
+	var b Batch
+	for _, ann := range []Annotation{
+		{Pos: [3]float32{-5, 0, 0}, Text: "A"},              // drawn
+		{Pos: [3]float32{-5, 0, 0}, Text: "A", Height: 4},   // drawn
+		{Pos: [3]float32{-5, 0, 0}, Text: "A", Height: 0.1}, // small
+		{Pos: [3]float32{5, 0, 0}, Text: "A"},               // occluded
+		{Pos: [3]float32{0, 20, 0}, Text: "A"},              // frustum
+	} {
+		a.Add(&b, LayerLabel, f, ann)
+	}
+	expect := Stats{Labels: 5, Drawn: 2, Frustum: 1, Occluded: 1, Small: 1}
+	if a.Stats != expect {
+		t.Errorf("stats %+v, expect %+v", a.Stats, expect)
+	}
+	if b.Len() != 2 {
+		t.Errorf("batch length %d", b.Len())
+	}
+	a.Reset()
+	if a.Stats != (Stats{}) {
+		t.Errorf("stats after reset")
+	}
+}
+
+func TestAnnotatorDetail(t *testing.T) {
+	a := Annotator{MinPixels: 2, ShortPixels: 8, FadePixels: 6}
+	ann := Annotation{Text: "Temperature", Short: "T", Height: 1, Color: color.RGBA{0xff, 0, 0, 0xff}}
+	for _, tc := range []struct {
+		pixels float32
+		text   string
+		alpha  uint8
+	}{
+		{10, "Temperature", 0xff},
+		{7, "T", 0xff},
+		{4, "T", 0x7f},
+		{2, "T", 0},
+	} {
+		text, c := a.detail(&ann, tc.pixels)
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		if text != tc.text || n.A != tc.alpha {
+			t.Errorf("%v pixels: %q %v, expect %q alpha %x", tc.pixels, text, n, tc.text, tc.alpha)
+		}
+	}
+	if a.Stats.Short != 3 || a.Stats.Faded != 2 {
+		t.Errorf("stats %+v", a.Stats)
+	}
+	ann.Height = 0
+	if text, c := a.detail(&ann, 0); text != ann.Text || c != ann.Color {
+		t.Errorf("label of constant size has detail %q %v", text, c)
+	}
+}
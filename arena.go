@@ -0,0 +1,110 @@
+package glsymbol
+
+// A FrameArena provides memory for transient data of layout and drawing,
+// which is needed only while a frame is drawn. Set Font.Arena and call
+// Reset once per frame, then after the first frames no memory is
+// allocated for such data.
+//
+// Slices returned by the arena are valid until the next Reset.
+// A nil arena allocates every slice from the heap.
+// A FrameArena must not be used from several goroutines at once.
+type FrameArena struct {
+	runes    arenaChunk[rune]
+	ints     arenaChunk[int]
+	int32s   arenaChunk[int32]
+	float32s arenaChunk[float32]
+	strings  arenaChunk[string]
+}
+
+// Reset makes all memory of the arena available again.
+// Slices returned before Reset must not be used after it.
+func (a *FrameArena) Reset() {
+	if a == nil {
+		return
+	}
+	a.runes.reset()
+	a.ints.reset()
+	a.int32s.reset()
+	a.float32s.reset()
+	a.strings.reset()
+}
+
+// Runes returns a zeroed slice of n runes.
+func (a *FrameArena) Runes(n int) []rune {
+	if a == nil {
+		return make([]rune, n)
+	}
+	return a.runes.alloc(n)
+}
+
+// Ints returns a zeroed slice of n int values.
+func (a *FrameArena) Ints(n int) []int {
+	if a == nil {
+		return make([]int, n)
+	}
+	return a.ints.alloc(n)
+}
+
+// Int32s returns a zeroed slice of n int32 values.
+func (a *FrameArena) Int32s(n int) []int32 {
+	if a == nil {
+		return make([]int32, n)
+	}
+	return a.int32s.alloc(n)
+}
+
+// Float32s returns a zeroed slice of n float32 values.
+func (a *FrameArena) Float32s(n int) []float32 {
+	if a == nil {
+		return make([]float32, n)
+	}
+	return a.float32s.alloc(n)
+}
+
+// Strings returns a slice of n empty strings.
+func (a *FrameArena) Strings(n int) []string {
+	if a == nil {
+		return make([]string, n)
+	}
+	return a.strings.alloc(n)
+}
+
+// arenaChunk is a bump allocator of values of one type.
+type arenaChunk[T any] struct {
+	buf  []T
+	used int // values allocated from buf
+	need int // values requested since the last reset
+}
+
+func (c *arenaChunk[T]) alloc(n int) []T {
+	c.need += n
+	if len(c.buf)-c.used < n {
+		// Slices returned before keep the old buffer alive,
+		// so a new buffer is used from the beginning.
+		size := 2 * len(c.buf)
+		if size < n {
+			size = n
+		}
+		if size < 64 {
+			size = 64
+		}
+		c.buf = make([]T, size)
+		c.used = 0
+	}
+	s := c.buf[c.used : c.used+n : c.used+n]
+	c.used += n
+	var zero T
+	for i := range s {
+		s[i] = zero
+	}
+	return s
+}
+
+func (c *arenaChunk[T]) reset() {
+	if len(c.buf) < c.need {
+		// the frame did not fit, grow to the frame size at once
+		c.buf = make([]T, c.need)
+	}
+	c.used = 0
+	c.need = 0
+}
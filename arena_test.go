@@ -0,0 +1,82 @@
+package glsymbol
+
+import "testing"
+
+func TestFrameArena(t *testing.T) {
+	var a FrameArena
+	r := a.Runes(3)
+	copy(r, []rune("abc"))
+	i := a.Int32s(100)
+	i[99] = 42
+	r2 := a.Runes(2)
+	if len(r) != 3 || len(r2) != 2 || len(i) != 100 {
+		t.Fatalf("unexpected length")
+	}
+	r2 = append(r2, 'x') // must not overwrite next allocations
+	r3 := a.Runes(1)
+	if r3[0] != 0 || string(r) != "abc" {
+		t.Errorf("arena slices overlap")
+	}
+
+	frame := func() {
+		a.Reset()
+		for k := 0; k < 100; k++ {
+			_ = a.Runes(30)
+			_ = a.Int32s(30)
+			_ = a.Float32s(30)
+		}
+	}
+	frame()
+	if n := testing.AllocsPerRun(10, frame); n != 0 {
+		t.Errorf("steady state allocations: %v", n)
+	}
+}
+
+func TestFrameArenaNil(t *testing.T) {
+	var a *FrameArena
+	a.Reset()
+	if len(a.Runes(2)) != 2 || len(a.Int32s(3)) != 3 || len(a.Float32s(4)) != 4 {
+		t.Errorf("unexpected length")
+	}
+}
+
+// fixedShaper places the same glyphs for any text.
+type fixedShaper []ShapedGlyph
+
+func (s fixedShaper) Shape(f *Font, runes []rune) []ShapedGlyph {
+	return s
+}
+
+func TestFrameArenaLayout(t *testing.T) {
+	f := testFont()
+	f.Arena = new(FrameArena)
+	place := func(s string) func() {
+		return func() {
+			f.Arena.Reset()
+			f.EachGlyph(s, func(PlacedGlyph) bool { return true })
+		}
+	}
+	// rune buffers of joining of Arabic letters
+	place("abc سلام")()
+	if n := testing.AllocsPerRun(10, place("abc سلام")); n != 0 {
+		t.Errorf("joining allocates %v times", n)
+	}
+	// rune and offset buffers of shaping
+	f.Shaper = fixedShaper{{Rune: 'a', Advance: 8}, {Rune: 'b', Cluster: 1, Advance: 8}}
+	place("ab")()
+	if n := testing.AllocsPerRun(10, place("ab")); n != 0 {
+		t.Errorf("shaping allocates %v times", n)
+	}
+	// words of lines of paragraphs
+	f.Shaper = nil
+	layout := func() {
+		f.Arena.Reset()
+		f.LayoutParagraphs("aa bb cc dd ee", 40, ParagraphStyle{})
+	}
+	layout()
+	withArena := testing.AllocsPerRun(10, layout)
+	f.Arena = nil
+	if n := testing.AllocsPerRun(10, layout); n <= withArena {
+		t.Errorf("layout allocates %v times with the arena, %v times without", withArena, n)
+	}
+}
@@ -0,0 +1,130 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/opentype"
+)
+
+// rasterizeData parses the truetype or OpenType font and draws glyphs of
+// runes of the ranges into a sprite sheet with pad blank pixels around
+// glyph cells. It needs no OpenGL context.
+func rasterizeData(ctx context.Context, data []byte, scale int32, ranges []RuneRange, pad int) (*image.RGBA, *FontConfig, error) {
+	if isOpentype(data) {
+		otf, err := opentype.Parse(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		img, fc, err := rasterizeOpentype(ctx, otf, scale, ranges, pad)
+		if err != nil {
+			return nil, nil, err
+		}
+		return img, fc, addKerning(fc, data, nil, otf, scale)
+	}
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	img, fc, err := rasterizeTruetype(ctx, ttf, scale, ranges, pad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, fc, addKerning(fc, data, ttf, nil, scale)
+}
+
+// LoadTruetypeContext loads a font as LoadTruetypeRanges. Rasterization
+// of glyphs, the long part of loads of large ranges, stops promptly with
+// the error of the context once the context is done.
+func LoadTruetypeContext(ctx context.Context, data []byte, scale int32, ranges ...RuneRange) (*Font, error) {
+	return loadTruetypeData(ctx, data, scale, ranges, 0)
+}
+
+// loadTruetypeData rasterizes the font as rasterizeData and loads it.
+func loadTruetypeData(ctx context.Context, data []byte, scale int32, ranges []RuneRange, pad int) (*Font, error) {
+	img, fc, err := rasterizeData(ctx, data, scale, ranges, pad)
+	if err != nil {
+		return nil, err
+	}
+	subst, err := newSubstitutions(data, scale, fc)
+	if err != nil {
+		return nil, err
+	}
+	f, err := loadFont(img, fc)
+	if err != nil {
+		return nil, err
+	}
+	f.size = scale
+	f.subst = subst
+	return f, nil
+}
+
+// A FontLoad is a font rasterized in the background, see
+// LoadTruetypeAsync.
+type FontLoad struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	scale  int32
+
+	// set before done is closed
+	img   *image.RGBA
+	fc    *FontConfig
+	subst *substitutions
+	err   error
+
+	font *Font // font created by Font
+}
+
+// LoadTruetypeAsync rasterizes glyphs of a font as LoadTruetypeContext
+// on a new goroutine, so the OpenGL thread is not blocked by loads of
+// large ranges. Cancel, or the end of the context, abandons the load,
+// for example when the user changes settings again. The data must not
+// be modified until the load is done.
+func LoadTruetypeAsync(ctx context.Context, data []byte, scale int32, ranges ...RuneRange) *FontLoad {
+	ctx, cancel := context.WithCancel(ctx)
+	l := &FontLoad{
+		done:   make(chan struct{}),
+		cancel: cancel,
+		scale:  scale,
+	}
+	go func() {
+		defer close(l.done)
+		defer cancel()
+		l.img, l.fc, l.err = rasterizeData(ctx, data, scale, ranges, 0)
+		if l.err == nil {
+			l.subst, l.err = newSubstitutions(data, scale, l.fc)
+		}
+	}()
+	return l
+}
+
+// Done returns a channel which is closed once rasterization ends,
+// successfully or not.
+func (l *FontLoad) Done() <-chan struct{} {
+	return l.done
+}
+
+// Cancel abandons the load. The error of Font is context.Canceled
+// unless rasterization has already ended.
+func (l *FontLoad) Cancel() {
+	l.cancel()
+}
+
+// Font waits for the end of rasterization and returns the font, which
+// is created on the first call. It must be called on the goroutine
+// holding the OpenGL context.
+func (l *FontLoad) Font() (*Font, error) {
+	<-l.done
+	if l.err != nil || l.font != nil {
+		return l.font, l.err
+	}
+	l.font, l.err = loadFont(l.img, l.fc)
+	if l.err != nil {
+		return nil, l.err
+	}
+	l.font.size = l.scale
+	l.font.subst = l.subst
+	l.img, l.fc, l.subst = nil, nil, nil
+	return l.font, nil
+}
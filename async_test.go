@@ -0,0 +1,30 @@
+package glsymbol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestLoadTruetypeAsync(t *testing.T) {
+	l := LoadTruetypeAsync(context.Background(), goregular.TTF, 12, ASCII...)
+	<-l.Done()
+	if l.err != nil {
+		t.Fatal(l.err)
+	}
+	if len(l.fc.Glyphs) != 95 || l.img == nil {
+		t.Errorf("%d glyphs", len(l.fc.Glyphs))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = LoadTruetypeAsync(ctx, goregular.TTF, 12, Union(Latin1, Cyrillic)...)
+	if _, err := l.Font(); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := LoadTruetypeContext(ctx, goregular.TTF, 12, RuneRange{32, 126}); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Atlas files store a rendered sprite sheet together with its FontConfig,
+// so a font can be restored without rasterizing it again.
+//
+// Layout of an atlas file, all integers are little-endian:
+//
+//	magic   [4]byte  "GLSA"
+//	major   uint16   incompatible format revision
+//	minor   uint16   backward compatible revision
+//	crc     uint32   CRC-32 (IEEE) of everything after the header
+//	config  uint32 length, followed by the JSON encoded FontConfig
+//	image   uint32 length, followed by the PNG encoded sprite sheet
+//	...     sections added by newer minor revisions
+//
+// Compatibility rules:
+//   - a reader refuses files with another major revision;
+//   - a reader accepts files with a newer minor revision and ignores
+//     any data following the sections it knows about;
+//   - a writer always writes the current revision.
+const (
+	atlasMagic = "GLSA"
+
+	// AtlasMajor is the major revision of the atlas format written by
+	// this package.
+	AtlasMajor = 1
+
+	// AtlasMinor is the minor revision of the atlas format written by
+	// this package.
+	AtlasMinor = 0
+
+	atlasHeaderSize = 4 + 2 + 2 + 4
+
+	// atlasMaxSection limits the size of a single section in order to
+	// reject corrupted length fields before allocating memory.
+	atlasMaxSection = 1 << 28
+)
+
+var (
+	// ErrAtlasMagic is returned when data is not an atlas file.
+	ErrAtlasMagic = errors.New("glsymbol: not an atlas file")
+
+	// ErrAtlasChecksum is returned when atlas data is corrupted.
+	ErrAtlasChecksum = errors.New("glsymbol: atlas checksum mismatch")
+)
+
+// AtlasVersionError is returned for atlas files written with
+// an incompatible revision of the format.
+type AtlasVersionError struct {
+	Major, Minor uint16
+}
+
+func (e *AtlasVersionError) Error() string {
+	return fmt.Sprintf("glsymbol: unsupported atlas version %d.%d, expected %d.x",
+		e.Major, e.Minor, AtlasMajor)
+}
+
+// SaveAtlas writes the font sprite sheet and configuration to w.
+// The result can be loaded by LoadAtlas.
+func (f *Font) SaveAtlas(w io.Writer) error {
+	if f.Config == nil || f.img == nil {
+		return fmt.Errorf("glsymbol: font is released")
+	}
+	return EncodeAtlas(w, f.img, f.Config)
+}
+
+// LoadAtlas loads a font previously stored by SaveAtlas.
+func LoadAtlas(r io.Reader) (*Font, error) {
+	img, config, err := readAtlas(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadFont(img, config)
+}
+
+// EncodeAtlas writes the sprite sheet and configuration of a bitmap font
+// in atlas format. The result can be loaded by LoadAtlas.
+func EncodeAtlas(w io.Writer, img image.Image, config *FontConfig) error {
+	if err := config.Validate(img.Bounds()); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	section := func(data []byte) {
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(data)))
+		body.Write(size[:])
+		body.Write(data)
+	}
+
+	cfg, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("glsymbol: encode atlas config: %v", err)
+	}
+	section(cfg)
+
+	var pic bytes.Buffer
+	if err := png.Encode(&pic, img); err != nil {
+		return fmt.Errorf("glsymbol: encode atlas image: %v", err)
+	}
+	section(pic.Bytes())
+
+	var header [atlasHeaderSize]byte
+	copy(header[:4], atlasMagic)
+	binary.LittleEndian.PutUint16(header[4:], AtlasMajor)
+	binary.LittleEndian.PutUint16(header[6:], AtlasMinor)
+	binary.LittleEndian.PutUint32(header[8:], crc32.ChecksumIEEE(body.Bytes()))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(body.Bytes())
+	return err
+}
+
+// readAtlas decodes data written by EncodeAtlas.
+func readAtlas(r io.Reader) (img *image.RGBA, config *FontConfig, err error) {
+	if err = faultError("readAtlas"); err != nil {
+		return
+	}
+	var header [atlasHeaderSize]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrAtlasMagic
+		}
+		return
+	}
+	if string(header[:4]) != atlasMagic {
+		err = ErrAtlasMagic
+		return
+	}
+	major := binary.LittleEndian.Uint16(header[4:])
+	minor := binary.LittleEndian.Uint16(header[6:])
+	if major != AtlasMajor {
+		err = &AtlasVersionError{Major: major, Minor: minor}
+		return
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[8:]) {
+		err = ErrAtlasChecksum
+		return
+	}
+
+	section := func(name string) (data []byte, err error) {
+		if len(body) < 4 {
+			return nil, fmt.Errorf("glsymbol: atlas %s section is truncated", name)
+		}
+		size := binary.LittleEndian.Uint32(body)
+		if atlasMaxSection < size || uint32(len(body)-4) < size {
+			return nil, fmt.Errorf("glsymbol: atlas %s section is truncated", name)
+		}
+		data, body = body[4:4+size], body[4+size:]
+		return data, nil
+	}
+
+	cfg, err := section("config")
+	if err != nil {
+		return
+	}
+	config = new(FontConfig)
+	if err = json.Unmarshal(cfg, config); err != nil {
+		err = fmt.Errorf("glsymbol: decode atlas config: %v", err)
+		return
+	}
+
+	pic, err := section("image")
+	if err != nil {
+		return
+	}
+	src, err := png.Decode(bytes.NewReader(pic))
+	if err != nil {
+		err = fmt.Errorf("glsymbol: decode atlas image: %v", err)
+		return
+	}
+	img, ok := src.(*image.RGBA)
+	if !ok {
+		img = image.NewRGBA(src.Bounds())
+		draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	}
+
+	if err = config.Validate(img.Bounds()); err != nil {
+		return
+	}
+
+	// Remaining sections belong to newer minor revisions.
+	return img, config, nil
+}
@@ -0,0 +1,116 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func testAtlas() (*image.RGBA, *FontConfig) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	img.Set(1, 1, color.White)
+	img.Set(9, 2, color.White)
+	config := &FontConfig{
+		Low:  'a',
+		High: 'b',
+		Glyphs: Charset{
+			{X: 0, Y: 0, Width: 8, Height: 8, Advance: 8},
+			{X: 8, Y: 0, Width: 8, Height: 8, Advance: 7},
+		},
+	}
+	return img, config
+}
+
+func TestAtlasRoundTrip(t *testing.T) {
+	img, config := testAtlas()
+	var buf bytes.Buffer
+	if err := EncodeAtlas(&buf, img, config); err != nil {
+		t.Fatal(err)
+	}
+	rimg, rconfig, err := readAtlas(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rimg.Pix, img.Pix) {
+		t.Errorf("image is not same")
+	}
+	if rconfig.Low != config.Low || rconfig.High != config.High {
+		t.Errorf("range is not same: %v", rconfig)
+	}
+	for i := range config.Glyphs {
+		if rconfig.Glyphs[i].Advance != config.Glyphs[i].Advance ||
+			rconfig.Glyphs[i].X != config.Glyphs[i].X {
+			t.Errorf("glyph %d is not same: %v", i, rconfig.Glyphs[i])
+		}
+	}
+}
+
+func TestAtlasErrors(t *testing.T) {
+	img, config := testAtlas()
+	var buf bytes.Buffer
+	if err := EncodeAtlas(&buf, img, config); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	t.Run("magic", func(t *testing.T) {
+		_, _, err := readAtlas(bytes.NewReader([]byte("PNG")))
+		if err != ErrAtlasMagic {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("major", func(t *testing.T) {
+		b := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint16(b[4:], AtlasMajor+1)
+		_, _, err := readAtlas(bytes.NewReader(b))
+		var ve *AtlasVersionError
+		if !errors.As(err, &ve) || ve.Major != AtlasMajor+1 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("minor", func(t *testing.T) {
+		// newer minor revision with an unknown trailing section
+		b := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint16(b[6:], AtlasMinor+1)
+		b = append(b, 3, 0, 0, 0, 'n', 'e', 'w')
+		binary.LittleEndian.PutUint32(b[8:], crc(b[atlasHeaderSize:]))
+		if _, _, err := readAtlas(bytes.NewReader(b)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("checksum", func(t *testing.T) {
+		b := append([]byte(nil), data...)
+		b[len(b)-1] ^= 0xFF
+		if _, _, err := readAtlas(bytes.NewReader(b)); err != ErrAtlasChecksum {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("truncated", func(t *testing.T) {
+		b := append([]byte(nil), data[:atlasHeaderSize+6]...)
+		binary.LittleEndian.PutUint32(b[8:], crc(b[atlasHeaderSize:]))
+		if _, _, err := readAtlas(bytes.NewReader(b)); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func crc(b []byte) uint32 {
+	return crc32.ChecksumIEEE(b)
+}
+
+func TestLoadBitmapInvalid(t *testing.T) {
+	img, config := testAtlas()
+	if _, err := LoadBitmap(img, nil); err == nil {
+		t.Errorf("font without config is loaded")
+	}
+	// glyph rectangles are relative to the image
+	sub := img.SubImage(image.Rect(4, 0, 16, 8))
+	if _, err := LoadBitmap(sub, config); err == nil || !strings.Contains(err.Error(), "outside of sprite sheet") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
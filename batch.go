@@ -0,0 +1,125 @@
+package glsymbol
+
+import (
+	"image/color"
+	"sort"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Layers of a Batch. Any other values may be used, items of lower layers
+// are drawn first.
+const (
+	LayerBackground = -100 // under all labels
+	LayerLabel      = 0    // usual labels
+	LayerSelected   = 100  // selected or hovered labels, over other labels
+)
+
+// A Batch collects text and boxes of many labels and draws them at once
+// by Flush, ordered by layer instead of the order of calls. In a layer
+// all boxes are drawn before all text, so the background of a label never
+// covers the text of another label of the same layer.
+//
+// A Batch may be reused for every frame.
+type Batch struct {
+	items []batchItem
+}
+
+type batchItem struct {
+	layer int
+	text  bool // text or a box
+
+	// text
+	font  *Font
+	x, y  float32
+	str   string
+	color color.Color
+
+	// box
+	rect  Rect
+	frame Frame
+}
+
+// Printf adds the string drawn as Printf by the color, or by the current
+// color at the moment of Flush if c is nil.
+func (b *Batch) Printf(layer int, f *Font, x, y float32, str string, c color.Color) {
+	b.items = append(b.items, batchItem{layer: layer, text: true, font: f, x: x, y: y, str: str, color: c})
+}
+
+// Box adds the frame drawn in the rectangle.
+func (b *Batch) Box(layer int, r Rect, frame Frame) {
+	b.items = append(b.items, batchItem{layer: layer, rect: r, frame: frame})
+}
+
+// Label adds a label as Font.DrawTextBox would draw it: the frame and
+// the text inside of it, with the top left corner at x, y. It returns
+// the box of the label.
+func (b *Batch) Label(layer int, f *Font, x, y float32, text string, frame Frame, padding float32, c color.Color) Rect {
+	w, h := f.TextBoxSize(text, frame, padding)
+	box := Rect{X: x, Y: linePos(y, h), Width: w, Height: h}
+	var l, t float32
+	if frame != nil {
+		b.Box(layer, box, frame)
+		l, t, _, _ = frame.Insets()
+	}
+	tx, ty := x+l+padding, below(y, t+padding)
+	lh := float32(f.lineHeight())
+	for _, line := range splitLines(text) {
+		b.Printf(layer, f, f.startX(tx, line), f.lineY(ty), line, c)
+		ty = below(ty, lh)
+	}
+	return box
+}
+
+// Len returns the amount of items in the batch.
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+// sort orders items by layer, boxes before text, keeping the order of
+// calls otherwise.
+func (b *Batch) sort() {
+	sort.SliceStable(b.items, func(i, j int) bool {
+		a, c := &b.items[i], &b.items[j]
+		if a.layer != c.layer {
+			return a.layer < c.layer
+		}
+		return !a.text && c.text
+	})
+}
+
+// Flush draws all items and empties the batch.
+func (b *Batch) Flush() error {
+	b.sort()
+	defer b.Reset()
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	var current [4]float32
+	gl.GetFloatv(gl.CURRENT_COLOR, &current[0])
+	for i := range b.items {
+		it := &b.items[i]
+		if !it.text {
+			if err := it.frame.Draw(it.rect); err != nil {
+				return err
+			}
+			continue
+		}
+		if it.color != nil {
+			setColor(it.color)
+		} else {
+			gl.Color4fv(&current[0])
+		}
+		if err := it.font.Printf(it.x, it.y, it.str); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset removes all items without drawing.
+func (b *Batch) Reset() {
+	for i := range b.items {
+		b.items[i] = batchItem{}
+	}
+	b.items = b.items[:0]
+}
@@ -0,0 +1,44 @@
+package glsymbol
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestBatchOrder(t *testing.T) {
+	f := testFont()
+	frame := RoundedFrame{Fill: color.Black}
+	var b Batch
+	b.Label(LayerSelected, f, 0, 0, "selected", frame, 2, nil)
+	b.Label(LayerLabel, f, 10, 10, "one\ntwo", frame, 2, nil)
+	b.Printf(LayerLabel, f, 0, 0, "plain", nil)
+	b.Box(LayerBackground, Rect{}, frame)
+	b.Label(LayerLabel, f, 20, 20, "three", frame, 2, nil)
+
+	b.sort()
+	var order []string
+	for _, it := range b.items {
+		s := "box"
+		if it.text {
+			s = it.str
+		}
+		order = append(order, s)
+	}
+	expect := []string{"box", "box", "box", "one", "two", "plain", "three", "box", "selected"}
+	if len(order) != len(expect) {
+		t.Fatalf("order %v", order)
+	}
+	for i := range expect {
+		if order[i] != expect[i] {
+			t.Fatalf("order %v, expect %v", order, expect)
+		}
+	}
+	if b.items[0].layer != LayerBackground {
+		t.Errorf("first box is not the background")
+	}
+
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("items after reset")
+	}
+}
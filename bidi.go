@@ -0,0 +1,330 @@
+package glsymbol
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// bidiLine is a single line of text with its visual order by the Unicode
+// bidirectional algorithm.
+type bidiLine struct {
+	runes   []rune
+	offsets []int   // byte offsets of runes, with the length of the text at the end
+	levels  []uint8 // embedding levels of runes, odd levels are right-to-left
+	visual  []int   // indices of runes from left to right on the screen
+	vis     []int   // visual positions of runes, the inverse of visual
+	rtl     bool    // base direction is right-to-left
+}
+
+// A BidiMode selects the base direction of lines reordered by the
+// Unicode bidirectional algorithm, UAX #9, see Font.Bidi.
+type BidiMode int
+
+// Modes of reordering of text.
+const (
+	BidiOff  BidiMode = iota // runes are drawn in logical order, the default
+	BidiAuto                 // direction of the first strong character, left-to-right if there is none
+	BidiLTR                  // left-to-right lines
+	BidiRTL                  // right-to-left lines
+)
+
+// newBidiLine orders the line. The base direction is given by the first
+// strong character, or by rtl if there is none.
+func newBidiLine(s string, rtl bool) *bidiLine {
+	return orderLine(s, rtl, true)
+}
+
+// orderLine orders the line with the base direction rtl, or with the
+// direction of the first strong character if detect is set.
+func orderLine(s string, rtl, detect bool) *bidiLine {
+	l := &bidiLine{rtl: rtl}
+	for i, r := range s {
+		l.runes = append(l.runes, r)
+		l.offsets = append(l.offsets, i)
+	}
+	l.offsets = append(l.offsets, len(s))
+	n := len(l.runes)
+
+	for _, r := range l.runes {
+		if !detect {
+			break
+		}
+		p, _ := bidi.LookupRune(r)
+		if c := p.Class(); c == bidi.L {
+			l.rtl = false
+			break
+		} else if c == bidi.R || c == bidi.AL {
+			l.rtl = true
+			break
+		}
+	}
+
+	l.levels = make([]uint8, n)
+	if 0 < n {
+		l.resolveLevels(s)
+	}
+
+	// reverse sequences from the highest level to the lowest odd level
+	l.visual = make([]int, n)
+	for i := range l.visual {
+		l.visual[i] = i
+	}
+	var high, low uint8 = 0, 0xff
+	for _, lv := range l.levels {
+		if high < lv {
+			high = lv
+		}
+		if lv%2 == 1 && lv < low {
+			low = lv
+		}
+	}
+	for lv := high; low <= lv && 0 < lv; lv-- {
+		for i := 0; i < n; {
+			if l.levels[l.visual[i]] < lv {
+				i++
+				continue
+			}
+			j := i
+			for j < n && lv <= l.levels[l.visual[j]] {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				l.visual[a], l.visual[b] = l.visual[b], l.visual[a]
+			}
+			i = j
+		}
+	}
+	l.vis = make([]int, n)
+	for v, i := range l.visual {
+		l.vis[i] = v
+	}
+	return l
+}
+
+// resolveLevels sets embedding levels of runes by directional runs.
+func (l *bidiLine) resolveLevels(s string) {
+	var base uint8
+	dir := bidi.LeftToRight
+	if l.rtl {
+		base = 1
+		dir = bidi.RightToLeft
+	}
+	// the paragraph takes the direction of the first strong character
+	// unless it is right-to-left, a left-to-right mark keeps it
+	shift := 0
+	if !l.rtl {
+		s, shift = "\u200e"+s, 1
+	}
+	var p bidi.Paragraph
+	if _, err := p.SetString(s, bidi.DefaultDirection(dir)); err != nil {
+		l.fillLevels(0, len(l.runes)-1, base)
+		return
+	}
+	o, err := p.Order()
+	if err != nil {
+		l.fillLevels(0, len(l.runes)-1, base)
+		return
+	}
+	type run struct {
+		start, end int
+		rtl        bool
+	}
+	runs := make([]run, o.NumRuns())
+	for i := range runs {
+		r := o.Run(i)
+		runs[i].start, runs[i].end = r.Pos()
+		runs[i].start -= shift
+		runs[i].end -= shift
+		if runs[i].start < 0 {
+			runs[i].start = 0
+		}
+		runs[i].rtl = r.Direction() == bidi.RightToLeft
+	}
+	for i, r := range runs {
+		var lv uint8
+		switch {
+		case r.rtl:
+			lv = 1
+		case l.rtl:
+			lv = 2
+		case 0 < i && i+1 < len(runs) && runs[i-1].rtl && runs[i+1].rtl && l.numeric(r.start, r.end):
+			// numbers inside of right-to-left text keep their place
+			lv = 2
+		}
+		l.fillLevels(r.start, r.end, lv)
+	}
+}
+
+func (l *bidiLine) fillLevels(start, end int, lv uint8) {
+	for i := start; i <= end && i < len(l.levels); i++ {
+		l.levels[i] = lv
+	}
+}
+
+// numeric reports whether the runes from start to end are a number.
+func (l *bidiLine) numeric(start, end int) bool {
+	for _, r := range l.runes[start : end+1] {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.EN, bidi.AN, bidi.CS, bidi.ES, bidi.ET:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isRTL reports whether the rune with the index is right-to-left.
+func (l *bidiLine) isRTL(i int) bool {
+	return l.levels[i]%2 == 1
+}
+
+// Visual caret positions are slots between runes on the screen, from
+// zero at the left edge up to the amount of runes at the right edge.
+// A rune boundary of mixed text may be at two slots, so editors keep
+// the slot of the caret besides its offset.
+
+// slotOffset returns the byte offset of the caret at the slot.
+func (l *bidiLine) slotOffset(slot int) int {
+	n := len(l.runes)
+	switch {
+	case n == 0:
+		return 0
+	case slot < n:
+		// caret is at the left edge of the rune right of the slot
+		i := l.visual[slot]
+		if l.isRTL(i) {
+			return l.offsets[i+1]
+		}
+		return l.offsets[i]
+	default:
+		// caret is at the right edge of the rightmost rune
+		i := l.visual[n-1]
+		if l.isRTL(i) {
+			return l.offsets[i]
+		}
+		return l.offsets[i+1]
+	}
+}
+
+// offsetSlot returns the slot of the caret at the byte offset, which is
+// at the leading edge of the rune after the caret.
+func (l *bidiLine) offsetSlot(offset int) int {
+	n := len(l.runes)
+	if n == 0 {
+		return 0
+	}
+	i := l.runeIndex(offset)
+	if i < n {
+		if l.isRTL(i) {
+			return l.vis[i] + 1
+		}
+		return l.vis[i]
+	}
+	// the end of the text is at the trailing edge of the last rune
+	if l.isRTL(n - 1) {
+		return l.vis[n-1]
+	}
+	return l.vis[n-1] + 1
+}
+
+// runeIndex returns the index of the rune at the byte offset.
+func (l *bidiLine) runeIndex(offset int) int {
+	for i, o := range l.offsets {
+		if offset <= o {
+			return i
+		}
+	}
+	return len(l.runes)
+}
+
+// visualRune returns the rune with the index as it is drawn: brackets
+// of right-to-left runs are mirrored.
+func (l *bidiLine) visualRune(i int) rune {
+	r := l.runes[i]
+	if p, _ := bidi.LookupRune(r); p.IsBracket() && l.isRTL(i) {
+		// reversal replaces brackets by their counterparts
+		r, _ = utf8.DecodeRuneInString(bidi.ReverseString(string(r)))
+	}
+	return r
+}
+
+// String returns the runes in visual order.
+func (l *bidiLine) String() string {
+	b := make([]byte, 0, l.offsets[len(l.runes)])
+	for _, i := range l.visual {
+		b = utf8.AppendRune(b, l.visualRune(i))
+	}
+	return string(b)
+}
+
+// mixed reports whether the single line string may be reordered by the
+// bidi mode: right-to-left lines or right-to-left characters.
+func (m BidiMode) mixed(s string) bool {
+	switch m {
+	case BidiOff:
+		return false
+	case BidiRTL:
+		return true
+	}
+	for _, r := range s {
+		if r < 0x590 {
+			// right-to-left scripts start with Hebrew
+			continue
+		}
+		switch p, _ := bidi.LookupRune(r); p.Class() {
+		case bidi.R, bidi.AL, bidi.AN, bidi.RLE, bidi.RLO, bidi.RLI:
+			return true
+		}
+	}
+	return false
+}
+
+// visual returns the single line string reordered for drawing by the
+// bidi mode of the options and logical indexes of runes of the result, or nil indexes if the
+// string is drawn as it is.
+func (f *Font) visual(str string, o drawOptions) (string, []int) {
+	if !o.bidi.mixed(str) && !o.rtl {
+		return str, nil
+	}
+	l := orderLine(str, o.bidi == BidiRTL || o.rtl, o.bidi == BidiAuto)
+	return l.String(), l.visual
+}
+
+// display returns the single line string as it is drawn, with Arabic
+// letters joined and reordered by Bidi, and logical indexes of runes of
+// the result in the string, or nil indexes if it is drawn as it is.
+func (f *Font) display(str string, o drawOptions) (string, []int) {
+	joined, index := f.join(str)
+	vis, order := f.visual(joined, o)
+	switch {
+	case order == nil:
+		return joined, index
+	case index == nil:
+		return vis, order
+	}
+	for i, j := range order {
+		order[i] = index[j]
+	}
+	return vis, order
+}
+
+// eachDisplay calls fn for glyphs of the single line string as it is
+// drawn, see display, as place, with indexes and offsets of runes of the
+// string.
+func (f *Font) eachDisplay(str string, o drawOptions, fn func(g PlacedGlyph) bool) (int32, bool) {
+	vis, index := f.display(str, o)
+	if index == nil {
+		return f.eachGlyph(str, fn)
+	}
+	offsets := f.Arena.Ints(len(index))[:0]
+	for i := range str {
+		offsets = append(offsets, i)
+	}
+	return f.eachGlyph(vis, func(g PlacedGlyph) bool {
+		g.Index = index[g.Index]
+		g.Offset = offsets[g.Index]
+		return fn(g)
+	})
+}
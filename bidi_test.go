@@ -0,0 +1,41 @@
+package glsymbol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFontBidi(t *testing.T) {
+	f := testFont()
+	for _, tc := range []struct {
+		mode       BidiMode
+		in, visual string
+	}{
+		{BidiOff, "ab אב", "ab אב"},
+		{BidiAuto, "ab אב", "ab בא"},
+		{BidiAuto, "אב (ג)", "(ג) בא"},
+		{BidiAuto, "price 50 ₪ שלום", "price 50 ₪ םולש"},
+		{BidiLTR, "אב ab", "בא ab"},
+		{BidiRTL, "ab", "ab"},
+		{BidiRTL, "ab אב", "בא ab"},
+	} {
+		f.Bidi = tc.mode
+		if s, _ := f.visual(tc.in, f.options()); s != tc.visual {
+			t.Errorf("%d %q: visual %q, expect %q", tc.mode, tc.in, s, tc.visual)
+		}
+	}
+
+	f.Bidi = BidiAuto
+	var index, offsets []int
+	f.EachGlyph("ab אב", func(g PlacedGlyph) bool {
+		index = append(index, g.Index)
+		offsets = append(offsets, g.Offset)
+		return true
+	})
+	if !reflect.DeepEqual(index, []int{0, 1, 2, 4, 3}) || !reflect.DeepEqual(offsets, []int{0, 1, 2, 5, 3}) {
+		t.Errorf("indexes %v, offsets %v", index, offsets)
+	}
+	if w := f.advanceSize("ab אב"); w != 8+8+4+2*f.unknownGlyph().step() {
+		t.Errorf("width %d", w)
+	}
+}
@@ -0,0 +1,52 @@
+package glsymbol
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// referenceBitmap is the conversion of glyphs by the color model,
+// pixel by pixel.
+func referenceBitmap(img *image.RGBA, glyph *Glyph) (dst []uint8) {
+	for y := glyph.Height; 0 <= y; y-- {
+		var u uint8
+		for x := 0; x < int(glyph.Width); x++ {
+			c := img.At(x+int(glyph.X), int(y)+int(glyph.Y))
+			h := x % 8
+			if r, _, _, _ := c.RGBA(); 40000 < r {
+				u |= 1 << (7 - h)
+			}
+			if h == 7 || x == int(glyph.Width)-1 {
+				dst = append(dst, u)
+				u = 0
+			}
+		}
+	}
+	return
+}
+
+func TestGlyphBitmap(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rnd.Intn(256))
+	}
+	img.Set(0, 0, color.RGBA{R: 155})
+	img.Set(1, 0, color.RGBA{R: 156})
+	for _, g := range []Glyph{
+		{X: 0, Y: 0, Width: 2, Height: 1},
+		{X: 3, Y: 2, Width: 8, Height: 16},
+		{X: 5, Y: 1, Width: 13, Height: 9},
+		{X: 30, Y: 20, Width: 17, Height: 12}, // out of the sheet
+		{X: 0, Y: 0, Width: 0, Height: 16},
+	} {
+		got := glyphBitmap([]uint8{0xaa}, img, &g)
+		want := append([]uint8{0xaa}, referenceBitmap(img, &g)...)
+		if !bytes.Equal(got, want) {
+			t.Errorf("glyph %+v:\n%x\n%x", g, got, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+)
+
+// rasterGlyph is a glyph of a bitmap font file. Rows of the bitmap hold
+// pixels as bits, the most significant bit first.
+type rasterGlyph struct {
+	width, height int // size of the bitmap
+	top           int // distance from the top of the cell to the bitmap
+	left          int // left bearing
+	advance       int
+	stride        int // bytes per row
+	bits          []byte
+}
+
+// rasterSheet draws glyphs of runes from low to high into a sprite sheet
+// with cells of the given height. The index function returns the index
+// of the glyph of the rune, or -1 if there is no glyph. A glyph shared
+// by many runes, like the default character, is drawn once.
+func rasterSheet(low, high rune, glyphs []rasterGlyph, index func(r rune) int, height, baseline int) (*image.RGBA, *FontConfig) {
+	fc := &FontConfig{Low: low, High: high, Baseline: int32(baseline)}
+	fc.Glyphs = make(Charset, high-low+1)
+
+	// cells are placed in rows of a sheet up to 1024 pixels wide,
+	// every cell has a blank row above it, see loadFont
+	const sheetWidth = 1024
+	var x, y, width int
+	placed := make(map[int]image.Point)
+	var order []int
+	for i := range fc.Glyphs {
+		idx := index(low + rune(i))
+		if idx < 0 {
+			continue
+		}
+		g := glyphs[idx]
+		at, ok := placed[idx]
+		if !ok {
+			if sheetWidth < x+g.width && 0 < x {
+				x = 0
+				y += height + 1
+			}
+			at = image.Pt(x, y)
+			placed[idx] = at
+			order = append(order, idx)
+			x += g.width
+			if width < x {
+				width = x
+			}
+		}
+		fc.Glyphs[i] = Glyph{
+			X: int32(at.X), Y: int32(at.Y),
+			Width: int32(g.width), Height: int32(height),
+			Advance:      int32(g.advance),
+			LeftBearing:  int32(g.left),
+			RightBearing: int32(g.advance - g.left - g.width),
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, y+height+1))
+	for _, idx := range order {
+		g, at := glyphs[idx], placed[idx]
+		top := at.Y + 1 + g.top
+		for py := 0; py < g.height; py++ {
+			row := g.bits[py*g.stride:]
+			for px := 0; px < g.width; px++ {
+				if row[px/8]&(0x80>>(px%8)) != 0 {
+					img.Set(at.X+px, top+py, color.White)
+				}
+			}
+		}
+	}
+	return img, fc
+}
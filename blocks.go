@@ -0,0 +1,61 @@
+package glsymbol
+
+import "sort"
+
+// A RuneSet is a set of runes given by ranges in any order, which may
+// overlap. See Union.
+type RuneSet []RuneRange
+
+// Presets of rune sets by Unicode blocks. Control characters are not
+// included.
+var (
+	ASCII          = RuneSet{{0x20, 0x7e}}
+	Latin1         = RuneSet{{0x20, 0x7e}, {0xa0, 0xff}} // ASCII and Latin-1 Supplement
+	LatinExtendedA = RuneSet{{0x100, 0x17f}}
+	Greek          = RuneSet{{0x370, 0x3ff}}
+	Cyrillic       = RuneSet{{0x400, 0x4ff}}
+	Punctuation    = RuneSet{{0x2000, 0x206f}} // General Punctuation
+	Currency       = RuneSet{{0x20a0, 0x20cf}}
+	Arrows         = RuneSet{{0x2190, 0x21ff}}
+	MathOperators  = RuneSet{{0x2200, 0x22ff}}
+	BoxDrawing     = RuneSet{{0x2500, 0x257f}}
+	BlockElements  = RuneSet{{0x2580, 0x259f}}
+	Geometric      = RuneSet{{0x25a0, 0x25ff}} // Geometric Shapes
+
+	// CJKCommon holds CJK punctuation, kana and fullwidth forms.
+	// Ideographs are too many for a single sprite sheet of common
+	// sizes, add the ones in use by ranges.
+	CJKCommon = RuneSet{{0x3000, 0x30ff}, {0xff00, 0xffef}}
+)
+
+// Union returns ascending, not overlapping ranges of runes of all sets,
+// as LoadTruetypeRanges expects. Adjacent ranges are merged.
+func Union(sets ...RuneSet) []RuneRange {
+	var all []RuneRange
+	for _, s := range sets {
+		all = append(all, s...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Low < all[j].Low })
+	var out []RuneRange
+	for _, rg := range all {
+		if rg.High < rg.Low {
+			continue
+		}
+		if n := len(out); 0 < n && rg.Low <= out[n-1].High+1 {
+			if out[n-1].High < rg.High {
+				out[n-1].High = rg.High
+			}
+			continue
+		}
+		out = append(out, rg)
+	}
+	return out
+}
+
+// LoadTruetypeSets loads a truetype or OpenType font from the data
+// as LoadTruetypeRanges with glyphs of runes of the sets, for example
+//
+//	f, err := LoadTruetypeSets(data, 14, Latin1, Cyrillic, Arrows)
+func LoadTruetypeSets(data []byte, scale int32, sets ...RuneSet) (*Font, error) {
+	return LoadTruetypeRanges(data, scale, Union(sets...)...)
+}
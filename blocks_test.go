@@ -0,0 +1,28 @@
+package glsymbol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	for _, tc := range []struct {
+		sets   []RuneSet
+		expect []RuneRange
+	}{
+		{nil, nil},
+		{[]RuneSet{Cyrillic, ASCII}, []RuneRange{{0x20, 0x7e}, {0x400, 0x4ff}}},
+		{[]RuneSet{Latin1, ASCII, LatinExtendedA}, []RuneRange{{0x20, 0x7e}, {0xa0, 0x17f}}},
+		{[]RuneSet{{{'a', 'c'}, {'b', 'z'}, {'x', 'y'}, {'2', '1'}}}, []RuneRange{{'a', 'z'}}},
+	} {
+		out := Union(tc.sets...)
+		if !reflect.DeepEqual(out, tc.expect) {
+			t.Errorf("%v: union %v, expect %v", tc.sets, out, tc.expect)
+		}
+		if len(out) != 0 {
+			if err := validateRanges(out); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
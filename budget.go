@@ -0,0 +1,78 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// BudgetError reports that memory of sprite sheets would exceed
+// the budget of a font or a face. Values are in bytes.
+type BudgetError struct {
+	Budget int64
+	Need   int64
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("glsymbol: sprite sheets need %d bytes, budget is %d bytes", e.Need, e.Budget)
+}
+
+// sheetBytes returns memory of a sprite sheet of the size.
+func sheetBytes(size image.Point) int64 {
+	return int64(size.X) * int64(size.Y) * 4
+}
+
+// overBudget reports whether a sheet of the size exceeds the budget.
+func (f *Font) overBudget(size image.Point) bool {
+	return 0 < f.Budget && f.Budget < sheetBytes(size)
+}
+
+// evictable marks the added glyph of the rune as one which may be
+// evicted, because it is rasterized again on demand.
+func (f *Font) evictable(r rune) {
+	if f.lru == nil {
+		f.lru = make(map[rune]uint64)
+	}
+	f.tick++
+	f.lru[r] = f.tick
+}
+
+// touch records the use of the evictable glyph of the rune.
+func (f *Font) touch(r rune) {
+	if _, ok := f.lru[r]; ok {
+		f.tick++
+		f.lru[r] = f.tick
+	}
+}
+
+// evict removes the least recently used evictable glyph with a cell for
+// a glyph of the width and the height, clears the cell and returns its
+// position, or false if there is no such glyph.
+func (f *Font) evict(w, h int) (image.Point, bool) {
+	var (
+		victim rune
+		oldest uint64
+		found  bool
+	)
+	for r, t := range f.lru {
+		g := f.added[r]
+		if g == nil || int(g.Height) != h || int(g.Width) < w {
+			continue
+		}
+		if !found || t < oldest || t == oldest && r < victim {
+			victim, oldest, found = r, t, true
+		}
+	}
+	if !found {
+		return image.Point{}, false
+	}
+	g := f.added[victim]
+	delete(f.added, victim)
+	delete(f.lru, victim)
+	f.identity = 0
+	warn("glsymbol: glyph is evicted", "rune", victim)
+
+	cell := image.Rect(int(g.X), int(g.Y)+1, int(g.X+g.Width), int(g.Y+g.Height)+1)
+	draw.Draw(f.img, cell, image.Transparent, image.Point{}, draw.Src)
+	return image.Pt(int(g.X), int(g.Y)), true
+}
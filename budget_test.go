@@ -0,0 +1,72 @@
+package glsymbol
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestFontBudget(t *testing.T) {
+	f := testSheetFont()
+	if f.img.Bounds() != image.Rect(0, 0, 752, 18) {
+		t.Fatalf("sheet %v", f.img.Bounds())
+	}
+	// a single row of added glyphs, two wide glyphs fit it
+	f.Budget = sheetBytes(image.Pt(752, 18+16+1))
+	f.Fallback = func(r rune) image.Image {
+		img := image.NewAlpha(image.Rect(0, 0, 300, 10))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		return img
+	}
+	a, b := f.glyph('А'), f.glyph('Б')
+	if a.X != 0 || b.X != 300 || a.Y != 18 || b.Y != 18 {
+		t.Fatalf("glyphs at %d,%d and %d,%d", a.X, a.Y, b.X, b.Y)
+	}
+	f.glyph('А')
+	c := f.glyph('В')
+	if c.X != 300 || c.Y != 18 || f.img.Bounds().Dy() != 35 {
+		t.Errorf("glyph at %d,%d in sheet %v", c.X, c.Y, f.img.Bounds())
+	}
+	if _, ok := f.added['Б']; ok {
+		t.Errorf("least recently used glyph is not evicted")
+	}
+	if f.added['А'] != a {
+		t.Errorf("recently used glyph is evicted")
+	}
+
+	// glyphs added by AddGlyph are not evicted
+	err := f.AddGlyph('\ue000', image.NewAlpha(image.Rect(0, 0, 400, 10)), GlyphMetrics{Advance: 400})
+	var be *BudgetError
+	if !errors.As(err, &be) || be.Budget != f.Budget {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Budget = 0
+	if err := f.AddGlyph('\ue000', image.NewAlpha(image.Rect(0, 0, 4, 10)), GlyphMetrics{Advance: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.lru['\ue000']; ok {
+		t.Errorf("glyph of AddGlyph is evictable")
+	}
+}
+
+func TestFaceBudget(t *testing.T) {
+	small, large := testSheetFont(), testSheetFont()
+	n := int64(len(small.img.Pix))
+	fa := &Face{
+		Budget: 2 * n,
+		fonts:  map[int32]*Font{10: small, 20: large},
+		used:   map[int32]uint64{10: 2, 20: 1},
+	}
+	if err := fa.fit(n); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fa.fonts[20]; ok || large.Config != nil || small.Config == nil {
+		t.Errorf("least recently requested font is not released")
+	}
+	var be *BudgetError
+	if err := fa.fit(3 * n); !errors.As(err, &be) || be.Need != 3*n {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
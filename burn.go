@@ -0,0 +1,150 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// CaptureFrame reads the rectangle of the framebuffer, in window
+// coordinates from the bottom left corner, into a new image, for
+// example to save an annotated screenshot. Rows are flipped, so the
+// image is upright.
+func CaptureFrame(x, y, width, height int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width <= 0 || height <= 0 {
+		return img, nil
+	}
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	if err := checkGLError("CaptureFrame"); err != nil {
+		return nil, err
+	}
+	flipRows(img)
+	return img, nil
+}
+
+// flipRows turns the image upside down.
+func flipRows(img *image.RGBA) {
+	h := img.Bounds().Dy()
+	row := make([]uint8, img.Stride)
+	for a, b := 0, h-1; a < b; a, b = a+1, b-1 {
+		ra := img.Pix[a*img.Stride : (a+1)*img.Stride]
+		rb := img.Pix[b*img.Stride : (b+1)*img.Stride]
+		copy(row, ra)
+		copy(ra, rb)
+		copy(rb, row)
+	}
+}
+
+// An ImageFrame is a Frame which may also be composited into images on
+// the CPU, see Font.BurnTextBox.
+type ImageFrame interface {
+	Frame
+	DrawImage(dst draw.Image, r image.Rectangle)
+}
+
+// DrawImage implements ImageFrame. The shape is anti-aliased.
+func (fr RoundedFrame) DrawImage(dst draw.Image, r image.Rectangle) {
+	w := fr.BorderWidth
+	if fr.Border == nil {
+		w = 0
+	}
+	inner := func(px, py float64) float64 {
+		return roundedCoverage(px-float64(w), py-float64(w),
+			float64(r.Dx())-2*float64(w), float64(r.Dy())-2*float64(w), float64(fr.Radius-w))
+	}
+	outer := func(px, py float64) float64 {
+		return roundedCoverage(px, py, float64(r.Dx()), float64(r.Dy()), float64(fr.Radius))
+	}
+	for py := 0; py < r.Dy(); py++ {
+		for px := 0; px < r.Dx(); px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			in := inner(cx, cy)
+			if fr.Fill != nil && 0 < in {
+				blendPixel(dst, r.Min.X+px, r.Min.Y+py, fr.Fill, in)
+			}
+			if 0 < w {
+				if ring := outer(cx, cy) - in; 0 < ring {
+					blendPixel(dst, r.Min.X+px, r.Min.Y+py, fr.Border, ring)
+				}
+			}
+		}
+	}
+}
+
+// roundedCoverage returns the coverage of the point by a rectangle with
+// rounded corners placed at zero, from 0 outside to 1 inside.
+func roundedCoverage(x, y, width, height, radius float64) float64 {
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	if m := math.Min(width, height) / 2; m < radius {
+		radius = m
+	}
+	if radius < 0 {
+		radius = 0
+	}
+	// signed distance to the edge, negative inside
+	hw, hh := width/2, height/2
+	dx := math.Abs(x-hw) - (hw - radius)
+	dy := math.Abs(y-hh) - (hh - radius)
+	d := math.Hypot(math.Max(dx, 0), math.Max(dy, 0)) + math.Min(math.Max(dx, dy), 0) - radius
+	return math.Max(0, math.Min(1, 0.5-d))
+}
+
+// blendPixel draws the color with the coverage over the pixel.
+func blendPixel(dst draw.Image, x, y int, c color.Color, coverage float64) {
+	sr, sg, sb, sa := c.RGBA()
+	k := coverage
+	sr, sg, sb, sa = uint32(float64(sr)*k), uint32(float64(sg)*k), uint32(float64(sb)*k), uint32(float64(sa)*k)
+	dr, dg, db, da := dst.At(x, y).RGBA()
+	inv := 0xffff - sa
+	dst.Set(x, y, color.RGBA64{
+		R: uint16(sr + dr*inv/0xffff),
+		G: uint16(sg + dg*inv/0xffff),
+		B: uint16(sb + db*inv/0xffff),
+		A: uint16(sa + da*inv/0xffff),
+	})
+}
+
+// BurnTextBox composites a text box into the image on the CPU as
+// DrawTextBox draws it on the screen: the frame sized by TextBoxSize
+// with the top left corner at x, y and the text inside of it by the
+// color. It is used to burn labels into captured frames, so saved
+// screenshots look as the screen. The frame must be nil or an
+// ImageFrame. It returns the box in the image.
+func (f *Font) BurnTextBox(dst draw.Image, x, y int, text string, frame Frame, padding int, c color.Color) (image.Rectangle, error) {
+	w, h := f.TextBoxSize(text, frame, float32(padding))
+	box := image.Rect(x, y, x+int(math.Ceil(float64(w))), y+int(math.Ceil(float64(h))))
+	var l, t float32
+	if frame != nil {
+		fi, ok := frame.(ImageFrame)
+		if !ok {
+			return box, fmt.Errorf("glsymbol: frame %T cannot be drawn into images", frame)
+		}
+		fi.DrawImage(dst, box)
+		l, t, _, _ = frame.Insets()
+	}
+	f.DrawImageLines(dst, x+int(l)+padding, y+int(t)+padding, text, c)
+	return box, nil
+}
+
+// BurnTextBox composites the text box into the image as
+// Font.BurnTextBox with the font of the role, the frame, the padding
+// and the text color of the theme, the same as Theme.DrawTextBox.
+func (t *Theme) BurnTextBox(dst draw.Image, role string, x, y int, text string) (image.Rectangle, error) {
+	f, err := t.Font(role)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	c := t.color(ColorText)
+	if c == nil {
+		c = color.White
+	}
+	return f.BurnTextBox(dst, x, y, text, t.TextFrame(), int(t.Padding), c)
+}
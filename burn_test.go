@@ -0,0 +1,69 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRoundedCoverage(t *testing.T) {
+	for _, tc := range []struct {
+		x, y, cov float64
+	}{
+		{5, 5, 1},
+		{0.5, 5, 1},   // left edge
+		{0, 5, 0.5},   // on the edge
+		{-1, 5, 0},    // outside
+		{0.5, 0.5, 0}, // rounded corner
+	} {
+		if c := roundedCoverage(tc.x, tc.y, 10, 10, 4); c != tc.cov {
+			t.Errorf("coverage at %v,%v is %v, expect %v", tc.x, tc.y, c, tc.cov)
+		}
+	}
+}
+
+func TestBurnTextBox(t *testing.T) {
+	f := testSheetFont()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	frame := RoundedFrame{
+		Fill:        color.RGBA{0, 0, 0xff, 0xff},
+		Border:      color.RGBA{0xff, 0, 0, 0xff},
+		BorderWidth: 1,
+	}
+	box, err := f.BurnTextBox(img, 2, 2, "A", frame, 2, white)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if box != image.Rect(2, 2, 16, 24) {
+		t.Errorf("box %v", box)
+	}
+	for _, tc := range []struct {
+		x, y int
+		c    color.RGBA
+	}{
+		{2, 2, frame.Border.(color.RGBA)},
+		{3, 3, frame.Fill.(color.RGBA)},
+		{5, 5, white},
+		{1, 1, color.RGBA{}},
+	} {
+		if c := img.RGBAAt(tc.x, tc.y); c != tc.c {
+			t.Errorf("pixel %d,%d is %v, expect %v", tc.x, tc.y, c, tc.c)
+		}
+	}
+
+	if _, err := f.BurnTextBox(img, 0, 0, "A", &NinePatch{}, 0, white); err == nil {
+		t.Errorf("nine-patch is burned")
+	}
+}
+
+func TestFlipRows(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 3))
+	for y := 0; y < 3; y++ {
+		img.Pix[y*img.Stride] = uint8(y)
+	}
+	flipRows(img)
+	if img.Pix[0] != 2 || img.Pix[img.Stride] != 1 || img.Pix[2*img.Stride] != 0 {
+		t.Errorf("rows %v", img.Pix)
+	}
+}
@@ -0,0 +1,145 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+)
+
+// ClipboardFormat is a format of text copied to the clipboard.
+type ClipboardFormat int
+
+// Clipboard formats.
+const (
+	ClipboardPlain ClipboardFormat = iota // text without styles
+	ClipboardHTML                         // span elements with inline styles
+	ClipboardANSI                         // SGR escape sequences of terminals
+)
+
+// TextStyle is the style of a range of text kept by clipboard formats.
+type TextStyle struct {
+	Color     color.Color // nil for the default color
+	Bold      bool
+	Italic    bool
+	Underline bool
+}
+
+// StyleRange is the style of the bytes from Start up to End of a text.
+type StyleRange struct {
+	Start, End int
+	Style      TextStyle
+}
+
+// Export returns the bytes of the text from start up to end, for example
+// a selection, in the format for copying to the clipboard. Ranges of the
+// styles are byte offsets into the text as start and end, the last range
+// covering a byte wins. Bytes out of the ranges have the zero style.
+func (t *Text) Export(start, end int, styles []StyleRange, format ClipboardFormat) (string, error) {
+	if err := t.checkOffset(start); err != nil {
+		return "", err
+	}
+	if err := t.checkOffset(end); err != nil {
+		return "", err
+	}
+	if end < start {
+		return "", fmt.Errorf("glsymbol: invalid range %d..%d", start, end)
+	}
+	s := t.String()[start:end]
+	if format == ClipboardPlain {
+		return s, nil
+	}
+
+	// the selection is split at every bound of a range
+	bounds := []int{0, len(s)}
+	for _, r := range styles {
+		for _, b := range []int{r.Start - start, r.End - start} {
+			if 0 < b && b < len(s) {
+				bounds = append(bounds, b)
+			}
+		}
+	}
+	sort.Ints(bounds)
+	var b strings.Builder
+	if format == ClipboardHTML {
+		b.WriteString("<pre>")
+	}
+	for i := 0; i+1 < len(bounds); i++ {
+		from, to := bounds[i], bounds[i+1]
+		if from == to {
+			continue
+		}
+		var style TextStyle
+		for _, r := range styles {
+			if r.Start <= start+from && start+to <= r.End {
+				style = r.Style
+			}
+		}
+		switch format {
+		case ClipboardHTML:
+			writeHTML(&b, s[from:to], style)
+		case ClipboardANSI:
+			writeANSI(&b, s[from:to], style)
+		}
+	}
+	if format == ClipboardHTML {
+		b.WriteString("</pre>")
+	}
+	return b.String(), nil
+}
+
+// writeHTML writes the escaped text in a span element with the style,
+// or without an element for the zero style.
+func writeHTML(b *strings.Builder, s string, style TextStyle) {
+	var css []string
+	if style.Color != nil {
+		c := color.RGBAModel.Convert(style.Color).(color.RGBA)
+		css = append(css, fmt.Sprintf("color:#%02x%02x%02x", c.R, c.G, c.B))
+	}
+	if style.Bold {
+		css = append(css, "font-weight:bold")
+	}
+	if style.Italic {
+		css = append(css, "font-style:italic")
+	}
+	if style.Underline {
+		css = append(css, "text-decoration:underline")
+	}
+	if len(css) == 0 {
+		b.WriteString(xmlEscape(s))
+		return
+	}
+	fmt.Fprintf(b, `<span style="%s">%s</span>`, strings.Join(css, ";"), xmlEscape(s))
+}
+
+// writeANSI writes the text between SGR sequences setting the style in
+// 24-bit color and resetting it, or without sequences for the zero style.
+// Styles end at line breaks, so every line stands alone.
+func writeANSI(b *strings.Builder, s string, style TextStyle) {
+	var sgr []string
+	if style.Bold {
+		sgr = append(sgr, "1")
+	}
+	if style.Italic {
+		sgr = append(sgr, "3")
+	}
+	if style.Underline {
+		sgr = append(sgr, "4")
+	}
+	if style.Color != nil {
+		c := color.RGBAModel.Convert(style.Color).(color.RGBA)
+		sgr = append(sgr, fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B))
+	}
+	if len(sgr) == 0 {
+		b.WriteString(s)
+		return
+	}
+	for i, line := range strings.Split(s, "\n") {
+		if 0 < i {
+			b.WriteByte('\n')
+		}
+		if line != "" {
+			fmt.Fprintf(b, "\x1b[%sm%s\x1b[0m", strings.Join(sgr, ";"), line)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package glsymbol
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestTextExport(t *testing.T) {
+	text := NewText(testFont(), "log: <error> x\nnext")
+	styles := []StyleRange{
+		{Start: 5, End: 12, Style: TextStyle{Color: color.RGBA{R: 0xff, A: 0xff}, Bold: true}},
+		{Start: 13, End: 19, Style: TextStyle{Italic: true}},
+	}
+	tcs := []struct {
+		format ClipboardFormat
+		expect string
+	}{
+		{ClipboardPlain, "<error> x\nne"},
+		{ClipboardHTML, `<pre><span style="color:#ff0000;font-weight:bold">&lt;error&gt;</span> ` +
+			`<span style="font-style:italic">x` + "\n" + `ne</span></pre>`},
+		{ClipboardANSI, "\x1b[1;38;2;255;0;0m<error>\x1b[0m \x1b[3mx\x1b[0m\n\x1b[3mne\x1b[0m"},
+	}
+	for _, tc := range tcs {
+		s, err := text.Export(5, 17, styles, tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tc.expect {
+			t.Errorf("format %d: %q, expect %q", tc.format, s, tc.expect)
+		}
+	}
+	if _, err := text.Export(6, 5, nil, ClipboardPlain); err == nil {
+		t.Errorf("invalid range is exported")
+	}
+}
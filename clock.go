@@ -0,0 +1,49 @@
+package glsymbol
+
+import "time"
+
+// A Clock tells the time of animations, such as blinking of the caret
+// and fading of notifications. Applications with pause, slow motion or
+// deterministic replays give animated helpers a ManualClock advanced by
+// their own frame time.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the wall clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the wall clock, used by helpers without a clock.
+var SystemClock Clock = systemClock{}
+
+// A ManualClock is a clock which moves only by Advance.
+// The zero value starts at the zero time.
+type ManualClock struct {
+	t time.Time
+}
+
+// NewManualClock returns a clock starting at the time.
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{t: t}
+}
+
+// Now returns the time of the clock.
+func (c *ManualClock) Now() time.Time {
+	return c.t
+}
+
+// Advance moves the clock by the delta time of a frame, which may be
+// scaled for slow motion or zero while paused.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// now returns the time of the clock, or of SystemClock if c is nil.
+func now(c Clock) time.Time {
+	if c == nil || c == SystemClock {
+		return wallClock()
+	}
+	return c.Now()
+}
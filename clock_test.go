@@ -0,0 +1,53 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(start)
+	c.Advance(time.Second)
+	c.Advance(0) // paused
+	if d := c.Now().Sub(start); d != time.Second {
+		t.Errorf("clock moved by %v", d)
+	}
+}
+
+func TestCaretBlink(t *testing.T) {
+	c := NewManualClock(time.Time{})
+	in := &Input{Font: testFont(), Blink: time.Second, Clock: c}
+	in.SetText("abc")
+	var shown []bool
+	for i := 0; i < 4; i++ {
+		shown = append(shown, in.CaretVisible())
+		c.Advance(300 * time.Millisecond)
+	}
+	expect := []bool{true, true, false, false}
+	for i := range expect {
+		if shown[i] != expect[i] {
+			t.Fatalf("caret shown %v, expect %v", shown, expect)
+		}
+	}
+	in.MoveLogical(-1)
+	if !in.CaretVisible() {
+		t.Errorf("caret is hidden after a move")
+	}
+}
+
+func TestNotificationsClock(t *testing.T) {
+	c := NewManualClock(time.Time{})
+	n := Notifications{Font: testFont(), Clock: c}
+	n.PushTTL("message", SeverityInfo, time.Second)
+	c.Advance(999 * time.Millisecond)
+	n.expire(c.Now())
+	if n.Len() != 1 {
+		t.Errorf("message expired early")
+	}
+	c.Advance(time.Millisecond)
+	n.expire(c.Now())
+	if n.Len() != 0 {
+		t.Errorf("message is not expired")
+	}
+}
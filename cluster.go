@@ -0,0 +1,128 @@
+package glsymbol
+
+import (
+	"math"
+	"strconv"
+)
+
+// A LabelCluster is a group of labels whose anchors are close on the
+// screen.
+type LabelCluster struct {
+	X, Y    float32 // mean position of anchors
+	Members []int   // indexes of anchors given to Clusterer.Update
+	Box     Rect    // box of the label or the badge after Clusterer.Add
+}
+
+// A Clusterer aggregates labels whose anchors collapse into a small
+// region of the screen into a single badge with the amount of labels,
+// for example "×12". A click at a badge calls Expand, so the caller may
+// zoom in or list the members.
+type Clusterer struct {
+	Radius float32 // anchors closer than Radius form a cluster
+
+	// Badge returns the text of a cluster of n labels, "×n" if nil.
+	Badge func(n int) string
+
+	// Expand is called by Click with the cluster under the point.
+	Expand func(c *LabelCluster)
+
+	clusters []LabelCluster
+}
+
+// Update groups anchors given in the coordinates of drawing. Every
+// anchor joins the first cluster whose first anchor is closer than
+// Radius, so the result depends on the order of anchors only for
+// anchors between clusters.
+func (c *Clusterer) Update(anchors [][2]float32) {
+	c.clusters = c.clusters[:0]
+	cell := c.Radius
+	if cell <= 0 {
+		cell = 1
+	}
+	type key struct{ x, y int }
+	grid := make(map[key][]int) // clusters by the cell of the first anchor
+	cellOf := func(x, y float32) key {
+		return key{int(math.Floor(float64(x / cell))), int(math.Floor(float64(y / cell)))}
+	}
+	first := make([][2]float32, 0, len(anchors))
+	for i, p := range anchors {
+		k := cellOf(p[0], p[1])
+		found := -1
+		best := c.Radius * c.Radius
+	search:
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for _, ci := range grid[key{k.x + dx, k.y + dy}] {
+					ddx, ddy := first[ci][0]-p[0], first[ci][1]-p[1]
+					if d := ddx*ddx + ddy*ddy; d < best {
+						found, best = ci, d
+						if d == 0 {
+							break search
+						}
+					}
+				}
+			}
+		}
+		if found < 0 {
+			found = len(c.clusters)
+			c.clusters = append(c.clusters, LabelCluster{})
+			first = append(first, p)
+			grid[k] = append(grid[k], found)
+		}
+		cl := &c.clusters[found]
+		cl.X += p[0]
+		cl.Y += p[1]
+		cl.Members = append(cl.Members, i)
+	}
+	for i := range c.clusters {
+		cl := &c.clusters[i]
+		n := float32(len(cl.Members))
+		cl.X /= n
+		cl.Y /= n
+	}
+}
+
+// Clusters returns clusters of the last Update, including clusters of
+// a single label.
+func (c *Clusterer) Clusters() []LabelCluster {
+	return c.clusters
+}
+
+// badge returns the text of a cluster of n labels.
+func (c *Clusterer) badge(n int) string {
+	if c.Badge != nil {
+		return c.Badge(n)
+	}
+	return "×" + strconv.Itoa(n)
+}
+
+// Add adds labels of clusters to the batch, centered at clusters: the
+// text returned by label for a single anchor and the badge for a group.
+// Boxes of labels are stored in clusters for Click.
+func (c *Clusterer) Add(b *Batch, layer int, f *Font, frame Frame, padding float32, label func(i int) string) {
+	for i := range c.clusters {
+		cl := &c.clusters[i]
+		text := c.badge(len(cl.Members))
+		if len(cl.Members) == 1 {
+			text = label(cl.Members[0])
+		}
+		w, h := f.TextBoxSize(text, frame, padding)
+		cl.Box = b.Label(layer, f, cl.X-w/2, above(cl.Y, h/2), text, frame, padding, nil)
+	}
+}
+
+// Click calls Expand for the group under the point and reports whether
+// there is one. Labels of a single anchor are not expanded.
+func (c *Clusterer) Click(x, y float32) bool {
+	for i := len(c.clusters) - 1; 0 <= i; i-- {
+		cl := &c.clusters[i]
+		if len(cl.Members) < 2 || !cl.Box.Contains(x, y) {
+			continue
+		}
+		if c.Expand != nil {
+			c.Expand(cl)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package glsymbol
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestClusterer(t *testing.T) {
+	c := Clusterer{
+		Radius: 10,
+		Badge:  func(n int) string { return "x" + strconv.Itoa(n) },
+	}
+	c.Update([][2]float32{{0, 0}, {100, 100}, {4, 2}, {-4, 4}, {30, 0}})
+	cl := c.Clusters()
+	if len(cl) != 3 {
+		t.Fatalf("clusters %+v", cl)
+	}
+	if len(cl[0].Members) != 3 || cl[0].X != 0 || cl[0].Y != 2 {
+		t.Errorf("first cluster %+v", cl[0])
+	}
+	if len(cl[1].Members) != 1 || cl[1].Members[0] != 1 || len(cl[2].Members) != 1 {
+		t.Errorf("single clusters %+v", cl[1:])
+	}
+
+	f := testFont()
+	var b Batch
+	c.Add(&b, LayerLabel, f, nil, 0, func(i int) string { return "A" })
+	if b.Len() != 3 {
+		t.Errorf("batch length %d", b.Len())
+	}
+	for _, it := range b.items {
+		if it.str != "x3" && it.str != "A" {
+			t.Errorf("label %q", it.str)
+		}
+	}
+
+	var expanded *LabelCluster
+	c.Expand = func(cl *LabelCluster) { expanded = cl }
+	if !c.Click(cl[0].X, cl[0].Y) || expanded != &cl[0] {
+		t.Errorf("badge is not expanded")
+	}
+	if c.Click(100, 100) {
+		t.Errorf("single label is expanded")
+	}
+}
@@ -0,0 +1,307 @@
+// Command glsymbol-edit is a small editor of bitmap font metadata.
+//
+// It loads a sprite sheet and the JSON font configuration, shows the
+// sheet with the selected glyph rectangle together with a sample string,
+// and writes the configuration back.
+//
+// Usage:
+//
+//	glsymbol-edit -sheet font.png -config font.json [-text "sample"] [-o out.json]
+//
+// Keys:
+//
+//	Tab, Shift+Tab         select next, previous glyph
+//	Arrows                 move glyph rectangle
+//	Shift+Arrows           resize glyph rectangle
+//	Minus, Equal           decrease, increase advance
+//	Ctrl+S                 write configuration
+//	Escape                 quit
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"runtime"
+
+	"github.com/Konstantin8105/glsymbol"
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func init() {
+	// GLFW event handling must run on the main OS thread.
+	runtime.LockOSThread()
+}
+
+func main() {
+	var (
+		sheet  = flag.String("sheet", "", "sprite sheet image")
+		config = flag.String("config", "", "JSON font configuration")
+		text   = flag.String("text", "The quick brown fox jumps over the lazy dog", "sample string")
+		output = flag.String("o", "", "output configuration, default is -config")
+	)
+	flag.Parse()
+	if *sheet == "" || *config == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = *config
+	}
+	if err := run(*sheet, *config, *output, *text); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// editor holds the state of an editing session.
+type editor struct {
+	img      *image.RGBA
+	config   glsymbol.FontConfig
+	selected int
+	output   string
+
+	preview *glsymbol.Font // font built from the current config
+	ui      *glsymbol.Font // font for status lines
+	status  string
+}
+
+func run(sheet, config, output, text string) error {
+	ed := editor{output: output}
+	if err := ed.load(sheet, config); err != nil {
+		return err
+	}
+
+	if err := glfw.Init(); err != nil {
+		return fmt.Errorf("failed to initialize glfw: %v", err)
+	}
+	defer glfw.Terminate()
+
+	glfw.WindowHint(glfw.Resizable, glfw.True)
+	glfw.WindowHint(glfw.ContextVersionMajor, 2)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+
+	window, err := glfw.CreateWindow(800, 600, "glsymbol-edit", nil, nil)
+	if err != nil {
+		return err
+	}
+	window.MakeContextCurrent()
+	if err = gl.Init(); err != nil {
+		return err
+	}
+	glfw.SwapInterval(1)
+
+	if ed.ui, err = glsymbol.DefaultFont(); err != nil {
+		return err
+	}
+	if err = ed.rebuild(); err != nil {
+		return err
+	}
+
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		if action == glfw.Release {
+			return
+		}
+		if key == glfw.KeyEscape {
+			w.SetShouldClose(true)
+			return
+		}
+		ed.key(key, mods)
+	})
+
+	for !window.ShouldClose() {
+		glfw.PollEvents()
+		w, h := window.GetSize()
+		glsymbol.SetupOrtho(w, h)
+		gl.LoadIdentity()
+		gl.ClearColor(0.1, 0.1, 0.1, 1)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+
+		if err := ed.draw(w, h, text); err != nil {
+			return err
+		}
+		window.SwapBuffers()
+	}
+	return nil
+}
+
+// load reads the sprite sheet and the configuration.
+func (ed *editor) load(sheet, config string) error {
+	fd, err := os.Open(sheet)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	src, _, err := image.Decode(fd)
+	if err != nil {
+		return fmt.Errorf("%s: %v", sheet, err)
+	}
+	ed.img = image.NewRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(ed.img, ed.img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	data, err := os.ReadFile(config)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, &ed.config); err != nil {
+		return fmt.Errorf("%s: %v", config, err)
+	}
+	return ed.config.Validate(ed.img.Bounds())
+}
+
+// rebuild creates the preview font from the current configuration.
+func (ed *editor) rebuild() error {
+	font, err := glsymbol.LoadBitmap(ed.img, &ed.config)
+	if err != nil {
+		return err
+	}
+	if ed.preview != nil {
+		ed.preview.Release()
+	}
+	ed.preview = font
+	return nil
+}
+
+// key applies a key press to the selected glyph.
+func (ed *editor) key(key glfw.Key, mods glfw.ModifierKey) {
+	n := len(ed.config.Glyphs)
+	if n == 0 {
+		return
+	}
+	shift := mods&glfw.ModShift != 0
+	g := ed.config.Glyphs[ed.selected]
+	switch key {
+	case glfw.KeyTab:
+		if shift {
+			ed.selected = (ed.selected + n - 1) % n
+		} else {
+			ed.selected = (ed.selected + 1) % n
+		}
+		ed.status = ""
+		return
+	case glfw.KeyS:
+		if mods&glfw.ModControl != 0 {
+			ed.save()
+		}
+		return
+	case glfw.KeyLeft:
+		if shift {
+			g.Width--
+		} else {
+			g.X--
+		}
+	case glfw.KeyRight:
+		if shift {
+			g.Width++
+		} else {
+			g.X++
+		}
+	case glfw.KeyUp:
+		if shift {
+			g.Height--
+		} else {
+			g.Y--
+		}
+	case glfw.KeyDown:
+		if shift {
+			g.Height++
+		} else {
+			g.Y++
+		}
+	case glfw.KeyMinus:
+		g.Advance--
+	case glfw.KeyEqual:
+		g.Advance++
+	default:
+		return
+	}
+
+	old := ed.config.Glyphs[ed.selected]
+	ed.config.Glyphs[ed.selected] = g
+	if err := ed.rebuild(); err != nil {
+		// keep the last valid state
+		ed.config.Glyphs[ed.selected] = old
+		ed.status = err.Error()
+		return
+	}
+	ed.status = ""
+}
+
+// save writes the configuration to the output file.
+func (ed *editor) save() {
+	data, err := json.MarshalIndent(ed.config, "", "\t")
+	if err == nil {
+		err = os.WriteFile(ed.output, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		ed.status = err.Error()
+		return
+	}
+	ed.status = "saved " + ed.output
+}
+
+// draw renders the sprite sheet, the selected glyph and the sample text.
+func (ed *editor) draw(w, h int, text string) error {
+	const margin = 10
+	_, lh := ed.ui.GlyphBounds()
+
+	// sprite sheet, scaled to fit the upper part of window
+	bounds := ed.img.Bounds()
+	zoom := float32(w-2*margin) / float32(bounds.Dx())
+	if z := float32(h/2) / float32(bounds.Dy()); z < zoom {
+		zoom = z
+	}
+	if zoom <= 0 {
+		// window is too small
+		return nil
+	}
+	ox, oy := float32(margin), float32(h-margin)
+	gl.RasterPos2f(ox, oy)
+	gl.PixelZoom(zoom, -zoom)
+	gl.DrawPixels(int32(bounds.Dx()), int32(bounds.Dy()),
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(ed.img.Pix))
+	gl.PixelZoom(1, 1)
+
+	// selected glyph rectangle
+	g := ed.config.Glyphs[ed.selected]
+	x0, y0 := ox+float32(g.X)*zoom, oy-float32(g.Y)*zoom
+	x1, y1 := x0+float32(g.Width)*zoom, y0-float32(g.Height)*zoom
+	gl.Color4f(1, 0, 0, 1)
+	gl.Begin(gl.LINE_LOOP)
+	gl.Vertex2f(x0, y0)
+	gl.Vertex2f(x1, y0)
+	gl.Vertex2f(x1, y1)
+	gl.Vertex2f(x0, y1)
+	gl.End()
+
+	// sample string rendered by the edited font,
+	// runes missing in the font are skipped
+	sample := []rune(text)[:0]
+	for _, r := range text {
+		if ed.config.Has(r) {
+			sample = append(sample, r)
+		}
+	}
+	gl.Color4f(1, 1, 1, 1)
+	_, ph := ed.preview.GlyphBounds()
+	if err := ed.preview.Printf(margin, float32(2*lh+margin+ph), string(sample)); err != nil {
+		return err
+	}
+
+	// status lines
+	r := ed.config.RuneAt(ed.selected)
+	gl.Color4f(1, 1, 0, 1)
+	info := fmt.Sprintf("glyph %+q (%d): x=%d y=%d width=%d height=%d advance=%d",
+		r, r, g.X, g.Y, g.Width, g.Height, g.Advance)
+	if err := ed.ui.Printf(margin, float32(lh+margin), info); err != nil {
+		return err
+	}
+	return ed.ui.Printf(margin, margin, ed.status)
+}
@@ -0,0 +1,207 @@
+// Command glsymbol-preview shows a TrueType font rendered by glsymbol.
+//
+// The window holds a pangram at several sizes and the grid of all loaded
+// glyphs. Metrics overlays of the grid show the cell, the advance and
+// the bottom line of every glyph.
+//
+// Usage:
+//
+//	glsymbol-preview [-low 32] [-high 127] [-size 16] font.ttf
+//
+// Keys:
+//
+//	M        toggle metrics overlays
+//	Escape   quit
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/Konstantin8105/glsymbol"
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const pangram = "The quick brown fox jumps over the lazy dog 0123456789"
+
+// sizes of the pangram lines
+var sizes = []int{8, 10, 12, 16, 20, 24, 32}
+
+func init() {
+	// GLFW event handling must run on the main OS thread.
+	runtime.LockOSThread()
+}
+
+func main() {
+	var (
+		low  = flag.Int("low", 32, "lower rune boundary")
+		high = flag.Int("high", 127, "upper rune boundary")
+		size = flag.Int("size", 16, "font size of glyph grid")
+	)
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: glsymbol-preview [flags] font.ttf")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), rune(*low), rune(*high), int32(*size)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, low, high rune, size int32) error {
+	if err := glfw.Init(); err != nil {
+		return fmt.Errorf("failed to initialize glfw: %v", err)
+	}
+	defer glfw.Terminate()
+
+	glfw.WindowHint(glfw.Resizable, glfw.True)
+	glfw.WindowHint(glfw.ContextVersionMajor, 2)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+
+	window, err := glfw.CreateWindow(1000, 700, "glsymbol-preview: "+file, nil, nil)
+	if err != nil {
+		return err
+	}
+	window.MakeContextCurrent()
+	if err = gl.Init(); err != nil {
+		return err
+	}
+	glfw.SwapInterval(1)
+
+	fd, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	face, err := glsymbol.NewFace(fd, low, high)
+	fd.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	defer face.Release()
+	grid, err := face.Font(size)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+
+	overlay := true
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		if action != glfw.Press {
+			return
+		}
+		switch key {
+		case glfw.KeyEscape:
+			w.SetShouldClose(true)
+		case glfw.KeyM:
+			overlay = !overlay
+		}
+	})
+
+	for !window.ShouldClose() {
+		glfw.PollEvents()
+		w, h := window.GetSize()
+		glsymbol.SetupOrtho(w, h)
+		gl.LoadIdentity()
+		gl.ClearColor(0, 0, 0, 1)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+
+		const margin = 10
+		y := float32(h - margin)
+
+		// pangram at all sizes
+		gl.Color4f(1, 1, 1, 1)
+		if err := glsymbol.RenderWaterfall(face, sizes, margin, y, filter(grid, pangram)); err != nil {
+			return err
+		}
+		for _, size := range sizes {
+			font, err := face.Font(int32(size))
+			if err != nil {
+				return err
+			}
+			// the waterfall moves down by the line height of every size
+			_, lh := font.BoundingBox("")
+			y -= float32(lh)
+		}
+
+		// grid of all glyphs
+		gw, gh := grid.GlyphBounds()
+		cols := (w - 2*margin) / int(gw+4)
+		if cols < 1 {
+			cols = 1
+		}
+		y -= float32(2 * gh)
+		for i := range grid.Config.Glyphs {
+			x := float32(margin + (i%cols)*int(gw+4))
+			gy := y - float32((i/cols)*int(gh+4))
+			if gy < 0 {
+				break
+			}
+			r := grid.Config.RuneAt(i)
+			if overlay {
+				drawMetrics(grid, x, gy, string(r))
+			}
+			gl.Color4f(1, 1, 1, 1)
+			if err := grid.Printf(x, gy, string(r)); err != nil {
+				return err
+			}
+		}
+
+		window.SwapBuffers()
+	}
+	return nil
+}
+
+// filter removes runes which are not present in the font.
+func filter(font *glsymbol.Font, text string) string {
+	rs := []rune(text)[:0]
+	for _, r := range text {
+		if font.Config.Has(r) {
+			rs = append(rs, r)
+		}
+	}
+	return string(rs)
+}
+
+// drawMetrics draws the bitmap box and the advance of every glyph and
+// the bottom line for the text rendered at x, y, as Printf places them.
+func drawMetrics(font *glsymbol.Font, x, y float32, text string) {
+	var pen float32
+	for _, r := range text {
+		g := font.Config.Glyphs[font.Config.Index(r)]
+		step := float32(g.LeftBearing + g.Width + g.RightBearing)
+
+		// glyph bitmap, placed by its bearings in the cell
+		gl.Color4f(0.2, 0.3, 0.6, 1)
+		x0, y0 := x+pen+float32(g.LeftBearing), y+float32(g.BottomBearing)
+		rect(x0, y0, x0+float32(g.Width), y0+float32(g.Height))
+
+		// advance
+		gl.Color4f(0.6, 0.3, 0.2, 1)
+		gl.Begin(gl.LINES)
+		gl.Vertex2f(x+pen+step, y)
+		gl.Vertex2f(x+pen+step, y+float32(g.TopBearing+g.Height+g.BottomBearing)/4)
+		gl.End()
+
+		pen += step
+	}
+
+	// bottom line
+	gl.Color4f(0.2, 0.6, 0.2, 1)
+	gl.Begin(gl.LINES)
+	gl.Vertex2f(x, y)
+	gl.Vertex2f(x+pen, y)
+	gl.End()
+}
+
+func rect(x0, y0, x1, y1 float32) {
+	gl.Begin(gl.LINE_LOOP)
+	gl.Vertex2f(x0, y0)
+	gl.Vertex2f(x1, y0)
+	gl.Vertex2f(x1, y1)
+	gl.Vertex2f(x0, y1)
+	gl.End()
+}
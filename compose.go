@@ -0,0 +1,102 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// sheetMask is the coverage of glyphs of a sprite sheet. Sheets are
+// white glyphs on a transparent or black background, so the coverage
+// is the red channel.
+type sheetMask struct {
+	img *image.RGBA
+}
+
+func (m sheetMask) ColorModel() color.Model { return color.Alpha16Model }
+func (m sheetMask) Bounds() image.Rectangle { return m.img.Bounds() }
+
+func (m sheetMask) At(x, y int) color.Color {
+	r, _, _, _ := m.img.At(x, y).RGBA()
+	return color.Alpha16{A: uint16(r)}
+}
+
+// DrawImage composites the single line string into the image on the
+// CPU, with glyphs placed as Printf places them and the top left corner
+// of the first glyph cell at x, y. Coordinates of images go down.
+// Glyphs are anti-aliased by the coverage of the sprite sheet and drawn
+// over the image by the color.
+func (f *Font) DrawImage(dst draw.Image, x, y int, str string, c color.Color) {
+	src := image.NewUniform(c)
+	mask := sheetMask{f.img}
+	f.EachGlyph(str, func(g PlacedGlyph) bool {
+		if !f.limit(str, g) {
+			return false
+		}
+		glyph := g.Glyph
+		// glyph rows start one row below the glyph rectangle, see loadFont
+		r := image.Rect(0, 0, int(glyph.Width), int(glyph.Height)).
+			Add(image.Pt(x+int(g.X), y+int(f.MaxGlyphHeight-glyph.Height-glyph.BottomBearing-g.Y)))
+		draw.DrawMask(dst, r, src, image.Point{}, mask, image.Pt(int(glyph.X), int(glyph.Y)+1), draw.Over)
+		return true
+	})
+}
+
+// DrawImageLines composites the multi-line text into the image as
+// DrawImage with the top left corner of the first line at x, y. Lines
+// are broken by the newline policy and placed with the line height of
+// the font.
+func (f *Font) DrawImageLines(dst draw.Image, x, y int, text string, c color.Color) {
+	lh := int(f.lineHeight())
+	y += int(f.halfLeading())
+	for _, line := range splitLines(text) {
+		f.DrawImage(dst, x, y, line, c)
+		y += lh
+	}
+}
+
+// RenderImage returns a new image of the text with the padding around
+// it, drawn by the color on a transparent background, for example for
+// textures or window icons.
+func (f *Font) RenderImage(text string, c color.Color, padding int) *image.RGBA {
+	w, h := f.BoundingBox(text)
+	img := image.NewRGBA(image.Rect(0, 0, int(w)+2*padding, int(h)+2*padding))
+	f.DrawImageLines(img, padding, padding, text, c)
+	return img
+}
+
+// BadgeImage returns a square image of the size with a short text, for
+// example a count, centered on a disc of the background color. The
+// image suits window icons and custom cursors of window libraries, for
+// example glfw.Window.SetIcon and glfw.CreateCursor. A nil background
+// leaves the image transparent.
+func (f *Font) BadgeImage(text string, size int, fg, bg color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	if bg != nil {
+		br, bgr, bb, ba := bg.RGBA()
+		r := float64(size) / 2
+		for py := 0; py < size; py++ {
+			for px := 0; px < size; px++ {
+				// coverage of the pixel by the disc, anti-aliased by distance
+				dx, dy := float64(px)+0.5-r, float64(py)+0.5-r
+				cov := r - math.Hypot(dx, dy) + 0.5
+				if cov <= 0 {
+					continue
+				}
+				if 1 < cov {
+					cov = 1
+				}
+				img.SetRGBA64(px, py, color.RGBA64{
+					R: uint16(float64(br) * cov),
+					G: uint16(float64(bgr) * cov),
+					B: uint16(float64(bb) * cov),
+					A: uint16(float64(ba) * cov),
+				})
+			}
+		}
+	}
+	w, h := f.BoundingBox(text)
+	f.DrawImageLines(img, (size-int(w))/2, (size-int(h))/2, text, fg)
+	return img
+}
@@ -0,0 +1,70 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testSheetFont returns testFont with a sprite sheet where 'A' is
+// a filled box and other glyphs are empty.
+func testSheetFont() *Font {
+	f := testFont()
+	var x int32
+	for i := range f.Config.Glyphs {
+		f.Config.Glyphs[i].X = x
+		x += f.Config.Glyphs[i].Width
+	}
+	f.img = image.NewRGBA(image.Rect(0, 0, int(x), 18))
+	a := f.Config.Glyphs['A'-f.Config.Low]
+	for py := a.Y + 1; py <= a.Y+a.Height; py++ {
+		for px := a.X; px < a.X+a.Width; px++ {
+			f.img.Set(int(px), int(py), color.White)
+		}
+	}
+	return f
+}
+
+func TestDrawImage(t *testing.T) {
+	f := testSheetFont()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	f.DrawImage(img, 2, 1, "iA", red)
+	for _, tc := range []struct {
+		x, y int
+		c    color.RGBA
+	}{
+		{1, 1, color.RGBA{}},
+		{5, 5, color.RGBA{}}, // empty glyph
+		{6, 1, red},          // top left of 'A'
+		{13, 16, red},        // bottom right of 'A'
+		{14, 16, color.RGBA{}},
+		{13, 17, color.RGBA{}},
+	} {
+		if c := img.RGBAAt(tc.x, tc.y); c != tc.c {
+			t.Errorf("pixel %d,%d is %v, expect %v", tc.x, tc.y, c, tc.c)
+		}
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	f := testSheetFont()
+	img := f.RenderImage("A\nAA", color.White, 2)
+	if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 36 {
+		t.Errorf("bounds %v", b)
+	}
+	if c := img.RGBAAt(2+8, 2+16+1); c.A != 0xff {
+		t.Errorf("second line is not drawn")
+	}
+
+	badge := f.BadgeImage("A", 32, color.White, color.RGBA{0, 0, 0xff, 0xff})
+	if c := badge.RGBAAt(0, 0); c.A != 0 {
+		t.Errorf("corner is covered %v", c)
+	}
+	if c := badge.RGBAAt(16, 16); c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("center %v", c)
+	}
+	if c := badge.RGBAAt(16, 2); c != (color.RGBA{0, 0, 0xff, 0xff}) {
+		t.Errorf("disc %v", c)
+	}
+}
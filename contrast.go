@@ -0,0 +1,129 @@
+package glsymbol
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Luminance returns the relative luminance of the color from 0 for black
+// to 1 for white, as defined by WCAG.
+func Luminance(c color.Color) float32 {
+	r, g, b, _ := c.RGBA()
+	lin := func(v uint32) float64 {
+		s := float64(v) / 0xffff
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return float32(0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b))
+}
+
+// ContrastColor returns black or white, whichever has more contrast
+// with a background of the luminance.
+func ContrastColor(luminance float32) color.RGBA {
+	// contrast ratios with black and white are equal at this luminance
+	if 0.179 < luminance {
+		return color.RGBA{0, 0, 0, 0xff}
+	}
+	return color.RGBA{0xff, 0xff, 0xff, 0xff}
+}
+
+// SampleLuminance returns the average luminance of the framebuffer in
+// the rectangle given in the coordinates of SetupOrtho. It reads pixels
+// back from OpenGL, which stalls the pipeline, so it should be used for
+// a few labels per frame.
+func SampleLuminance(r Rect) (float32, error) {
+	x, y := int32(r.X), int32(r.Y)
+	w, h := int32(r.Width), int32(r.Height)
+	if origin == OriginTopLeft {
+		_, vh := viewportSize()
+		y = int32(vh) - y - h
+	}
+	if w <= 0 || h <= 0 {
+		return 0, nil
+	}
+	pix := make([]uint8, 4*w*h)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(x, y, w, h, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	if err := checkGLError("SampleLuminance"); err != nil {
+		return 0, err
+	}
+	return averageLuminance(pix), nil
+}
+
+// averageLuminance returns the average luminance of RGBA pixels.
+func averageLuminance(pix []uint8) float32 {
+	if len(pix) < 4 {
+		return 0
+	}
+	var sum float32
+	for i := 0; i+3 < len(pix); i += 4 {
+		sum += Luminance(color.RGBA{pix[i], pix[i+1], pix[i+2], 0xff})
+	}
+	return sum / float32(len(pix)/4)
+}
+
+// Contrast selects how text is kept readable over a background.
+type Contrast int
+
+// Contrast modes.
+const (
+	// ContrastText draws text black or white, see ContrastColor.
+	ContrastText Contrast = iota
+
+	// ContrastOutline keeps the current color and draws an outline of
+	// one pixel by the color contrasting with the background.
+	ContrastOutline
+)
+
+// SampleBackground is the background luminance of PrintfContrast which
+// means sampling of the framebuffer under the string.
+const SampleBackground float32 = -1
+
+// PrintfContrast draws the string as Printf readable over the background
+// of the luminance, for example over a 3D render whose colors change with
+// the camera. If the luminance is SampleBackground, it is sampled under
+// the string by SampleLuminance. The current color is not changed.
+func (f *Font) PrintfContrast(x, y float32, str string, background float32, mode Contrast) error {
+	if !viewportVisible() {
+		return nil
+	}
+	if background < 0 {
+		h := float32(f.MaxGlyphHeight)
+		w := f.advanceSize(str)
+		// the string ends at x in the RightToLeft direction
+		box := Rect{X: x - float32(f.anchor(w)), Y: y, Width: float32(w), Height: h}
+		var err error
+		if background, err = SampleLuminance(box); err != nil {
+			return err
+		}
+	}
+	c := ContrastColor(background)
+
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	if mode == ContrastOutline {
+		var text [4]float32
+		gl.GetFloatv(gl.CURRENT_COLOR, &text[0])
+		gl.Color4ub(c.R, c.G, c.B, c.A)
+		for _, d := range outlineOffsets {
+			if err := f.Printf(x+d[0], y+d[1], str); err != nil {
+				return err
+			}
+		}
+		gl.Color4fv(&text[0])
+	} else {
+		gl.Color4ub(c.R, c.G, c.B, c.A)
+	}
+	return f.Printf(x, y, str)
+}
+
+// outlineOffsets are offsets of copies of text drawn as its outline.
+var outlineOffsets = [8][2]float32{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
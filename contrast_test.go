@@ -0,0 +1,32 @@
+package glsymbol
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestContrast(t *testing.T) {
+	for _, tc := range []struct {
+		c    color.Color
+		l    float32
+		text color.RGBA
+	}{
+		{color.Black, 0, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{color.White, 1, color.RGBA{0, 0, 0, 0xff}},
+		{color.RGBA{0x80, 0x80, 0x80, 0xff}, 0.216, color.RGBA{0, 0, 0, 0xff}},
+		{color.RGBA{0, 0, 0xff, 0xff}, 0.0722, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+	} {
+		l := Luminance(tc.c)
+		if math.Abs(float64(l-tc.l)) > 1e-3 {
+			t.Errorf("luminance of %v: %v, expect %v", tc.c, l, tc.l)
+		}
+		if c := ContrastColor(l); c != tc.text {
+			t.Errorf("text over %v: %v", tc.c, c)
+		}
+	}
+	pix := []uint8{0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if l := averageLuminance(pix); l != 0.5 {
+		t.Errorf("average luminance %v", l)
+	}
+}
@@ -0,0 +1,86 @@
+package glsymbol
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// Counters is a snapshot of counters of the package, see ReadCounters.
+//
+// Values only grow, except AtlasBytes, so rates are differences of
+// two snapshots, see Sub: for example glyphs drawn per frame are
+// GlyphsDrawn of the difference of snapshots taken after two frames.
+// Fields are flat numbers, which map to Prometheus counters and gauges
+// one by one.
+type Counters struct {
+	FontsLoaded   uint64 `json:"fontsLoaded"`   // fonts loaded
+	FontsReleased uint64 `json:"fontsReleased"` // fonts released by Font.Release
+	AtlasBytes    int64  `json:"atlasBytes"`    // memory of sprite sheets of fonts in use
+	GlyphsDrawn   uint64 `json:"glyphsDrawn"`   // glyphs drawn by Printf and PrintfColors
+
+	// CacheHits and CacheMisses count lookups of fonts of Face and of
+	// glyphs added to fonts, a miss rasterizes a font or calls Fallback.
+	CacheHits   uint64 `json:"cacheHits"`
+	CacheMisses uint64 `json:"cacheMisses"`
+}
+
+// HitRate returns the share of cache lookups which are hits, from 0 to 1,
+// or 1 if there are no lookups.
+func (s Counters) HitRate() float64 {
+	n := s.CacheHits + s.CacheMisses
+	if n == 0 {
+		return 1
+	}
+	return float64(s.CacheHits) / float64(n)
+}
+
+// Sub returns the difference of the snapshot and the previous one.
+func (s Counters) Sub(prev Counters) Counters {
+	return Counters{
+		FontsLoaded:   s.FontsLoaded - prev.FontsLoaded,
+		FontsReleased: s.FontsReleased - prev.FontsReleased,
+		AtlasBytes:    s.AtlasBytes - prev.AtlasBytes,
+		GlyphsDrawn:   s.GlyphsDrawn - prev.GlyphsDrawn,
+		CacheHits:     s.CacheHits - prev.CacheHits,
+		CacheMisses:   s.CacheMisses - prev.CacheMisses,
+	}
+}
+
+// stats holds the counters, they are updated from any goroutine.
+var stats struct {
+	fontsLoaded   atomic.Uint64
+	fontsReleased atomic.Uint64
+	atlasBytes    atomic.Int64
+	glyphsDrawn   atomic.Uint64
+	cacheHits     atomic.Uint64
+	cacheMisses   atomic.Uint64
+}
+
+// ReadCounters returns a snapshot of the counters of the package.
+func ReadCounters() Counters {
+	return Counters{
+		FontsLoaded:   stats.fontsLoaded.Load(),
+		FontsReleased: stats.fontsReleased.Load(),
+		AtlasBytes:    stats.atlasBytes.Load(),
+		GlyphsDrawn:   stats.glyphsDrawn.Load(),
+		CacheHits:     stats.cacheHits.Load(),
+		CacheMisses:   stats.cacheMisses.Load(),
+	}
+}
+
+// CountersVar returns an expvar variable of the counters, which is
+// published by expvar.Publish, for example:
+//
+//	expvar.Publish("glsymbol", glsymbol.CountersVar())
+func CountersVar() expvar.Var {
+	return expvar.Func(func() interface{} { return ReadCounters() })
+}
+
+// countCache counts a cache lookup.
+func countCache(hit bool) {
+	if hit {
+		stats.cacheHits.Add(1)
+	} else {
+		stats.cacheMisses.Add(1)
+	}
+}
@@ -0,0 +1,40 @@
+package glsymbol
+
+import (
+	"encoding/json"
+	"image"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	before := ReadCounters()
+	f := testFont()
+	f.Fallback = func(r rune) image.Image {
+		return image.NewAlpha(image.Rect(0, 0, 4, 4))
+	}
+	f.advanceSize("ЖЖЖ")
+	f.Release()
+
+	d := ReadCounters().Sub(before)
+	if d.CacheMisses != 1 || d.CacheHits != 2 {
+		t.Errorf("cache %d hits, %d misses", d.CacheHits, d.CacheMisses)
+	}
+	if r := d.HitRate(); r < 0.66 || 0.67 < r {
+		t.Errorf("hit rate %v", r)
+	}
+	// the sheet grown by the fallback glyph is released
+	if d.AtlasBytes != 0 || d.FontsReleased != 1 {
+		t.Errorf("atlas bytes %d, released %d", d.AtlasBytes, d.FontsReleased)
+	}
+	if (Counters{}).HitRate() != 1 {
+		t.Errorf("hit rate without lookups")
+	}
+
+	var v Counters
+	if err := json.Unmarshal([]byte(CountersVar().String()), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.CacheMisses < d.CacheMisses {
+		t.Errorf("expvar %+v", v)
+	}
+}
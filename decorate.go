@@ -0,0 +1,128 @@
+package glsymbol
+
+import (
+	"image/color"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// DecorationStyle is a style of lines decorating ranges of text.
+type DecorationStyle int
+
+// Decoration styles.
+const (
+	DecorationUnderline DecorationStyle = iota // straight line
+	DecorationSquiggly                         // wavy line, as spelling and lint markers
+)
+
+// Sizes of decoration lines in pixels: heights of lines and the
+// horizontal period of wavy lines.
+const (
+	squiggleHeight  = 3
+	squigglePeriod  = 4
+	underlineHeight = 1
+)
+
+// Decoration decorates the bytes from Start up to End of a text with a
+// line below the baseline.
+type Decoration struct {
+	Start, End int
+	Style      DecorationStyle
+	Color      color.Color // nil for the current color
+}
+
+// decorationRects returns the rectangles of lines of the decoration,
+// one on every line of the text with decorated runes, relative to the
+// top left corner of the text with Y going down. Lines start one pixel
+// below the baseline, as underlines of links.
+func (t *Text) decorationRects(d Decoration) []Rect {
+	t.remeasure()
+	f := t.font
+	h := float32(underlineHeight)
+	if d.Style == DecorationSquiggly {
+		h = squiggleHeight
+	}
+	lh := f.lineHeight()
+	underline := f.halfLeading() + f.MaxGlyphHeight - f.Config.Baseline + 1
+	var rects []Rect
+	for i, l := range t.lines {
+		end := l.start + len(l.text)
+		if end <= d.Start || d.End <= l.start {
+			continue
+		}
+		from, to := d.Start-l.start, d.End-l.start
+		if from < 0 {
+			from = 0
+		}
+		if len(l.text) < to {
+			to = len(l.text)
+		}
+		if to <= from {
+			continue
+		}
+		rects = append(rects, Rect{
+			X:      float32(f.advanceSize(l.text[:from])),
+			Y:      float32(int32(i)*lh + underline),
+			Width:  float32(f.advanceSize(l.text[from:to])),
+			Height: h,
+		})
+	}
+	return rects
+}
+
+// DrawDecorations draws lines of the decorations of the text drawn by
+// Draw at the same coordinates, for example squiggly lines under
+// misspelled words. Bytes out of the text are not decorated.
+func (t *Text) DrawDecorations(x, y float32, decorations []Decoration) error {
+	for _, d := range decorations {
+		for _, r := range t.decorationRects(d) {
+			r.X += x
+			r.Y = linePos(below(y, r.Y), r.Height)
+			if d.Style == DecorationSquiggly {
+				drawSquiggle(r, d.Color)
+			} else {
+				fillRect(r, d.Color)
+			}
+		}
+	}
+	return checkGLError("DrawDecorations")
+}
+
+// squigglePoints returns points of a zigzag line going between the top
+// and the bottom edges of the rectangle with the squigglePeriod, as
+// pairs of coordinates.
+func squigglePoints(r Rect) []float32 {
+	lo, hi := r.Y+0.5, r.Y+r.Height-0.5
+	half := float32(squigglePeriod) / 2
+	points := []float32{r.X, lo}
+	for x, from, to := r.X, lo, hi; x < r.X+r.Width; from, to = to, from {
+		next := x + half
+		if end := r.X + r.Width; end < next {
+			// the line ends part way of a slope
+			return append(points, end, from+(to-from)*(end-x)/half)
+		}
+		points = append(points, next, to)
+		x = next
+	}
+	return points
+}
+
+// drawSquiggle draws a wavy line in the rectangle with the color, or
+// with the current color if c is nil.
+func drawSquiggle(r Rect, c color.Color) {
+	gl.PushAttrib(gl.CURRENT_BIT | gl.ENABLE_BIT | gl.LINE_BIT)
+	defer gl.PopAttrib()
+
+	if c != nil {
+		setColor(c)
+	}
+	gl.Disable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Begin(gl.LINE_STRIP)
+	points := squigglePoints(r)
+	for i := 0; i+1 < len(points); i += 2 {
+		gl.Vertex2f(points[i], points[i+1])
+	}
+	gl.End()
+}
@@ -0,0 +1,34 @@
+package glsymbol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecorationRects(t *testing.T) {
+	text := NewText(testFont(), "aaa bb\nc\n\ndd")
+	// the font has no descent, lines are one pixel below the cells
+	got := text.decorationRects(Decoration{Start: 4, End: 12, Style: DecorationSquiggly})
+	expect := []Rect{
+		{X: 28, Y: 17, Width: 16, Height: squiggleHeight},
+		{X: 0, Y: 33, Width: 8, Height: squiggleHeight},
+		{X: 0, Y: 65, Width: 16, Height: squiggleHeight},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("rects %+v", got)
+	}
+	if got := text.decorationRects(Decoration{Start: 0, End: 1}); len(got) != 1 || got[0].Height != underlineHeight {
+		t.Errorf("underline %+v", got)
+	}
+	if got := text.decorationRects(Decoration{Start: 20, End: 30}); len(got) != 0 {
+		t.Errorf("decoration out of the text %+v", got)
+	}
+}
+
+func TestSquigglePoints(t *testing.T) {
+	got := squigglePoints(Rect{X: 10, Y: 0, Width: 5, Height: 3})
+	expect := []float32{10, 0.5, 12, 2.5, 14, 0.5, 15, 1.5}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("points %v", got)
+	}
+}
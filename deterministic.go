@@ -0,0 +1,94 @@
+package glsymbol
+
+import (
+	"time"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// deterministic is the mode of SetDeterministic.
+var deterministic bool
+
+// SetDeterministic switches text rendering to a deterministic mode for
+// tests comparing frames with golden screenshots, so text frames are
+// byte-identical across runs:
+//
+//   - the wall clock stands still at the zero time, so the caret does
+//     not blink and notifications do not fade or expire; helpers with
+//     their own Clock keep it,
+//   - fonts round positions and sizes by RoundFloor whatever their
+//     Rounding is,
+//   - Printf draws glyphs as textured quads at whole pixels instead of
+//     gl.Bitmap, whose rasterization differs between drivers.
+//
+// Like SetOrigin it affects all fonts and must be called on the
+// goroutine holding the OpenGL context.
+func SetDeterministic(on bool) {
+	deterministic = on
+}
+
+// wallClock returns the time of the wall clock, see SetDeterministic.
+func wallClock() time.Time {
+	if deterministic {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// rounding returns the rounding policy of the font, see SetDeterministic.
+func (f *Font) rounding() Rounding {
+	if deterministic {
+		return RoundFloor
+	}
+	return f.Rounding
+}
+
+// drawQuad draws the glyph as a textured quad of the texture page with
+// the bottom left corner of the bitmap at x, y, or the top left corner
+// with OriginTopLeft. The texture is bound if it is not bound yet.
+func (f *Font) drawQuad(pages []texturePage, bound *int, glyph *Glyph, x, y int32) {
+	p := pageOf(pages, glyph)
+	if p != *bound {
+		gl.BindTexture(gl.TEXTURE_2D, pages[p].tex)
+		*bound = p
+	}
+	// texture coordinates of the bottom left, bottom right, top right
+	// and top left corners
+	var tex [4][2]float32
+	if u := glyph.upright; u != nil {
+		// the bottom left corner of the upright glyph is at the top left
+		u0, v0, u1, v1 := f.glyphTexCoords(pages[p], u)
+		tex = [4][2]float32{{u1, v1}, {u1, v0}, {u0, v0}, {u0, v1}}
+	} else {
+		u0, v0, u1, v1 := f.glyphTexCoords(pages[p], glyph)
+		tex = [4][2]float32{{u0, v1}, {u1, v1}, {u1, v0}, {u0, v0}}
+	}
+	left, right := float32(x), float32(x+glyph.Width)
+	bottom := float32(y)
+	top := above(bottom, float32(glyph.Height))
+	gl.Begin(gl.QUADS)
+	gl.TexCoord2f(tex[0][0], tex[0][1])
+	gl.Vertex2f(left, bottom)
+	gl.TexCoord2f(tex[1][0], tex[1][1])
+	gl.Vertex2f(right, bottom)
+	gl.TexCoord2f(tex[2][0], tex[2][1])
+	gl.Vertex2f(right, top)
+	gl.TexCoord2f(tex[3][0], tex[3][1])
+	gl.Vertex2f(left, top)
+	gl.End()
+}
+
+// beginQuads pushes and sets the OpenGL state for drawing of glyphs as
+// textured quads in the deterministic mode and returns true, the caller
+// pops it. It returns false otherwise.
+func beginQuads() bool {
+	if !deterministic {
+		return false
+	}
+	gl.PushAttrib(gl.ENABLE_BIT | gl.TEXTURE_BIT | gl.COLOR_BUFFER_BIT)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.TexEnvi(gl.TEXTURE_ENV, gl.TEXTURE_ENV_MODE, gl.MODULATE)
+	return true
+}
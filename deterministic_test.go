@@ -0,0 +1,37 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeterministic(t *testing.T) {
+	SetDeterministic(true)
+	defer SetDeterministic(false)
+
+	in := &Input{Font: testFont(), Blink: time.Millisecond}
+	in.SetText("abc")
+	time.Sleep(2 * time.Millisecond)
+	if !in.CaretVisible() {
+		t.Errorf("caret blinks")
+	}
+	if !now(nil).Equal(now(SystemClock)) {
+		t.Errorf("wall clock moves")
+	}
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if !now(c).Equal(c.Now()) {
+		t.Errorf("own clock is not kept")
+	}
+
+	f := testFont()
+	f.Rounding = RoundNearest
+	if r := f.rounding(); r != RoundFloor {
+		t.Errorf("rounding %v", r)
+	}
+	h := f.TextHash("abc", nil)
+	SetDeterministic(false)
+	f.Rounding = RoundFloor
+	if f.TextHash("abc", nil) != h {
+		t.Errorf("hash differs from the one of the floor rounding")
+	}
+}
@@ -0,0 +1,77 @@
+package glsymbol
+
+// Direction is the direction the pen advances in over a line of text.
+type Direction int
+
+// Directions of the pen.
+const (
+	LeftToRight Direction = iota // the string starts at x and grows to the right
+	RightToLeft                  // the string ends at x and grows to the left
+	TopToBottom                  // the string starts at y and grows down
+)
+
+// placeRTL calls fn for glyphs of the string placed by placeLTR in the
+// opposite direction: the first glyph is at the right edge, x is zero,
+// and the pen moves left, so glyphs have negative X. Bitmaps are not
+// mirrored, every glyph keeps its bearings in its advance cell.
+func (f *Font) placeRTL(str string, o drawOptions, fn func(g PlacedGlyph) bool) (int32, bool) {
+	return f.placeLTR(str, o, func(g PlacedGlyph) bool {
+		pen := g.X - g.Glyph.LeftBearing
+		g.X = -(pen + g.Step) + g.Glyph.LeftBearing
+		return fn(g)
+	})
+}
+
+// placeTTB calls fn for glyphs of the string placed in a column: the
+// pen moves down by vertical advances of glyphs and every glyph is
+// centered in the column of MaxGlyphWidth, so glyphs have negative Y
+// and Step is the vertical advance. Glyphs may be turned, see Sideways.
+func (f *Font) placeTTB(str string, o drawOptions, fn func(g PlacedGlyph) bool) (int32, bool) {
+	var pen int32
+	_, all := f.placeLTR(str, o, func(g PlacedGlyph) bool {
+		if !f.turnSideways(&g) {
+			step := f.verticalStep(g.Glyph)
+			g.X = (f.MaxGlyphWidth-g.Step)/2 + g.Glyph.LeftBearing
+			g.Step = step
+		}
+		g.Y -= pen
+		pen += g.Step
+		return fn(g)
+	})
+	return pen, all
+}
+
+// verticalStep returns the distance the pen moves down over the glyph.
+func (f *Font) verticalStep(g *Glyph) int32 {
+	if g.VerticalAdvance != 0 {
+		return g.VerticalAdvance
+	}
+	return f.MaxGlyphHeight
+}
+
+// ColumnSize returns the size of the single line string drawn in the
+// TopToBottom direction: the width of the column and the distance the
+// pen moves down over the glyphs, whatever the direction of the font.
+func (f *Font) ColumnSize(str string) (width, height int32) {
+	height, _ = f.placeTTB(str, f.options(), func(PlacedGlyph) bool { return true })
+	return f.MaxGlyphWidth, height
+}
+
+// anchor returns the distance from the start of a line of the width to
+// the place of the pen Printf draws it from.
+func (f *Font) anchor(width int32) int32 {
+	if f.Direction == RightToLeft {
+		return width
+	}
+	return 0
+}
+
+// startX returns the place of the pen Printf draws the single line
+// string from, so the line starts at the left edge x whatever the
+// direction of the font, as in boxes measured from their left edges.
+func (f *Font) startX(x float32, str string) float32 {
+	if f.Direction == RightToLeft {
+		return x + float32(f.advanceSize(str))
+	}
+	return x
+}
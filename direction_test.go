@@ -0,0 +1,101 @@
+package glsymbol
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDirection(t *testing.T) {
+	f := testFont()
+	f.Direction = RightToLeft
+	// 'i' is 4 pixels wide
+	var xs []int32
+	pen, all := f.place("ai", func(g PlacedGlyph) bool {
+		xs = append(xs, g.X)
+		return true
+	})
+	if len(xs) != 2 || xs[0] != -8 || xs[1] != -12 || pen != 12 || !all {
+		t.Errorf("glyphs at %v, pen %d", xs, pen)
+	}
+	if w := f.advanceSize("ai"); w != 12 {
+		t.Errorf("width %d", w)
+	}
+
+	// lines of paragraphs end at their right edges
+	glyphs := f.ShapeParagraphs("ai", 100, ParagraphStyle{})
+	if len(glyphs) != 2 || glyphs[0].Box.Min != image.Pt(4, 0) || glyphs[1].Box.Min != image.Pt(0, 0) {
+		t.Errorf("positioned glyphs %+v", glyphs)
+	}
+
+	h := f.TextHash("ai", nil)
+	f.Direction = LeftToRight
+	if h == f.TextHash("ai", nil) {
+		t.Errorf("hash does not depend on the direction")
+	}
+}
+
+func TestTopToBottom(t *testing.T) {
+	f := testFont()
+	f.Direction = TopToBottom
+	f.Config.Glyphs['a'-f.Config.Low].VerticalAdvance = 10
+	var got []image.Point
+	pen, _ := f.place("aia", func(g PlacedGlyph) bool {
+		got = append(got, image.Pt(int(g.X), int(g.Y)))
+		return true
+	})
+	// 'i' of the width 4 is centered in the column of the width 8
+	expect := []image.Point{{0, 0}, {2, -10}, {0, -26}}
+	if pen != 36 || len(got) != 3 || got[0] != expect[0] || got[1] != expect[1] || got[2] != expect[2] {
+		t.Errorf("glyphs at %v, pen %d", got, pen)
+	}
+	f.Direction = LeftToRight
+	if w, h := f.ColumnSize("aia"); w != 8 || h != 36 {
+		t.Errorf("column size %dx%d", w, h)
+	}
+}
+
+func TestRightToLeftBoxes(t *testing.T) {
+	f := testFont()
+	f.Direction = RightToLeft
+	var q Queue
+	f.SetQueue(&q)
+	// queued strings end at x, they are drawn inside of the rectangle
+	inside := func(name string, r Rect) {
+		t.Helper()
+		for _, c := range q.cmds {
+			x0 := c.x - float32(f.advanceSize(c.str))
+			if x0 < r.X || r.X+r.Width < c.x {
+				t.Errorf("%s: %q at %v..%v is out of %+v", name, c.str, x0, c.x, r)
+			}
+		}
+		q.cmds = nil
+	}
+
+	text := NewText(f, "ab\naib")
+	if err := text.Draw(10, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		c := q.cmds[i]
+		if x0 := c.x - float32(f.advanceSize(c.str)); x0 != 10 {
+			t.Errorf("line %d starts at %v", i, x0)
+		}
+	}
+	inside("text", text.LineRect(0, 10, 0, 0))
+
+	box, err := f.DrawTextBox(0, 0, "ab\nc", nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := q.cmds[1]; c.x != 2+8 {
+		t.Errorf("second line ends at %v", c.x)
+	}
+	inside("text box", box)
+
+	var b Batch
+	box = b.Label(0, f, 0, 0, "ab\nc", nil, 2, nil)
+	for _, it := range b.items {
+		q.cmds = append(q.cmds, queueCmd{x: it.x, str: it.str})
+	}
+	inside("label", box)
+}
@@ -0,0 +1,135 @@
+package glsymbol
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// glyphSource rasterizes glyphs of a font on demand, see
+// LoadTruetypeDynamic.
+type glyphSource struct {
+	face font.Face
+	has  func(r rune) bool // reports whether the font has a glyph of the rune
+}
+
+// newGlyphSource parses the truetype or OpenType font for rasterization
+// of glyphs at the font scale in points.
+func newGlyphSource(data []byte, scale int32) (*glyphSource, error) {
+	if isOpentype(data) {
+		otf, err := opentype.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		face, err := opentype.NewFace(otf, &opentype.FaceOptions{
+			Size:    float64(scale),
+			DPI:     72,
+			Hinting: font.HintingNone,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var buf sfnt.Buffer
+		return &glyphSource{face: face, has: func(r rune) bool {
+			i, err := otf.GlyphIndex(&buf, r)
+			return err == nil && i != 0
+		}}, nil
+	}
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	face := truetype.NewFace(ttf, &truetype.Options{Size: float64(scale), DPI: 72})
+	return &glyphSource{face: face, has: func(r rune) bool {
+		return ttf.Index(r) != 0
+	}}, nil
+}
+
+// glyph returns the image and the metrics of the glyph of the rune,
+// or false if the font has no glyph of the rune.
+func (s *glyphSource) glyph(r rune) (*image.Alpha, GlyphMetrics, bool) {
+	if !s.has(r) {
+		return nil, GlyphMetrics{}, false
+	}
+	// the dot is on the baseline, Y of the rectangle goes down
+	dr, mask, mp, adv, ok := s.face.Glyph(fixed.Point26_6{}, r)
+	if !ok {
+		return nil, GlyphMetrics{}, false
+	}
+	img := image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+	draw.Draw(img, img.Bounds(), mask, mp, draw.Src)
+	return img, GlyphMetrics{
+		BearingX: int32(dr.Min.X),
+		BearingY: int32(-dr.Min.Y),
+		Advance:  int32(adv.Round()),
+	}, true
+}
+
+// LoadTruetypeDynamic loads a truetype or OpenType font from the data,
+// which rasterizes glyphs lazily: runes of the ranges are rasterized up
+// front as by LoadTruetypeRanges, ASCII if there are no ranges, every
+// other rune of the font is rasterized and added to the sprite sheet
+// the first time it is drawn or measured. This makes full Unicode text
+// practical without a gigantic sprite sheet.
+//
+// Glyphs of runes out of the ranges are cropped by glyph cells of
+// the ranges. Runes the font has no glyphs for are passed to Fallback.
+func LoadTruetypeDynamic(data []byte, scale int32, ranges ...RuneRange) (*Font, error) {
+	if len(ranges) == 0 {
+		ranges = ASCII
+	}
+	src, err := newGlyphSource(data, scale)
+	if err != nil {
+		return nil, err
+	}
+	f, err := LoadTruetypeRanges(data, scale, ranges...)
+	if err != nil {
+		return nil, err
+	}
+	f.source = src
+	return f, nil
+}
+
+// sourceGlyph adds the glyph of the rune rasterized by the source of
+// the font, or returns nil if there is no such glyph.
+func (f *Font) sourceGlyph(r rune) *Glyph {
+	if f.source == nil {
+		return nil
+	}
+	img, m, ok := f.source.glyph(r)
+	if !ok {
+		return nil
+	}
+	countCache(false)
+	top := f.cellHeight(img) - int(f.Config.Baseline) - int(m.BearingY)
+	g, err := f.addImage(img, top, int(m.BearingX), int(m.Advance))
+	if err != nil {
+		warn("glsymbol: glyph is not added", "rune", r, "error", err)
+		return nil
+	}
+	f.addedGlyph(r, g)
+	f.evictable(r)
+	return g
+}
+
+// Prewarm rasterizes glyphs of all runes of the texts which are not
+// in the sprite sheet yet, for example during a loading screen, so
+// the first frame showing the texts does not wait for rasterization on
+// demand or Fallback. It returns the amount of added glyphs.
+//
+// Prewarm makes no OpenGL calls, but as other methods of the font it
+// must not run concurrently with them.
+func (f *Font) Prewarm(texts []string) int {
+	n := len(f.added)
+	for _, s := range texts {
+		for _, r := range s {
+			f.glyph(r)
+		}
+	}
+	return len(f.added) - n
+}
@@ -0,0 +1,61 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestDynamicGlyphs(t *testing.T) {
+	img, fc, err := rasterizeData(context.Background(), goregular.TTF, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.source, err = newGlyphSource(goregular.TTF, 16); err != nil {
+		t.Fatal(err)
+	}
+	_, ref, err := rasterizeData(context.Background(), goregular.TTF, 16, Cyrillic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range "ЖЯж" {
+		g := f.glyph(r)
+		if g.Advance != ref.Glyphs[ref.Index(r)].Advance {
+			t.Errorf("advance of %q is %d, expect %d", r, g.Advance, ref.Glyphs[ref.Index(r)].Advance)
+		}
+		if len(g.BitmapData) == 0 || g.Height != f.MaxGlyphHeight {
+			t.Errorf("glyph of %q: %+v", r, g)
+		}
+	}
+	if f.glyph('Ж') != f.glyph('Ж') || len(f.addedRunes()) != 3 {
+		t.Errorf("glyphs are rasterized again: %q", f.addedRunes())
+	}
+	// Go fonts have no CJK glyphs
+	if g := f.glyph('中'); g != f.unknownGlyph() {
+		t.Errorf("glyph of missing rune %+v", g)
+	}
+	f.Release()
+	if f.source != nil {
+		t.Errorf("source is not released")
+	}
+}
+
+func TestPrewarm(t *testing.T) {
+	f := testFont()
+	var calls int
+	f.Fallback = func(r rune) image.Image {
+		calls++
+		return image.NewAlpha(image.Rect(0, 0, 4, 4))
+	}
+	if n := f.Prewarm([]string{"Привет", "мир", "ok"}); n != 7 {
+		t.Errorf("%d glyphs are added", n)
+	}
+	f.advanceSize("Привет мир")
+	if n := f.Prewarm([]string{"мир"}); n != 0 || calls != 7 {
+		t.Errorf("%d glyphs are added, %d calls", n, calls)
+	}
+}
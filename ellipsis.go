@@ -0,0 +1,46 @@
+package glsymbol
+
+// ellipsisText returns the ellipsis drawn by the font: '…' if the font
+// has it, otherwise "...".
+func (f *Font) ellipsisText() string {
+	if c := f.Config; c != nil && c.Has('…') {
+		return "…"
+	}
+	return "..."
+}
+
+// Ellipsize returns the single line string cut to fit the width with an
+// ellipsis at the end, or the string itself if it fits. If not even the
+// ellipsis fits, the result is empty.
+func (f *Font) Ellipsize(str string, width int32) string {
+	if f.advanceSize(str) <= width {
+		return str
+	}
+	dots := f.ellipsisText()
+	avail := width - f.advanceSize(dots)
+	if avail < 0 {
+		return ""
+	}
+	var w int32
+	if f.Shaper != nil {
+		glyphs, offsets := f.shape(str)
+		for _, g := range glyphs {
+			w += g.Advance
+			if avail < w {
+				return str[:offsets[g.Cluster]] + dots
+			}
+		}
+		return str
+	}
+	prev := rune(-1)
+	for i := 0; i < len(str); {
+		c := f.cluster(str[i:])
+		w += c.step + f.kerning(prev, c.first)
+		prev = c.last
+		if avail < w {
+			return str[:i] + dots
+		}
+		i += c.bytes
+	}
+	return str
+}
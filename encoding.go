@@ -0,0 +1,269 @@
+package glsymbol
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"unicode/utf8"
+)
+
+// JSON representation of font metadata.
+//
+// Configurations of bitmap fonts are usually written by hand, so decoding
+// validates every value and reports the position of a wrong glyph.
+// Runes may be written as numbers or as single character strings:
+//
+//	{
+//		"low": "A",
+//		"high": 66,
+//		"baseline": 3,
+//		"glyphs": [
+//			{"x": 0, "y": 0, "width": 8, "height": 12, "advance": 8},
+//			{"x": 8, "y": 0, "width": 8, "height": 12, "advance": 8, "leftBearing": -1, "rightBearing": 1}
+//		]
+//	}
+//
+// A font with several segments of runes lists them in "ranges", "low"
+// and "high" may be omitted then:
+//
+//	{
+//		"ranges": [{"low": " ", "high": "~"}, {"low": "А", "high": "я"}],
+//		"glyphs": [...]
+//	}
+//
+// Glyph bitmap data is derived from the sprite sheet and is never encoded.
+
+type glyphJSON struct {
+	X       int32 `json:"x"`
+	Y       int32 `json:"y"`
+	Width   int32 `json:"width"`
+	Height  int32 `json:"height"`
+	Advance int32 `json:"advance"`
+
+	LeftBearing   int32 `json:"leftBearing,omitempty"`
+	RightBearing  int32 `json:"rightBearing,omitempty"`
+	TopBearing    int32 `json:"topBearing,omitempty"`
+	BottomBearing int32 `json:"bottomBearing,omitempty"`
+
+	VerticalAdvance int32 `json:"verticalAdvance,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (g Glyph) MarshalJSON() ([]byte, error) {
+	return json.Marshal(glyphJSON{
+		X:       g.X,
+		Y:       g.Y,
+		Width:   g.Width,
+		Height:  g.Height,
+		Advance: g.Advance,
+
+		LeftBearing:   g.LeftBearing,
+		RightBearing:  g.RightBearing,
+		TopBearing:    g.TopBearing,
+		BottomBearing: g.BottomBearing,
+
+		VerticalAdvance: g.VerticalAdvance,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *Glyph) UnmarshalJSON(data []byte) error {
+	var v glyphJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	ng := Glyph{
+		X:       v.X,
+		Y:       v.Y,
+		Width:   v.Width,
+		Height:  v.Height,
+		Advance: v.Advance,
+
+		LeftBearing:   v.LeftBearing,
+		RightBearing:  v.RightBearing,
+		TopBearing:    v.TopBearing,
+		BottomBearing: v.BottomBearing,
+
+		VerticalAdvance: v.VerticalAdvance,
+	}
+	if err := ng.validate(); err != nil {
+		return err
+	}
+	*g = ng
+	return nil
+}
+
+// validate checks glyph metrics which do not depend on the sprite sheet.
+func (g Glyph) validate() error {
+	switch {
+	case g.Width < 0:
+		return fmt.Errorf("negative width %d", g.Width)
+	case g.Height < 0:
+		return fmt.Errorf("negative height %d", g.Height)
+	case g.Advance < 0:
+		return fmt.Errorf("negative advance %d", g.Advance)
+	case g.VerticalAdvance < 0:
+		return fmt.Errorf("negative vertical advance %d", g.VerticalAdvance)
+	case g.TopBearing < 0 || g.BottomBearing < 0:
+		return fmt.Errorf("negative bearings %d and %d above and below the bitmap", g.TopBearing, g.BottomBearing)
+	case g.step() < 0:
+		return fmt.Errorf("bearings %d and %d move the pen back", g.LeftBearing, g.RightBearing)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Charset) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]Glyph(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Charset) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("glsymbol: charset must be an array of glyphs: %v", err)
+	}
+	nc := make(Charset, len(raw))
+	for i := range raw {
+		if err := json.Unmarshal(raw[i], &nc[i]); err != nil {
+			return fmt.Errorf("glsymbol: glyph %d: %v", i, err)
+		}
+	}
+	*c = nc
+	return nil
+}
+
+type fontConfigJSON struct {
+	Low      jsonRune `json:"low"`
+	High     jsonRune `json:"high"`
+	Baseline int32    `json:"baseline,omitempty"`
+	Glyphs   Charset  `json:"glyphs"`
+
+	Ranges  []runeRangeJSON `json:"ranges,omitempty"`
+	Kerning []kernPairJSON  `json:"kerning,omitempty"`
+}
+
+type kernPairJSON struct {
+	Left   jsonRune `json:"left"`
+	Right  jsonRune `json:"right"`
+	Adjust int32    `json:"adjust"`
+}
+
+type runeRangeJSON struct {
+	Low  jsonRune `json:"low"`
+	High jsonRune `json:"high"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (fc FontConfig) MarshalJSON() ([]byte, error) {
+	v := fontConfigJSON{
+		Low:      jsonRune(fc.Low),
+		High:     jsonRune(fc.High),
+		Baseline: fc.Baseline,
+		Glyphs:   fc.Glyphs,
+	}
+	for _, rg := range fc.Ranges {
+		v.Ranges = append(v.Ranges, runeRangeJSON{jsonRune(rg.Low), jsonRune(rg.High)})
+	}
+	for _, p := range fc.Kerning {
+		v.Kerning = append(v.Kerning, kernPairJSON{jsonRune(p.Left), jsonRune(p.Right), p.Adjust})
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (fc *FontConfig) UnmarshalJSON(data []byte) error {
+	var v fontConfigJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	nc := FontConfig{
+		Low:      rune(v.Low),
+		High:     rune(v.High),
+		Baseline: v.Baseline,
+		Glyphs:   v.Glyphs,
+	}
+	for _, rg := range v.Ranges {
+		nc.Ranges = append(nc.Ranges, RuneRange{rune(rg.Low), rune(rg.High)})
+	}
+	for _, p := range v.Kerning {
+		nc.Kerning = append(nc.Kerning, KernPair{rune(p.Left), rune(p.Right), p.Adjust})
+	}
+	if len(nc.Ranges) != 0 && nc.Low == 0 && nc.High == 0 {
+		nc.Low, nc.High = nc.Ranges[0].Low, nc.Ranges[len(nc.Ranges)-1].High
+	}
+	if err := nc.Validate(image.Rectangle{}); err != nil {
+		return err
+	}
+	*fc = nc
+	return nil
+}
+
+// Validate checks the font configuration for consistency.
+// If bounds is not empty, every glyph must be located inside it.
+func (fc *FontConfig) Validate(bounds image.Rectangle) error {
+	if fc.High < fc.Low {
+		return fmt.Errorf("glsymbol: high rune %q is less than low rune %q",
+			fc.High, fc.Low)
+	}
+	if len(fc.Ranges) != 0 {
+		if err := validateRanges(fc.Ranges); err != nil {
+			return err
+		}
+		if first, last := fc.Ranges[0], fc.Ranges[len(fc.Ranges)-1]; fc.Low != first.Low || fc.High != last.High {
+			return fmt.Errorf("glsymbol: range %q..%q does not match ranges %q..%q",
+				fc.Low, fc.High, first.Low, last.High)
+		}
+	}
+	expect := 0
+	for _, rg := range fc.ranges() {
+		expect += rg.Len()
+	}
+	if len(fc.Glyphs) != expect {
+		return fmt.Errorf("glsymbol: range %q..%q needs %d glyphs, got %d",
+			fc.Low, fc.High, expect, len(fc.Glyphs))
+	}
+	for i, g := range fc.Glyphs {
+		r := fc.RuneAt(i)
+		if err := g.validate(); err != nil {
+			return fmt.Errorf("glsymbol: glyph %d (%q): %v", i, r, err)
+		}
+		if bounds.Empty() {
+			continue
+		}
+		rect := image.Rect(int(g.X), int(g.Y), int(g.X+g.Width), int(g.Y+g.Height))
+		if !rect.In(bounds) {
+			return fmt.Errorf("glsymbol: glyph %d (%q): rectangle %v is outside of sprite sheet %v",
+				i, r, rect, bounds)
+		}
+	}
+	return nil
+}
+
+// jsonRune is a rune encoded as a number and decoded
+// from a number or a single character string.
+type jsonRune rune
+
+func (r *jsonRune) UnmarshalJSON(data []byte) error {
+	var n int32
+	if err := json.Unmarshal(data, &n); err == nil {
+		if n < 0 || !utf8.ValidRune(n) {
+			return fmt.Errorf("glsymbol: invalid rune %d", n)
+		}
+		*r = jsonRune(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("glsymbol: rune must be a number or a string, got %s", data)
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return fmt.Errorf("glsymbol: rune string %q must hold exactly one character", s)
+	}
+	c, _ := utf8.DecodeRuneInString(s)
+	*r = jsonRune(c)
+	return nil
+}
@@ -0,0 +1,70 @@
+package glsymbol
+
+import (
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestFontConfigJSON(t *testing.T) {
+	_, config := testAtlas()
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc FontConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Low != 'a' || fc.High != 'b' || len(fc.Glyphs) != 2 || fc.Glyphs[1].Advance != 7 {
+		t.Errorf("not same: %s", data)
+	}
+
+	// runes as strings
+	in := `{"low":"A","high":"B","glyphs":[
+		{"x":0,"y":0,"width":8,"height":8,"advance":8},
+		{"x":8,"y":0,"width":8,"height":8,"advance":8,"leftBearing":-2,"rightBearing":-1,"topBearing":3,"bottomBearing":1}]}`
+	if err := json.Unmarshal([]byte(in), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Low != 'A' || fc.High != 'B' {
+		t.Errorf("unexpected range: %q..%q", fc.Low, fc.High)
+	}
+	if g := fc.Glyphs[1]; g.LeftBearing != -2 || g.RightBearing != -1 || g.TopBearing != 3 || g.BottomBearing != 1 {
+		t.Errorf("unexpected bearings: %+v", g)
+	}
+}
+
+func TestFontConfigJSONErrors(t *testing.T) {
+	tcs := []struct {
+		in, err string
+	}{
+		{`{"low":66,"high":65,"glyphs":[]}`, "less than low"},
+		{`{"low":65,"high":66,"glyphs":[{}]}`, "needs 2 glyphs, got 1"},
+		{`{"low":65,"high":65,"glyphs":[{"width":-1}]}`, "glyph 0: negative width"},
+		{`{"low":65,"high":65,"glyphs":[{"width":4,"leftBearing":-3,"rightBearing":-2}]}`, "move the pen back"},
+		{`{"low":65,"high":65,"glyphs":[{"width":4,"bottomBearing":-1}]}`, "negative bearings"},
+		{`{"low":"AB","high":65,"glyphs":[]}`, "exactly one character"},
+		{`{"low":true,"high":65,"glyphs":[]}`, "number or a string"},
+		{`{"low":65,"high":65,"glyphs":{}}`, "array of glyphs"},
+	}
+	for _, tc := range tcs {
+		var fc FontConfig
+		err := json.Unmarshal([]byte(tc.in), &fc)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+		}
+	}
+}
+
+func TestFontConfigValidateBounds(t *testing.T) {
+	_, config := testAtlas()
+	if err := config.Validate(image.Rect(0, 0, 16, 8)); err != nil {
+		t.Fatal(err)
+	}
+	err := config.Validate(image.Rect(0, 0, 12, 8))
+	if err == nil || !strings.Contains(err.Error(), "glyph 1 ('b')") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package glsymbol
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Export of recorded text into vector formats, so text of screenshots
+// stays sharp at any zoom and can be searched and copied. Strings are
+// stretched to their measured widths, so the layout matches the screen
+// even with a different font.
+
+// fontSize returns the size of the font for vector formats.
+func (f *Font) fontSize() float32 {
+	if f.size != 0 {
+		return float32(f.size)
+	}
+	return float32(f.MaxGlyphHeight)
+}
+
+// WriteSVG writes the records as an SVG image of the given size in
+// pixels. The text is set in the font family, for example "monospace".
+func (r *Recorder) WriteSVG(w io.Writer, width, height int, family string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	for _, rec := range r.Records {
+		fmt.Fprintf(&b, `<text x="%g" y="%g" font-family="%s" font-size="%g" %s`,
+			rec.Box.X, rec.Baseline, xmlEscape(family), rec.Font.fontSize(), svgFill(rec.Color))
+		if 0 < rec.Box.Width {
+			fmt.Fprintf(&b, ` textLength="%g" lengthAdjust="spacingAndGlyphs"`, rec.Box.Width)
+		}
+		b.WriteString(` xml:space="preserve">`)
+		if len(rec.Colors) == 0 {
+			b.WriteString(xmlEscape(rec.Text))
+		} else {
+			n := 0
+			for _, c := range rec.Text {
+				if n < len(rec.Colors) {
+					fmt.Fprintf(&b, `<tspan %s>%s</tspan>`, svgFill(rec.Colors[n]), xmlEscape(string(c)))
+				} else {
+					b.WriteString(xmlEscape(string(c)))
+				}
+				n++
+			}
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// svgFill returns SVG attributes of the color.
+func svgFill(c color.RGBA) string {
+	s := fmt.Sprintf(`fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf(` fill-opacity="%.3g"`, float32(c.A)/0xff)
+	}
+	return s
+}
+
+var xmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+func xmlEscape(s string) string {
+	return xmlReplacer.Replace(s)
+}
+
+// WritePDF writes the records as a single page PDF document of the given
+// size in pixels, one pixel is one point. The text is set in Courier,
+// runes outside of Latin-1 are written as '?'.
+func (r *Recorder) WritePDF(w io.Writer, width, height int) error {
+	var content bytes.Buffer
+	content.WriteString("BT\n")
+	for _, rec := range r.Records {
+		size := rec.Font.fontSize()
+		fmt.Fprintf(&content, "/F1 %g Tf\n", size)
+		runes := utf8.RuneCountInString(rec.Text)
+		if 0 < runes && 0 < rec.Box.Width {
+			// Courier glyphs are 0.6 em wide
+			fmt.Fprintf(&content, "%.2f Tz\n", 100*rec.Box.Width/(0.6*size*float32(runes)))
+		}
+		base := float32(height) - rec.Baseline
+		if len(rec.Colors) == 0 {
+			fmt.Fprintf(&content, "%s 1 0 0 1 %g %g Tm (%s) Tj\n",
+				pdfColor(rec.Color), rec.Box.X, base, pdfString(rec.Text))
+			continue
+		}
+		// runes of different colors are placed one by one
+		rec.Font.EachGlyph(rec.Text, func(g PlacedGlyph) bool {
+			c := rec.Color
+			if g.Index < len(rec.Colors) {
+				c = rec.Colors[g.Index]
+			}
+			fmt.Fprintf(&content, "%s 1 0 0 1 %g %g Tm (%s) Tj\n",
+				pdfColor(c), rec.Box.X+float32(g.X), base, pdfString(g.Text))
+			return true
+		})
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", width, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()),
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfColor returns the PDF operator setting the fill color.
+func pdfColor(c color.RGBA) string {
+	return fmt.Sprintf("%.3g %.3g %.3g rg", float32(c.R)/0xff, float32(c.G)/0xff, float32(c.B)/0xff)
+}
+
+// pdfString escapes the string for a PDF literal string in Latin-1.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c < 0x20 || 0xff < c:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(c))
+		}
+	}
+	return b.String()
+}
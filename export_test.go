@@ -0,0 +1,120 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testRecorder() *Recorder {
+	f := testFont()
+	f.Config.Baseline = 4
+	r := new(Recorder)
+	rec := f.newRecord(10, 20, "a<b", 100)
+	rec.Color = color.RGBA{255, 0, 0, 255}
+	r.Records = append(r.Records, rec)
+	rec = f.newRecord(0, 0, "(i)", 100)
+	rec.Colors = []color.RGBA{{0, 0, 255, 128}}
+	r.Records = append(r.Records, rec)
+	return r
+}
+
+func TestNewRecord(t *testing.T) {
+	f := testFont()
+	f.Config.Baseline = 4
+	for _, tc := range []struct {
+		origin Origin
+		box    Rect
+		base   float32
+	}{
+		{OriginBottomLeft, Rect{10, 64, 16, 16}, 76},
+		{OriginTopLeft, Rect{10, 20, 16, 16}, 32},
+	} {
+		SetOrigin(tc.origin)
+		rec := f.newRecord(10, 20, "ab", 100)
+		SetOrigin(OriginBottomLeft)
+		if rec.Box != tc.box || rec.Baseline != tc.base {
+			t.Errorf("origin %d: box %v, baseline %v", tc.origin, rec.Box, rec.Baseline)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := testRecorder().WriteSVG(&b, 200, 100, "monospace"); err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+	for _, s := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100"`,
+		`<text x="10" y="76" font-family="monospace" font-size="16" fill="#ff0000" textLength="24"`,
+		`>a&lt;b</text>`,
+		`<tspan fill="#0000ff" fill-opacity="0.502">(</tspan>i)</text>`,
+	} {
+		if !strings.Contains(svg, s) {
+			t.Errorf("no %q in\n%s", s, svg)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var b bytes.Buffer
+	if err := testRecorder().WritePDF(&b, 200, 100); err != nil {
+		t.Fatal(err)
+	}
+	pdf := b.String()
+	for _, s := range []string{"%PDF-1.4", "/MediaBox [0 0 200 100]", "(a<b) Tj", `(\() Tj`, "%%EOF"} {
+		if !strings.Contains(pdf, s) {
+			t.Errorf("no %q in\n%s", s, pdf)
+		}
+	}
+	// every object is at its offset in the cross-reference table
+	xref := strings.Index(pdf, "xref\n")
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf[xref:], -1)
+	if len(offsets) != 5 {
+		t.Fatalf("%d objects in xref", len(offsets))
+	}
+	for i, m := range offsets {
+		off, _ := strconv.Atoi(m[1])
+		if !strings.HasPrefix(pdf[off:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Errorf("object %d is not at offset %d", i+1, off)
+		}
+	}
+	if !strings.HasSuffix(pdf, fmt.Sprintf("startxref\n%d\n%%%%EOF\n", xref)) {
+		t.Errorf("wrong startxref")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := testRecorder()
+	r.Records[0].Caller = "main.go:10"
+	var b bytes.Buffer
+	if err := r.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(b.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("%d records", len(out))
+	}
+	if out[0]["caller"] != "main.go:10" || out[0]["color"] != "#ff0000ff" || out[0]["text"] != "a<b" {
+		t.Errorf("unexpected record %v", out[0])
+	}
+	if colors, ok := out[1]["colors"].([]any); !ok || len(colors) != 1 || colors[0] != "#0000ff80" {
+		t.Errorf("unexpected colors %v", out[1]["colors"])
+	}
+}
+
+func TestCaller(t *testing.T) {
+	c := caller()
+	if !strings.Contains(c, "export_test.go:") {
+		t.Errorf("caller %q", c)
+	}
+}
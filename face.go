@@ -0,0 +1,191 @@
+package glsymbol
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/opentype"
+)
+
+// A Face is a parsed TrueType or OpenType font, which provides fonts of
+// different sizes for the same range of runes.
+// Fonts are rasterized on first request and cached.
+type Face struct {
+	// Budget, if not zero, limits memory of sprite sheets of all fonts
+	// of the face in bytes. Fonts of the least recently requested sizes
+	// are released to make place for a new size, so fonts should be
+	// requested by Font every frame instead of being kept. If that is not
+	// enough, Font fails with *BudgetError.
+	Budget int64
+
+	data   []byte
+	ttf    *truetype.Font
+	otf    *opentype.Font // font with CFF outlines, ttf is nil
+	ranges []RuneRange
+	fonts  map[int32]*Font
+	used   map[int32]uint64 // last request of fonts, see Budget
+	tick   uint64
+}
+
+// NewFace parses a truetype or OpenType font from the given stream.
+// The low and high values determine the rune limits of all fonts
+// provided by the face, see LoadTruetype.
+func NewFace(r io.Reader, low, high rune) (*Face, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewFaceBytes(data, low, high)
+}
+
+// NewFaceBytes parses a truetype or OpenType font from the data as
+// NewFace, for fonts which are already in memory. The face keeps the
+// data, which must not be modified while the face is used.
+func NewFaceBytes(data []byte, low, high rune) (*Face, error) {
+	var err error
+	fa := &Face{
+		data:   data,
+		ranges: []RuneRange{{low, high}},
+		fonts:  map[int32]*Font{},
+	}
+	if isOpentype(data) {
+		fa.otf, err = opentype.Parse(data)
+	} else {
+		fa.ttf, err = truetype.Parse(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fa, nil
+}
+
+// Font returns the font of the face for the given font scale in points.
+func (fa *Face) Font(scale int32) (*Font, error) {
+	if fa.used == nil {
+		fa.used = make(map[int32]uint64)
+	}
+	fa.tick++
+	if f, ok := fa.fonts[scale]; ok {
+		countCache(true)
+		fa.used[scale] = fa.tick
+		return f, nil
+	}
+	countCache(false)
+	var (
+		img *image.RGBA
+		fc  *FontConfig
+		err error
+	)
+	if fa.otf != nil {
+		img, fc, err = rasterizeOpentype(context.Background(), fa.otf, scale, fa.ranges, 0)
+	} else {
+		img, fc, err = rasterizeTruetype(context.Background(), fa.ttf, scale, fa.ranges, 0)
+	}
+	if err == nil {
+		err = addKerning(fc, fa.data, fa.ttf, fa.otf, scale)
+	}
+	var subst *substitutions
+	if err == nil {
+		subst, err = newSubstitutions(fa.data, scale, fc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = fa.fit(int64(len(img.Pix))); err != nil {
+		return nil, err
+	}
+	f, err := loadFont(img, fc)
+	if err != nil {
+		return nil, err
+	}
+	f.size = scale
+	f.subst = subst
+	fa.fonts[scale] = f
+	fa.used[scale] = fa.tick
+	return f, nil
+}
+
+// fit releases fonts of the least recently requested sizes until
+// a sheet of need bytes fits the budget.
+func (fa *Face) fit(need int64) error {
+	if fa.Budget == 0 {
+		return nil
+	}
+	sheets := func() (n int64) {
+		for _, f := range fa.fonts {
+			if f.img != nil {
+				n += int64(len(f.img.Pix))
+			}
+		}
+		return
+	}
+	for fa.Budget < sheets()+need && len(fa.fonts) != 0 {
+		var oldest int32
+		first := true
+		for scale := range fa.fonts {
+			if first || fa.used[scale] < fa.used[oldest] {
+				oldest, first = scale, false
+			}
+		}
+		warn("glsymbol: font is evicted from face", "scale", oldest)
+		fa.fonts[oldest].Release()
+		delete(fa.fonts, oldest)
+		delete(fa.used, oldest)
+	}
+	if n := sheets() + need; fa.Budget < n {
+		return &BudgetError{Budget: fa.Budget, Need: n}
+	}
+	return nil
+}
+
+// Release releases all fonts of the face.
+func (fa *Face) Release() {
+	for scale, f := range fa.fonts {
+		f.Release()
+		delete(fa.fonts, scale)
+	}
+	fa.used = nil
+}
+
+// RenderWaterfall draws the same text at a ladder of font sizes.
+// Every line is labeled by its size and drawn with the face font of
+// that size. The top edge of the first line is at the y coordinate,
+// every next line is placed below the previous one.
+func RenderWaterfall(face *Face, sizes []int, x, y float32, text string) error {
+	var gutter int32
+	for _, size := range sizes {
+		f, err := face.Font(int32(size))
+		if err != nil {
+			return fmt.Errorf("size %d: %v", size, err)
+		}
+		sp := startSpan(StageLayout)
+		if w := f.advanceSize(waterfallLabel(size)); gutter < w {
+			gutter = w
+		}
+		sp.end()
+	}
+	for _, size := range sizes {
+		f, err := face.Font(int32(size))
+		if err != nil {
+			return fmt.Errorf("size %d: %v", size, err)
+		}
+		ly := f.lineY(y)
+		label := waterfallLabel(size)
+		if err := f.Printf(f.startX(x, label), ly, label); err != nil {
+			return err
+		}
+		if err := f.Printf(f.startX(x+float32(gutter), text), ly, text); err != nil {
+			return err
+		}
+		y = below(y, float32(f.lineHeight()))
+	}
+	return nil
+}
+
+func waterfallLabel(size int) string {
+	return strconv.Itoa(size) + " "
+}
@@ -0,0 +1,26 @@
+package glsymbol
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestNewFaceBytes(t *testing.T) {
+	fa, err := NewFaceBytes(goregular.TTF, 32, 127)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fa.ttf == nil || fa.otf != nil {
+		t.Errorf("truetype font is not parsed by freetype")
+	}
+	if _, err := NewFaceBytes([]byte("OTTO"), 32, 127); err == nil {
+		t.Errorf("no error for invalid OpenType font")
+	}
+	if _, err := NewFaceBytes(nil, 32, 127); err == nil {
+		t.Errorf("no error for empty data")
+	}
+	if _, err := LoadTruetypeBytes([]byte("invalid"), 16, 32, 127); err == nil {
+		t.Errorf("no error for invalid font")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !glsymbol_faults
+
+package glsymbol
+
+import "github.com/go-gl/gl/v2.1/gl"
+
+// fault returns an injected error code for the operation.
+// Error injection is available with the glsymbol_faults build tag only.
+func fault(op string) uint32 {
+	return gl.NO_ERROR
+}
+
+// faultError returns an injected error for the operation, which is
+// never the case without the glsymbol_faults build tag.
+func faultError(op string) error {
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build glsymbol_faults
+
+package glsymbol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// errInjected is the error injected by tests of error points.
+var errInjected = errors.New("injected")
+
+func TestFaultSheetPages(t *testing.T) {
+	f := testSheetFont()
+	injectError("sheetPages", errInjected)
+	if pages, err := f.sheetPages(); !errors.Is(err, errInjected) || pages != nil {
+		t.Errorf("pages %v, error %v", pages, err)
+	}
+	if len(f.pages) != 0 {
+		t.Errorf("pages are kept after the error")
+	}
+}
+
+func TestFaultAddImage(t *testing.T) {
+	f := testSheetFont()
+	sheet := f.img.Bounds()
+	img := image.NewAlpha(image.Rect(0, 0, 6, 6))
+	injectError("addImage", errInjected)
+	if err := f.AddGlyph(0x416, img, GlyphMetrics{Advance: 6}); !errors.Is(err, errInjected) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.img.Bounds() != sheet || f.added[0x416] != nil {
+		t.Errorf("sheet is changed by the failed glyph")
+	}
+	// next glyph is not affected
+	if err := f.AddGlyph(0x416, img, GlyphMetrics{Advance: 6}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaultReadAtlas(t *testing.T) {
+	img, config := testAtlas()
+	var buf bytes.Buffer
+	if err := EncodeAtlas(&buf, img, config); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	injectError("readAtlas", errInjected)
+	if _, _, err := readAtlas(bytes.NewReader(data)); !errors.Is(err, errInjected) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, _, err := readAtlas(bytes.NewReader(data)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFaultRasterize(t *testing.T) {
+	img, fc, err := rasterizeData(context.Background(), goregular.TTF, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.subst, err = newSubstitutions(goregular.TTF, 16, fc); err != nil {
+		t.Fatal(err)
+	}
+	id, err := f.subst.font.GlyphIndex(&f.subst.buf, 'é')
+	if err != nil || id == 0 {
+		t.Fatalf("glyph index %d: %v", id, err)
+	}
+	f.Shaper = idShaper(id)
+	injectError("rasterize", errInjected)
+	// the glyph placed by the shaper is unknown, text is still placed
+	if g := f.ShapeParagraphs("a", 100, ParagraphStyle{}); len(g) != 1 {
+		t.Errorf("shaped glyphs %+v", g)
+	}
+	if g, ok := f.subst.glyphs[id]; !ok || g != nil {
+		t.Errorf("glyph %v is added", g)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build glsymbol_faults
+
+package glsymbol
+
+import "github.com/go-gl/gl/v2.1/gl"
+
+// faults holds error codes injected for operations.
+var faults = map[string]uint32{}
+
+// faultErrors holds errors injected for operations without OpenGL calls.
+var faultErrors = map[string]error{}
+
+// injectFault makes the next error check of the operation report
+// the given error code, for example gl.OUT_OF_MEMORY, as if the
+// driver failed. It is used by tests of error handling:
+//
+//	go test -tags glsymbol_faults
+func injectFault(op string, code uint32) {
+	faults[op] = code
+}
+
+// fault returns and forgets an injected error code for the operation.
+func fault(op string) uint32 {
+	code, ok := faults[op]
+	if !ok {
+		return gl.NO_ERROR
+	}
+	delete(faults, op)
+	return code
+}
+
+// injectError makes the next call of the operation fail with the error
+// before it changes anything. Operations with error points are:
+//
+//	sheetPages  upload of the sprite sheet as textures
+//	addImage    packing of a glyph into the sprite sheet
+//	readAtlas   decoding of an atlas file
+//	rasterize   rasterization of a glyph placed by a shaper
+//
+// They need no OpenGL context, so tests run without a window.
+func injectError(op string, err error) {
+	faultErrors[op] = err
+}
+
+// faultError returns and forgets an injected error for the operation.
+func faultError(op string) error {
+	err := faultErrors[op]
+	delete(faultErrors, op)
+	return err
+}
@@ -0,0 +1,40 @@
+//go:build glsymbol_faults
+
+package glsymbol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+func TestFaultLoad(t *testing.T) {
+	defer initWindow(t)()
+
+	injectFault("loadFont", gl.OUT_OF_MEMORY)
+	font, err := DefaultFont()
+	var ge *GLError
+	if !errors.As(err, &ge) || ge.Code != gl.OUT_OF_MEMORY || ge.Op != "loadFont" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if font != nil {
+		t.Errorf("font is returned with error")
+	}
+
+	// next load is not affected
+	font, err = DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	injectFault("Printf", gl.INVALID_OPERATION)
+	err = font.Printf(10, 10, "Hello")
+	if !errors.As(err, &ge) || ge.Code != gl.INVALID_OPERATION {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err = font.Printf(10, 10, "Hello"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package glsymbol
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Fisheye describes magnification of glyphs near a point, like in a dock
+// of application icons. It makes dense labels, for example of a timeline,
+// readable near the pointer.
+type Fisheye struct {
+	Focus  float32 // x coordinate of the center of magnification
+	Radius float32 // distance from the focus where magnification ends
+	Scale  float32 // magnification at the focus, for example 2
+}
+
+// scale returns the magnification at the distance from the focus.
+func (fe Fisheye) scale(d float32) float32 {
+	if fe.Radius <= 0 || fe.Scale <= 0 {
+		return 1
+	}
+	d = float32(math.Abs(float64(d)))
+	if fe.Radius <= d {
+		return 1
+	}
+	// smooth falloff to 1 at the radius
+	t := float32(math.Cos(float64(d/fe.Radius)*math.Pi)+1) / 2
+	return 1 + (fe.Scale-1)*t
+}
+
+// fisheyeBox is the place of a magnified glyph relative to the start
+// of the string: the offset of the left edge, the width, the height and
+// the distance from the bottom of the cell up to the bottom edge.
+type fisheyeBox struct {
+	x, width, height, lift float32
+}
+
+// layoutFisheye places glyphs of the string drawn at x magnified by fe.
+// Glyphs are scaled by the distance of their pen steps from the focus,
+// then all glyphs are narrowed by the same factor, so the total advance
+// of the string does not change.
+func (f *Font) layoutFisheye(x float32, str string, fe Fisheye) []fisheyeBox {
+	var (
+		scales []float32
+		total  float32
+		drawn  float32
+	)
+	f.EachGlyph(str, func(g PlacedGlyph) bool {
+		w := float32(g.Step)
+		s := fe.scale(x + float32(g.X-g.Glyph.LeftBearing) + w/2 - fe.Focus)
+		scales = append(scales, s)
+		total += w
+		drawn += w * s
+		return true
+	})
+	k := float32(1)
+	if 0 < drawn {
+		k = total / drawn
+	}
+	boxes := make([]fisheyeBox, 0, len(scales))
+	var pen float32
+	f.EachGlyph(str, func(g PlacedGlyph) bool {
+		s := scales[len(boxes)]
+		// bearings are scaled with the glyph
+		sx := s * k
+		boxes = append(boxes, fisheyeBox{
+			x:      pen + float32(g.Glyph.LeftBearing)*sx,
+			width:  float32(g.Glyph.Width) * sx,
+			height: float32(g.Glyph.Height) * s,
+			lift:   float32(g.Glyph.BottomBearing+g.Y) * s,
+		})
+		pen += float32(g.Step) * sx
+		return true
+	})
+	return boxes
+}
+
+// PrintfFisheye draws the string as Printf with glyphs near the focus
+// magnified. Magnified glyphs grow up from the bottom of glyph cells.
+// The string takes the same width as with Printf.
+//
+// Glyphs are drawn as textured quads with the current color, the texture
+// of the sprite sheet is created on the first call.
+func (f *Font) PrintfFisheye(x, y float32, str string, fe Fisheye) error {
+	sp := startSpan(StageDraw)
+	defer sp.end()
+
+	if !viewportVisible() {
+		return nil
+	}
+	f.record(x, y, str, nil)
+	pages, err := f.sheetPages()
+	if err != nil {
+		return err
+	}
+	boxes := f.layoutFisheye(x, str, fe)
+
+	gl.PushAttrib(gl.ENABLE_BIT | gl.TEXTURE_BIT | gl.COLOR_BUFFER_BIT)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.TexEnvi(gl.TEXTURE_ENV, gl.TEXTURE_ENV_MODE, gl.MODULATE)
+	page, drawn := -1, 0
+	f.EachGlyph(str, func(g PlacedGlyph) bool {
+		if !f.limit(str, g) {
+			return false
+		}
+		// runs of glyphs of a page are drawn by a single batch of quads
+		if p := pageOf(pages, g.Glyph); p != page {
+			if 0 <= page {
+				gl.End()
+			}
+			page = p
+			gl.BindTexture(gl.TEXTURE_2D, pages[p].tex)
+			gl.Begin(gl.QUADS)
+		}
+		b := boxes[drawn]
+		drawn++
+		u0, v0, u1, v1 := f.glyphTexCoords(pages[page], g.Glyph)
+		bottom := above(cellBottom(y, float32(f.MaxGlyphHeight)), b.lift)
+		top := above(bottom, b.height)
+		left, right := x+b.x, x+b.x+b.width
+		gl.TexCoord2f(u0, v1)
+		gl.Vertex2f(left, bottom)
+		gl.TexCoord2f(u1, v1)
+		gl.Vertex2f(right, bottom)
+		gl.TexCoord2f(u1, v0)
+		gl.Vertex2f(right, top)
+		gl.TexCoord2f(u0, v0)
+		gl.Vertex2f(left, top)
+		return true
+	})
+	if 0 <= page {
+		gl.End()
+	}
+	gl.PopAttrib()
+	return checkGLError("PrintfFisheye")
+}
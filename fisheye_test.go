@@ -0,0 +1,42 @@
+package glsymbol
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFisheye(t *testing.T) {
+	f := testFont()
+	str := "abcdefghij"
+	width := float32(f.advanceSize(str))
+
+	for _, fe := range []Fisheye{
+		{},
+		{Focus: 20, Radius: 16, Scale: 2},
+		{Focus: 80, Radius: 40, Scale: 3},
+		{Focus: -100, Radius: 10, Scale: 2},
+	} {
+		boxes := f.layoutFisheye(0, str, fe)
+		if len(boxes) != len(str) {
+			t.Fatalf("%+v: %d boxes", fe, len(boxes))
+		}
+		last := boxes[len(boxes)-1]
+		if total := last.x + last.width; math.Abs(float64(total-width)) > 1e-3 {
+			t.Errorf("%+v: total advance %v, expect %v", fe, total, width)
+		}
+	}
+
+	boxes := f.layoutFisheye(0, str, Fisheye{Focus: 20, Radius: 16, Scale: 2})
+	// the glyph with the center at the focus is the largest
+	if boxes[2].height != 32 {
+		t.Errorf("height at the focus %v", boxes[2].height)
+	}
+	for i, b := range boxes {
+		if i != 2 && boxes[2].width <= b.width {
+			t.Errorf("glyph %d is wider than the glyph at the focus", i)
+		}
+	}
+	if boxes[9].height != 16 {
+		t.Errorf("height far from the focus %v", boxes[9].height)
+	}
+}
@@ -0,0 +1,154 @@
+package glsymbol
+
+// A Widget receives keys when it has the keyboard focus.
+// HandleKey reports whether the key is used by the widget.
+type Widget interface {
+	HandleKey(k Key) bool
+}
+
+// A FocusManager keeps the keyboard focus of widgets. Widgets are
+// registered with their rectangles in tab order, usually every frame
+// after Reset, and the manager moves the focus by Tab, Shift+Tab and
+// arrow keys which are not used by the focused widget.
+type FocusManager struct {
+	items []focusItem
+	focus Widget
+}
+
+type focusItem struct {
+	w Widget
+	r Rect
+}
+
+// Reset removes all widgets, the focused widget keeps the focus if it
+// is registered again.
+func (m *FocusManager) Reset() {
+	m.items = m.items[:0]
+}
+
+// Register adds the widget placed in the rectangle, in the coordinates
+// of drawing functions, as the next widget in tab order.
+func (m *FocusManager) Register(w Widget, r Rect) {
+	m.items = append(m.items, focusItem{w: w, r: r})
+}
+
+// Focused returns the focused widget or nil.
+func (m *FocusManager) Focused() Widget {
+	if m.index(m.focus) < 0 {
+		return nil
+	}
+	return m.focus
+}
+
+// SetFocus focuses the widget, nil removes the focus.
+func (m *FocusManager) SetFocus(w Widget) {
+	m.focus = w
+}
+
+// FocusAt focuses the widget at the point, for example on a click,
+// and returns it or nil.
+func (m *FocusManager) FocusAt(x, y float32) Widget {
+	for i := len(m.items) - 1; 0 <= i; i-- {
+		if m.items[i].r.Contains(x, y) {
+			m.focus = m.items[i].w
+			return m.focus
+		}
+	}
+	return nil
+}
+
+func (m *FocusManager) index(w Widget) int {
+	if w == nil {
+		return -1
+	}
+	for i, it := range m.items {
+		if it.w == w {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleKey forwards the key to the focused widget or moves the focus.
+// Tab and Shift+Tab move it in tab order, arrow keys unused by the
+// focused widget move it to the nearest widget in the direction.
+// It returns the widget which has the focus after the key.
+func (m *FocusManager) HandleKey(k Key, shift bool) Widget {
+	if len(m.items) == 0 {
+		return nil
+	}
+	cur := m.index(m.focus)
+	if k == KeyTab {
+		step := 1
+		if shift {
+			step = -1
+		}
+		switch {
+		case cur < 0 && shift:
+			cur = len(m.items) - 1
+		case cur < 0:
+			cur = 0
+		default:
+			cur = (cur + step + len(m.items)) % len(m.items)
+		}
+		m.focus = m.items[cur].w
+		return m.focus
+	}
+	if cur < 0 {
+		return nil
+	}
+	if m.focus.HandleKey(k) {
+		return m.focus
+	}
+	if next := m.nearest(cur, k); 0 <= next {
+		m.focus = m.items[next].w
+	}
+	return m.focus
+}
+
+// nearest returns the index of the widget nearest to the widget cur in
+// the direction of the arrow key, or -1.
+func (m *FocusManager) nearest(cur int, k Key) int {
+	// unit vector of the direction in the screen with Y going down
+	var dx, dy float32
+	switch k {
+	case KeyLeft:
+		dx = -1
+	case KeyRight:
+		dx = 1
+	case KeyUp:
+		dy = -1
+	case KeyDown:
+		dy = 1
+	default:
+		return -1
+	}
+	center := func(r Rect) (x, y float32) {
+		x, y = r.X+r.Width/2, r.Y+r.Height/2
+		if origin != OriginTopLeft {
+			y = -y
+		}
+		return
+	}
+	cx, cy := center(m.items[cur].r)
+	best, bestScore := -1, float32(0)
+	for i, it := range m.items {
+		if i == cur {
+			continue
+		}
+		x, y := center(it.r)
+		along := (x-cx)*dx + (y-cy)*dy
+		if along <= 0 {
+			continue
+		}
+		across := (x-cx)*dy + (y-cy)*dx
+		if across < 0 {
+			across = -across
+		}
+		// prefer widgets in line with the current one
+		if score := along + 2*across; best < 0 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package glsymbol
+
+import "testing"
+
+type testWidget struct {
+	name string
+	keys []Key // used keys
+}
+
+func (w *testWidget) HandleKey(k Key) bool {
+	for _, u := range w.keys {
+		if u == k {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFocusManager(t *testing.T) {
+	SetOrigin(OriginTopLeft)
+	defer SetOrigin(OriginBottomLeft)
+
+	// a b
+	// c d
+	a := &testWidget{name: "a"}
+	b := &testWidget{name: "b", keys: []Key{KeyLeft}}
+	c := &testWidget{name: "c"}
+	d := &testWidget{name: "d"}
+	var m FocusManager
+	m.Register(a, Rect{0, 0, 50, 20})
+	m.Register(b, Rect{100, 0, 50, 20})
+	m.Register(c, Rect{0, 40, 50, 20})
+	m.Register(d, Rect{100, 40, 50, 20})
+
+	if w := m.HandleKey(KeyDown, false); w != nil {
+		t.Errorf("arrow without focus gives %v", w)
+	}
+	steps := []struct {
+		k      Key
+		shift  bool
+		expect *testWidget
+	}{
+		{KeyTab, false, a},
+		{KeyTab, false, b},
+		{KeyLeft, false, b}, // used by b
+		{KeyDown, false, d},
+		{KeyLeft, false, c},
+		{KeyUp, false, a},
+		{KeyUp, false, a},
+		{KeyTab, true, d},
+		{KeyTab, false, a},
+		{KeyEnter, false, a},
+	}
+	for i, s := range steps {
+		if w := m.HandleKey(s.k, s.shift); w != Widget(s.expect) {
+			t.Fatalf("step %d: focus on %v, expect %s", i, w, s.expect.name)
+		}
+	}
+
+	if w := m.FocusAt(120, 50); w != Widget(d) {
+		t.Errorf("click gives %v", w)
+	}
+	m.Reset()
+	if m.Focused() != nil {
+		t.Errorf("unregistered widget has focus")
+	}
+	m.Register(d, Rect{})
+	if m.Focused() != Widget(d) {
+		t.Errorf("focus is lost after reset")
+	}
+}
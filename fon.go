@@ -0,0 +1,250 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Windows raster fonts are .FNT files of versions 2.0 and 3.0, and .FON
+// files holding a few .FNT resources of different sizes in a 16-bit
+// executable. Codes of glyphs are converted to runes by the character
+// set of the font.
+
+// fnt holds parsed glyphs of a .FNT font.
+type fnt struct {
+	height  int // pixel height of all glyphs
+	ascent  int // distance from the top of glyphs to the baseline
+	first   byte
+	defChar int // index of the default glyph, or -1
+	glyphs  []rasterGlyph
+	decode  func(b byte) rune
+}
+
+// fntCharsets maps character sets of .FNT fonts to code pages.
+var fntCharsets = map[byte]*charmap.Charmap{
+	0:   charmap.Windows1252, // ANSI_CHARSET
+	161: charmap.Windows1253, // GREEK_CHARSET
+	162: charmap.Windows1254, // TURKISH_CHARSET
+	177: charmap.Windows1255, // HEBREW_CHARSET
+	178: charmap.Windows1256, // ARABIC_CHARSET
+	186: charmap.Windows1257, // BALTIC_CHARSET
+	204: charmap.Windows1251, // RUSSIAN_CHARSET
+	222: charmap.Windows874,  // THAI_CHARSET
+	238: charmap.Windows1250, // EASTEUROPE_CHARSET
+	255: charmap.CodePage437, // OEM_CHARSET
+}
+
+// parseFNT parses a raster font of a .FNT file or resource.
+func parseFNT(data []byte) (*fnt, error) {
+	if len(data) < 118 {
+		return nil, fmt.Errorf("glsymbol: fnt header is truncated")
+	}
+	le := binary.LittleEndian
+	version := le.Uint16(data)
+	if version != 0x200 && version != 0x300 {
+		return nil, fmt.Errorf("glsymbol: fnt version %#x is not supported", version)
+	}
+	if le.Uint16(data[66:])&1 != 0 {
+		return nil, fmt.Errorf("glsymbol: fnt font is a vector font")
+	}
+	f := &fnt{
+		ascent: int(le.Uint16(data[74:])),
+		height: int(le.Uint16(data[88:])),
+		first:  data[95],
+	}
+	last := data[96]
+	if last < f.first {
+		return nil, fmt.Errorf("glsymbol: fnt last char %d is less than first char %d", last, f.first)
+	}
+	f.defChar = int(data[97])
+	if int(last-f.first) < f.defChar {
+		f.defChar = -1
+	}
+	f.decode = func(b byte) rune { return rune(b) }
+	if cm, ok := fntCharsets[data[85]]; ok {
+		f.decode = cm.DecodeByte
+	}
+
+	// table of glyph widths and bitmap offsets
+	table, entry := 118, 4
+	if version == 0x300 {
+		table, entry = 148, 6
+	}
+	n := int(last-f.first) + 1
+	if len(data) < table+n*entry {
+		return nil, fmt.Errorf("glsymbol: fnt glyph table is truncated")
+	}
+	f.glyphs = make([]rasterGlyph, n)
+	for i := range f.glyphs {
+		e := data[table+i*entry:]
+		w := int(le.Uint16(e))
+		var offset int
+		if version == 0x300 {
+			offset = int(le.Uint32(e[2:]))
+		} else {
+			offset = int(le.Uint16(e[2:]))
+		}
+		// bitmaps are stored by columns of 8 pixels
+		columns := (w + 7) / 8
+		if offset < 0 || len(data) < offset+columns*f.height {
+			return nil, fmt.Errorf("glsymbol: fnt bitmap of glyph %d is out of file", i)
+		}
+		bits := make([]byte, columns*f.height)
+		for c := 0; c < columns; c++ {
+			for y := 0; y < f.height; y++ {
+				bits[y*columns+c] = data[offset+c*f.height+y]
+			}
+		}
+		f.glyphs[i] = rasterGlyph{
+			width:   w,
+			height:  f.height,
+			advance: w,
+			stride:  columns,
+			bits:    bits,
+		}
+	}
+	return f, nil
+}
+
+// runes returns the range of runes of all glyphs and the index of
+// the glyph of every rune.
+func (f *fnt) runes() (low, high rune, index map[rune]int) {
+	index = make(map[rune]int)
+	low, high = -1, -1
+	for i := range f.glyphs {
+		r := f.decode(f.first + byte(i))
+		if r == utf8.RuneError {
+			continue
+		}
+		index[r] = i
+		if low < 0 || r < low {
+			low = r
+		}
+		if high < r {
+			high = r
+		}
+	}
+	return
+}
+
+// fonResources returns .FNT resources of a .FON file.
+func fonResources(data []byte) ([][]byte, error) {
+	le := binary.LittleEndian
+	if len(data) < 0x40 || !bytes.Equal(data[:2], []byte("MZ")) {
+		return nil, fmt.Errorf("glsymbol: not a fon or fnt font")
+	}
+	ne := int(le.Uint32(data[0x3c:]))
+	if ne < 0 || len(data) < ne+0x40 || !bytes.Equal(data[ne:ne+2], []byte("NE")) {
+		return nil, fmt.Errorf("glsymbol: fon file is not a 16-bit executable")
+	}
+	res := ne + int(le.Uint16(data[ne+0x24:]))
+	if len(data) < res+2 {
+		return nil, fmt.Errorf("glsymbol: fon resource table is out of file")
+	}
+	shift := le.Uint16(data[res:])
+	var fonts [][]byte
+	for p := res + 2; ; {
+		if len(data) < p+2 {
+			return nil, fmt.Errorf("glsymbol: fon resource table is truncated")
+		}
+		typ := le.Uint16(data[p:])
+		if typ == 0 {
+			break
+		}
+		if len(data) < p+8 {
+			return nil, fmt.Errorf("glsymbol: fon resource table is truncated")
+		}
+		count := int(le.Uint16(data[p+2:]))
+		p += 8
+		for i := 0; i < count; i++ {
+			if len(data) < p+12 {
+				return nil, fmt.Errorf("glsymbol: fon resource table is truncated")
+			}
+			offset := int(le.Uint16(data[p:])) << shift
+			size := int(le.Uint16(data[p+2:])) << shift
+			p += 12
+			if typ != 0x8008 { // RT_FONT
+				continue
+			}
+			if len(data) < offset+size {
+				return nil, fmt.Errorf("glsymbol: fon font resource is out of file")
+			}
+			fonts = append(fonts, data[offset:offset+size])
+		}
+	}
+	if len(fonts) == 0 {
+		return nil, fmt.Errorf("glsymbol: fon file has no fonts")
+	}
+	return fonts, nil
+}
+
+// DecodeFON reads a Windows raster font of a .FON or .FNT file into
+// a sprite sheet and its config for the runes from low to high. If high
+// is zero, all runes of the character set of the font are decoded.
+// Runes without glyphs are drawn as the default character of the font.
+//
+// A .FON file usually holds the font in a few sizes, the one with
+// the pixel height nearest to height is used. Zero height means
+// the first font of the file.
+func DecodeFON(r io.Reader, height int32, low, high rune) (*image.RGBA, *FontConfig, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resources := [][]byte{data}
+	if bytes.HasPrefix(data, []byte("MZ")) {
+		if resources, err = fonResources(data); err != nil {
+			return nil, nil, err
+		}
+	}
+	var f *fnt
+	for _, res := range resources {
+		rf, err := parseFNT(res)
+		if err != nil {
+			return nil, nil, err
+		}
+		if f == nil {
+			f = rf
+		} else if height != 0 && abs(int32(rf.height)-height) < abs(int32(f.height)-height) {
+			f = rf
+		}
+	}
+
+	lo, hi, index := f.runes()
+	if high == 0 {
+		low, high = lo, hi
+	}
+	if high < low {
+		return nil, nil, fmt.Errorf("glsymbol: high rune %q is less than low rune %q", high, low)
+	}
+	img, config := rasterSheet(low, high, f.glyphs, func(r rune) int {
+		if i, ok := index[r]; ok {
+			return i
+		}
+		return f.defChar
+	}, f.height, f.height-f.ascent)
+	return img, config, nil
+}
+
+// LoadFON loads a Windows raster font of a .FON or .FNT file with
+// the runes from low to high. See DecodeFON.
+func LoadFON(r io.Reader, height int32, low, high rune) (*Font, error) {
+	img, config, err := DecodeFON(r, height, low, high)
+	if err != nil {
+		return nil, err
+	}
+	return loadFont(img, config)
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,149 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// testFNT builds a .FNT font of the version with glyphs 'A' and 'B'
+// in two sizes of the pixel height 2 or 3.
+func testFNT(version uint16, height int) []byte {
+	le := binary.LittleEndian
+	table, entry := 118, 4
+	if version == 0x300 {
+		table, entry = 148, 6
+	}
+	head := make([]byte, table+3*entry)
+	le.PutUint16(head, version)
+	le.PutUint16(head[74:], 1)              // ascent
+	le.PutUint16(head[88:], uint16(height)) // pixel height
+	head[85] = 0                            // ANSI_CHARSET
+	head[95], head[96], head[97] = 'A', 'B', 1
+
+	// 'A' is 3 pixels wide: 101, 010, ...
+	a := []byte{0xa0, 0x40, 0, 0}[:height]
+	// 'B' is 10 pixels wide, stored by columns: 1000000001, ...
+	b := append([]byte{0x80, 0, 0, 0}[:height], []byte{0x40, 0, 0, 0}[:height]...)
+	widths := []int{3, 10, 0}
+	offsets := []int{len(head), len(head) + len(a), len(head) + len(a) + len(b)}
+	for i := range widths {
+		e := head[table+i*entry:]
+		le.PutUint16(e, uint16(widths[i]))
+		if version == 0x300 {
+			le.PutUint32(e[2:], uint32(offsets[i]))
+		} else {
+			le.PutUint16(e[2:], uint16(offsets[i]))
+		}
+	}
+	data := append(head, a...)
+	return append(data, b...)
+}
+
+// testFON wraps fonts into a .FON file.
+func testFON(fonts ...[]byte) []byte {
+	le := binary.LittleEndian
+	const ne, shift = 0x40, 4
+	data := make([]byte, ne+0x40)
+	copy(data, "MZ")
+	le.PutUint32(data[0x3c:], ne)
+	copy(data[ne:], "NE")
+	le.PutUint16(data[ne+0x24:], 0x40) // resource table
+
+	var res bytes.Buffer
+	binary.Write(&res, le, []uint16{shift, 0x8008, uint16(len(fonts)), 0, 0})
+	offset := len(data) + 2 + 8 + 12*len(fonts) + 2
+	offset = (offset + 1<<shift - 1) &^ (1<<shift - 1)
+	for _, f := range fonts {
+		size := (len(f) + 1<<shift - 1) >> shift
+		binary.Write(&res, le, []uint16{uint16(offset >> shift), uint16(size), 0, 0, 0, 0})
+		offset += size << shift
+	}
+	binary.Write(&res, le, uint16(0))
+	data = append(data, res.Bytes()...)
+	for _, f := range fonts {
+		for len(data)%(1<<shift) != 0 {
+			data = append(data, 0)
+		}
+		data = append(data, f...)
+	}
+	for len(data)%(1<<shift) != 0 {
+		data = append(data, 0)
+	}
+	return data
+}
+
+func TestDecodeFON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data   []byte
+		height int32
+		expect int32
+	}{
+		"fnt2":    {data: testFNT(0x200, 2), expect: 2},
+		"fnt3":    {data: testFNT(0x300, 3), expect: 3},
+		"fon":     {data: testFON(testFNT(0x200, 2), testFNT(0x300, 3)), expect: 2},
+		"fonSize": {data: testFON(testFNT(0x200, 2), testFNT(0x300, 3)), height: 4, expect: 3},
+	} {
+		t.Run(name, func(t *testing.T) {
+			img, config, err := DecodeFON(bytes.NewReader(tc.data), tc.height, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Low != 'A' || config.High != 'B' || config.Baseline != tc.expect-1 {
+				t.Fatalf("config %q..%q baseline %d", config.Low, config.High, config.Baseline)
+			}
+			if err := config.Validate(img.Bounds()); err != nil {
+				t.Fatal(err)
+			}
+			a, b := config.Glyphs[0], config.Glyphs[1]
+			if a.Width != 3 || a.Advance != 3 || b.Width != 10 || a.Height != tc.expect {
+				t.Fatalf("glyphs %+v %+v", a, b)
+			}
+			ink := func(g Glyph, x, y int) bool {
+				_, _, _, a := img.At(int(g.X)+x, int(g.Y)+1+y).RGBA()
+				return a != 0
+			}
+			for _, p := range []struct {
+				g    Glyph
+				x, y int
+				ink  bool
+			}{
+				{a, 0, 0, true}, {a, 1, 0, false}, {a, 2, 0, true}, {a, 1, 1, true},
+				{b, 0, 0, true}, {b, 1, 0, false}, {b, 8, 0, false}, {b, 9, 0, true},
+			} {
+				if ink(p.g, p.x, p.y) != p.ink {
+					t.Errorf("pixel %d,%d of glyph %+v", p.x, p.y, p.g)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFONDefault(t *testing.T) {
+	_, config, err := DecodeFON(bytes.NewReader(testFNT(0x200, 2)), 0, 'A', 'D')
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the default character is 'B'
+	if c := config.Glyphs[2]; c.Width != 10 || c.X != config.Glyphs[1].X {
+		t.Errorf("glyph C %+v", c)
+	}
+}
+
+func TestDecodeFONInvalid(t *testing.T) {
+	fnt := testFNT(0x200, 2)
+	vector := append([]byte(nil), fnt...)
+	vector[66] = 1
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"version":   append([]byte{1, 1}, fnt[2:]...),
+		"vector":    vector,
+		"truncated": fnt[:len(fnt)-1],
+		"mz":        []byte("MZ"),
+		"noFonts":   testFON(),
+	} {
+		if _, _, err := DecodeFON(bytes.NewReader(data), 0, 0, 0); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
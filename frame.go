@@ -0,0 +1,263 @@
+package glsymbol
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A Frame draws the background and border of a box, for example of
+// a tooltip. Insets are the widths of the border on every side, text
+// of a box is placed inside of them.
+type Frame interface {
+	Draw(r Rect) error
+	Insets() (left, top, right, bottom float32)
+}
+
+// A NinePatch is a frame made of a texture region split into nine parts:
+// corners are drawn unscaled, edges are stretched along the frame and
+// the center in both directions, so themed frames fit any box.
+type NinePatch struct {
+	Texture uint32
+
+	// U0, V0 are texture coordinates of the top left corner of the
+	// region, U1, V1 of the bottom right corner.
+	U0, V0, U1, V1 float32
+
+	Width, Height int32 // size of the region in pixels
+
+	// Left, Top, Right and Bottom are sizes of the unscaled borders
+	// of the region in pixels.
+	Left, Top, Right, Bottom int32
+}
+
+// Insets implements Frame.
+func (p *NinePatch) Insets() (left, top, right, bottom float32) {
+	return float32(p.Left), float32(p.Top), float32(p.Right), float32(p.Bottom)
+}
+
+// patchQuad is a part of a nine-patch: the rectangle in the coordinates
+// of the box with Y going down and the texture coordinates.
+type patchQuad struct {
+	x0, y0, x1, y1 float32
+	u0, v0, u1, v1 float32
+}
+
+// quads splits the box of the given size into nine parts. Borders are
+// shrunk in proportion if the box is smaller than the borders.
+func (p *NinePatch) quads(width, height float32) []patchQuad {
+	fit := func(a, b, size float32) (float32, float32) {
+		if a+b <= size || a+b == 0 {
+			return a, b
+		}
+		k := size / (a + b)
+		return a * k, b * k
+	}
+	l, r := fit(float32(p.Left), float32(p.Right), width)
+	t, b := fit(float32(p.Top), float32(p.Bottom), height)
+	xs := [4]float32{0, l, width - r, width}
+	ys := [4]float32{0, t, height - b, height}
+
+	du := (p.U1 - p.U0) / float32(p.Width)
+	dv := (p.V1 - p.V0) / float32(p.Height)
+	us := [4]float32{p.U0, p.U0 + float32(p.Left)*du, p.U1 - float32(p.Right)*du, p.U1}
+	vs := [4]float32{p.V0, p.V0 + float32(p.Top)*dv, p.V1 - float32(p.Bottom)*dv, p.V1}
+
+	qs := make([]patchQuad, 0, 9)
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			if xs[i] == xs[i+1] || ys[j] == ys[j+1] {
+				continue
+			}
+			qs = append(qs, patchQuad{
+				xs[i], ys[j], xs[i+1], ys[j+1],
+				us[i], vs[j], us[i+1], vs[j+1],
+			})
+		}
+	}
+	return qs
+}
+
+// Draw implements Frame. The texture is modulated by the current color.
+func (p *NinePatch) Draw(r Rect) error {
+	gl.PushAttrib(gl.ENABLE_BIT | gl.TEXTURE_BIT | gl.COLOR_BUFFER_BIT)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindTexture(gl.TEXTURE_2D, p.Texture)
+	gl.TexEnvi(gl.TEXTURE_ENV, gl.TEXTURE_ENV_MODE, gl.MODULATE)
+	top := rectTop(r)
+	gl.Begin(gl.QUADS)
+	for _, q := range p.quads(r.Width, r.Height) {
+		gl.TexCoord2f(q.u0, q.v1)
+		gl.Vertex2f(r.X+q.x0, below(top, q.y1))
+		gl.TexCoord2f(q.u1, q.v1)
+		gl.Vertex2f(r.X+q.x1, below(top, q.y1))
+		gl.TexCoord2f(q.u1, q.v0)
+		gl.Vertex2f(r.X+q.x1, below(top, q.y0))
+		gl.TexCoord2f(q.u0, q.v0)
+		gl.Vertex2f(r.X+q.x0, below(top, q.y0))
+	}
+	gl.End()
+	gl.PopAttrib()
+	return checkGLError("NinePatch.Draw")
+}
+
+// rectTop returns the coordinate of the top edge of the rectangle.
+func rectTop(r Rect) float32 {
+	if origin == OriginTopLeft {
+		return r.Y
+	}
+	return r.Y + r.Height
+}
+
+// A RoundedFrame is a frame drawn without textures: a rectangle with
+// rounded corners filled by a color and outlined by a border.
+type RoundedFrame struct {
+	Fill   color.Color // nil for no fill
+	Border color.Color // nil for no border
+
+	BorderWidth float32
+	Radius      float32
+}
+
+// Insets implements Frame.
+func (fr RoundedFrame) Insets() (left, top, right, bottom float32) {
+	w := fr.BorderWidth
+	if fr.Border == nil {
+		w = 0
+	}
+	return w, w, w, w
+}
+
+// cornerSegments returns the amount of segments of rounded corners of
+// the radius, more segments for larger corners.
+func cornerSegments(radius float32) int {
+	if radius <= 0 {
+		return 0
+	}
+	segs := 1 + int(radius/2)
+	if 8 < segs {
+		segs = 8
+	}
+	return segs
+}
+
+// roundedPath returns points of the outline of a rectangle with rounded
+// corners of segs segments, clockwise on the screen with Y going down.
+// Every corner has segs+1 points, so outlines of the same segs have the
+// same points whatever their radii are.
+func roundedPath(x, y, width, height, radius float32, segs int) (xs, ys []float32) {
+	if m := min32(width, height) / 2; m < radius {
+		radius = m
+	}
+	if radius < 0 {
+		radius = 0
+	}
+	corners := [4][3]float32{
+		{x + width - radius, y + radius, -math.Pi / 2}, // top right
+		{x + width - radius, y + height - radius, 0},   // bottom right
+		{x + radius, y + height - radius, math.Pi / 2}, // bottom left
+		{x + radius, y + radius, math.Pi},              // top left
+	}
+	for _, c := range corners {
+		for i := 0; i <= segs; i++ {
+			a := float64(c[2])
+			if 0 < segs {
+				a += float64(i) / float64(segs) * math.Pi / 2
+			}
+			xs = append(xs, c[0]+radius*float32(math.Cos(a)))
+			ys = append(ys, c[1]+radius*float32(math.Sin(a)))
+		}
+	}
+	return
+}
+
+// paths returns the outer and the inner outlines of the frame of the
+// size, the border is between them. Both have the same points.
+func (fr RoundedFrame) paths(width, height float32) (ox, oy, ix, iy []float32) {
+	segs := cornerSegments(fr.Radius)
+	ox, oy = roundedPath(0, 0, width, height, fr.Radius, segs)
+	w := fr.BorderWidth
+	if fr.Border == nil {
+		w = 0
+	}
+	ix, iy = roundedPath(w, w, width-2*w, height-2*w, fr.Radius-w, segs)
+	return
+}
+
+// Draw implements Frame.
+func (fr RoundedFrame) Draw(r Rect) error {
+	gl.PushAttrib(gl.CURRENT_BIT | gl.ENABLE_BIT | gl.COLOR_BUFFER_BIT)
+	gl.Disable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	top := rectTop(r)
+	ox, oy, ix, iy := fr.paths(r.Width, r.Height)
+	if fr.Fill != nil {
+		setColor(fr.Fill)
+		gl.Begin(gl.TRIANGLE_FAN)
+		for i := range ix {
+			gl.Vertex2f(r.X+ix[i], below(top, iy[i]))
+		}
+		gl.End()
+	}
+	if fr.Border != nil && 0 < fr.BorderWidth {
+		setColor(fr.Border)
+		gl.Begin(gl.TRIANGLE_STRIP)
+		for i := 0; i <= len(ox); i++ {
+			k := i % len(ox)
+			gl.Vertex2f(r.X+ox[k], below(top, oy[k]))
+			gl.Vertex2f(r.X+ix[k], below(top, iy[k]))
+		}
+		gl.End()
+	}
+	gl.PopAttrib()
+	return checkGLError("RoundedFrame.Draw")
+}
+
+// setColor sets the current color.
+func setColor(c color.Color) {
+	r, g, b, a := c.RGBA()
+	gl.Color4f(float32(r)/0xffff, float32(g)/0xffff, float32(b)/0xffff, float32(a)/0xffff)
+}
+
+// TextBoxSize returns the size of a box of the multi-line text with the
+// padding between the text and the insets of the frame.
+func (f *Font) TextBoxSize(text string, frame Frame, padding float32) (width, height float32) {
+	w, h := f.BoundingBox(text)
+	var l, t, r, b float32
+	if frame != nil {
+		l, t, r, b = frame.Insets()
+	}
+	return float32(w) + l + r + 2*padding, float32(h) + t + b + 2*padding
+}
+
+// DrawTextBox draws the frame sized by TextBoxSize with the top left
+// corner at x, y and the text inside of it. Lines are broken by the
+// newline policy, see SetNewlinePolicy.
+// Text is drawn with the current color. It returns the box.
+func (f *Font) DrawTextBox(x, y float32, text string, frame Frame, padding float32) (Rect, error) {
+	w, h := f.TextBoxSize(text, frame, padding)
+	box := Rect{X: x, Y: linePos(y, h), Width: w, Height: h}
+	var l, t float32
+	if frame != nil {
+		gl.PushAttrib(gl.CURRENT_BIT)
+		err := frame.Draw(box)
+		gl.PopAttrib()
+		if err != nil {
+			return box, err
+		}
+		l, t, _, _ = frame.Insets()
+	}
+	tx, ty := x+l+padding, below(y, t+padding)
+	lh := float32(f.lineHeight())
+	for _, line := range splitLines(text) {
+		if err := f.Printf(f.startX(tx, line), f.lineY(ty), line); err != nil {
+			return box, err
+		}
+		ty = below(ty, lh)
+	}
+	return box, nil
+}
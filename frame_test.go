@@ -0,0 +1,64 @@
+package glsymbol
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestNinePatch(t *testing.T) {
+	p := &NinePatch{U1: 1, V1: 1, Width: 16, Height: 16, Left: 4, Top: 4, Right: 4, Bottom: 4}
+	qs := p.quads(100, 40)
+	if len(qs) != 9 {
+		t.Fatalf("%d quads", len(qs))
+	}
+	if q := qs[0]; q != (patchQuad{0, 0, 4, 4, 0, 0, 0.25, 0.25}) {
+		t.Errorf("top left corner %v", q)
+	}
+	if q := qs[4]; q != (patchQuad{4, 4, 96, 36, 0.25, 0.25, 0.75, 0.75}) {
+		t.Errorf("center %v", q)
+	}
+	if q := qs[8]; q != (patchQuad{96, 36, 100, 40, 0.75, 0.75, 1, 1}) {
+		t.Errorf("bottom right corner %v", q)
+	}
+
+	// box lower than borders: no center row
+	qs = p.quads(100, 6)
+	if len(qs) != 6 || qs[0].y1 != 3 {
+		t.Errorf("low box %v", qs)
+	}
+}
+
+func TestRoundedPath(t *testing.T) {
+	xs, ys := roundedPath(0, 0, 20, 10, 0, 0)
+	if len(xs) != 4 || xs[0] != 20 || ys[0] != 0 || xs[2] != 0 || ys[2] != 10 {
+		t.Errorf("rectangle %v %v", xs, ys)
+	}
+	xs, ys = roundedPath(0, 0, 20, 10, 8, cornerSegments(8))
+	for i := range xs {
+		if xs[i] < 0 || 20 < xs[i] || ys[i] < -1e-4 || 10+1e-4 < ys[i] {
+			t.Errorf("point %v,%v is out of the box", xs[i], ys[i])
+		}
+	}
+}
+
+func TestRoundedFrameBorder(t *testing.T) {
+	for _, tc := range [][2]float32{{4, 1}, {5, 2}, {2, 2}, {8, 1}, {1, 1}, {0, 2}} {
+		fr := RoundedFrame{Border: color.White, Radius: tc[0], BorderWidth: tc[1]}
+		ox, oy, ix, iy := fr.paths(40, 20)
+		// the border strip joins points of both outlines
+		if len(ox) == 0 || len(ox) != len(ix) || len(oy) != len(iy) {
+			t.Errorf("radius %v, border %v: %d outer and %d inner points", tc[0], tc[1], len(ox), len(ix))
+		}
+	}
+}
+
+func TestTextBoxSize(t *testing.T) {
+	f := testFont()
+	frame := RoundedFrame{Border: color.White, BorderWidth: 2, Radius: 4}
+	if w, h := f.TextBoxSize("ab\nabc", frame, 3); w != 24+4+6 || h != 32+4+6 {
+		t.Errorf("size %v,%v", w, h)
+	}
+	if w, h := f.TextBoxSize("ab", nil, 0); w != 16 || h != 16 {
+		t.Errorf("size without frame %v,%v", w, h)
+	}
+}
@@ -8,6 +8,10 @@ top-to-bottom). This allows for correct display of text for various languages.
 This package supports the full set of unicode characters, provided the loaded
 font does as well.
 
+Drawing calls OpenGL, so it must run on the thread of the OpenGL context.
+Fonts with a Queue may be drawn from any goroutine, the queue is flushed
+on the thread of the context.
+
 This packages uses freetype-go (code.google.com/p/freetype-go) which is licensed
 under GPLv2 e FTL licenses. You can choose which one is a better fit for your
 use case but FTL requires you to give some form of credit to Freetype.org
@@ -19,16 +23,24 @@ licenses for more information about the requirements.
 package glsymbol
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"io"
+	"math/bits"
+	"runtime"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/language"
 )
 
 //go:embed ProggyClean.ttf
@@ -62,8 +74,38 @@ type Glyph struct {
 	// This is used to properly align non-monospaced fonts.
 	Advance int32
 
+	// LeftBearing is the distance from the pen position to the left edge
+	// of the glyph, RightBearing from the right edge of the glyph to the
+	// next pen position. They are negative if the glyph overlaps its
+	// neighbours, as in script and italic fonts. The pen moves by
+	// LeftBearing + Width + RightBearing.
+	LeftBearing, RightBearing int32
+
+	// TopBearing is the distance from the top of the glyph cell down to
+	// the top row of the bitmap, BottomBearing from the bottom row of the
+	// bitmap down to the bottom of the cell. Truetype fonts store tight
+	// bitmaps of the ink of glyphs, the bearings keep them on the common
+	// baseline. The cell is TopBearing + Height + BottomBearing high.
+	TopBearing, BottomBearing int32
+
+	// VerticalAdvance is the distance the pen moves down over the glyph
+	// in the TopToBottom direction, MaxGlyphHeight of the font if zero.
+	VerticalAdvance int32
+
 	// Bitmap data of glyph
 	BitmapData []uint8
+
+	upright *Glyph // glyph turned into this one, see Font.turned
+}
+
+// step returns the distance the pen moves over the glyph.
+func (g *Glyph) step() int32 {
+	return g.LeftBearing + g.Width + g.RightBearing
+}
+
+// cell returns the height of the glyph cell.
+func (g *Glyph) cell() int32 {
+	return g.TopBearing + g.Height + g.BottomBearing
 }
 
 // A Charset represents a set of glyph descriptors for a font.
@@ -71,13 +113,26 @@ type Glyph struct {
 // properly align the given glyph in the resulting rendered string.
 type Charset []Glyph
 
-// checkGLError returns an opengl error if one exists.
-func checkGLError() error {
-	errno := gl.GetError()
+// GLError is an opengl error detected after an operation.
+type GLError struct {
+	Op   string // Operation, for example "Printf".
+	Code uint32 // Error code, for example gl.OUT_OF_MEMORY.
+}
+
+func (e *GLError) Error() string {
+	return fmt.Sprintf("%s: GL error: %d", e.Op, e.Code)
+}
+
+// checkGLError returns an opengl error of the operation if one exists.
+func checkGLError(op string) error {
+	errno := fault(op)
+	if errno == gl.NO_ERROR {
+		errno = gl.GetError()
+	}
 	if errno == gl.NO_ERROR {
 		return nil
 	}
-	return fmt.Errorf("GL error: %d", errno)
+	return &GLError{Op: op, Code: errno}
 }
 
 // FontConfig describes raster font metadata.
@@ -90,9 +145,23 @@ type FontConfig struct {
 	// Upper rune boundary.
 	High rune
 
+	// Baseline is the distance from the bottom of glyph cells up to
+	// the baseline of glyphs. Zero for fonts without a known baseline.
+	Baseline int32
+
 	// Glyphs holds a set of glyph descriptors, defining the location,
 	// size and advance of each glyph in the sprite sheet.
 	Glyphs Charset
+
+	// Ranges, if not empty, are ascending segments of runes of the font
+	// in place of the single range Low..High, which are then the lowest
+	// and the highest runes of the segments. Glyphs of the segments
+	// follow each other in Glyphs. See Index and RuneAt.
+	Ranges []RuneRange
+
+	// Kerning holds kerning pairs of runes of the font, read from the
+	// GPOS or kern tables of truetype fonts.
+	Kerning []KernPair
 }
 
 // A Font allows rendering of text to an OpenGL context.
@@ -100,6 +169,124 @@ type Font struct {
 	Config         *FontConfig // Character set for this font.
 	MaxGlyphWidth  int32       // Largest glyph width.
 	MaxGlyphHeight int32       // Largest glyph height.
+
+	// LineHeight is the distance between lines of multi-line text.
+	LineHeight LineHeight
+
+	// Rounding is the policy of rounding fractional positions and sizes.
+	Rounding Rounding
+
+	// Grid, if not nil, snaps baselines of multi-line text to the grid.
+	// The line height is rounded up to a multiple of the grid step.
+	Grid *BaselineGrid
+
+	// MaxGlyphs limits the amount of glyphs drawn by a single Printf call,
+	// protecting a frame from accidentally drawn huge strings.
+	// Zero means no limit.
+	MaxGlyphs int
+
+	// Overflow is called, if not nil, when Printf truncates a string
+	// because of MaxGlyphs. It receives the amount of drawn glyphs and
+	// the amount of runes in the string.
+	Overflow func(drawn, total int)
+
+	// Arena, if not nil, provides memory for transient data of layout
+	// and drawing: rune and offset buffers of joining, reordering and
+	// shaping, words of laid out lines and tables of MetricsBatch.
+	Arena *FrameArena
+
+	// Unknown is the policy for runes without glyphs.
+	Unknown UnknownRune
+
+	// Fallback, if not nil, supplies images of runes the font has no
+	// glyphs for, for example drawn programmatically. It is called once
+	// for every such rune and the image is packed into the sprite sheet
+	// as a normal glyph: pixels with alpha of at least a half are drawn,
+	// the image stands on the baseline and the pen moves by its width.
+	// Nil means the rune has no glyph.
+	Fallback func(r rune) image.Image
+
+	// NoKerning disables kerning pairs of Config, so the pen moves by
+	// steps of glyphs only, as in fonts without kerning.
+	NoKerning bool
+
+	// Features lists OpenType features of the GSUB table of truetype
+	// fonts applied by layout, for example "smcp" for small capitals or
+	// "zero" for the slashed zero. Single substitutions of glyphs are
+	// supported, the first feature substituting a glyph wins.
+	Features []string
+
+	// Ligatures enables standard ligatures, for example fi, fl and ffi:
+	// runes of a ligature are drawn by a single glyph, taken from the
+	// liga feature of the GSUB table of truetype fonts or from Unicode
+	// presentation forms the font has glyphs of. The glyph of a ligature
+	// is placed by EachGlyph for the first rune of the ligature, other
+	// runes have no glyphs, so carets can not be placed within ligatures.
+	// It is off by default, which keeps one glyph for every rune.
+	Ligatures bool
+
+	// NoJoining disables joining of Arabic letters: letters are drawn by
+	// glyphs of their presentation forms, isolated, initial, medial or
+	// final, by neighbour letters, and lam with alef by their ligature,
+	// if the font has glyphs of the forms, see the Arabic Presentation
+	// Forms blocks. Otherwise Arabic letters are drawn disconnected.
+	NoJoining bool
+
+	// Bidi reorders mixed left-to-right and right-to-left text by the
+	// Unicode bidirectional algorithm before it is drawn and measured,
+	// brackets of right-to-left runs are mirrored. Every line is a
+	// paragraph with the base direction of the mode. Glyphs placed by
+	// EachGlyph keep indexes and offsets of runes in the string, so
+	// PrintfColors colors runes as without reordering.
+	Bidi BidiMode
+
+	// Direction is the direction the pen moves in, x of Printf is the
+	// left edge of left-to-right strings and the right edge of
+	// right-to-left strings. Glyphs keep the order of the string, the
+	// first one is at x, see Bidi for the visual order of mixed text.
+	Direction Direction
+
+	// Sideways turns glyphs of runes of scripts written horizontally,
+	// Latin letters and digits for example, a quarter turn clockwise in
+	// the TopToBottom direction, as in vertical Japanese and Chinese
+	// text, where ideographs, kana and hangul stay upright.
+	Sideways bool
+
+	// Shaper, if not nil, places glyphs of text instead of the font, see
+	// Shaper. TextHash does not cover results of the shaper.
+	Shaper Shaper
+
+	// Budget, if not zero, limits memory of the sprite sheet in bytes.
+	// Glyphs rasterized on demand or supplied by Fallback are evicted,
+	// least recently used first, to make place for new ones; if that is
+	// not enough, AddGlyph fails with *BudgetError and other runes are
+	// drawn by the Unknown policy.
+	Budget int64
+
+	img     *image.RGBA   // Sprite sheet the glyphs were taken from.
+	bitmaps []uint8       // Bitmap data of all glyphs.
+	pages   []texturePage // Textures of the sprite sheet, if created.
+	stale   bool          // Textures do not hold glyphs added to the sheet.
+	size    int32         // Font size in pixels, zero if not known.
+
+	added    map[rune]*Glyph                // Glyphs of runes out of the range of Config, nil if Fallback has none.
+	unknown  Glyph                          // Glyph of unknown runes, see unknownGlyph.
+	shelf    image.Point                    // Free place for added glyphs in the sheet.
+	missing  map[rune]bool                  // Runes without glyphs reported to the logger.
+	source   *glyphSource                   // Rasterizer of glyphs on demand, see LoadTruetypeDynamic.
+	queue    *Queue                         // Queue of Printf calls, see SetQueue.
+	lru      map[rune]uint64                // Last use of evictable added glyphs, see Budget.
+	locl     map[language.Tag]map[rune]rune // Variants of glyphs by languages, see Localize.
+	kern     map[[2]rune]int32              // Kerning pairs of Config by runes.
+	subst    *substitutions                 // Substitutes of glyphs by Features, nil if none.
+	advances map[rune]int32                 // Overrides of advances, see SetAdvance.
+	data     map[rune]interface{}           // Data of runes, see SetRuneData.
+	tick     uint64                         // Clock of uses of added glyphs.
+
+	turnedGlyphs map[*Glyph]*Glyph // Glyphs turned sideways, see Sideways.
+
+	identity uint64  // Hash of glyphs, zero if not computed, see Identity.
+	avgStep  float64 // Average step of glyphs, zero if not computed.
 }
 
 // loadFont loads the given font data. This does not deal with font scaling.
@@ -112,92 +299,551 @@ type Font struct {
 func loadFont(img *image.RGBA, config *FontConfig) (f *Font, err error) {
 	f = new(Font)
 	f.Config = config
+	f.kern = kernMap(config.Kerning)
+	f.img = img
+	stats.fontsLoaded.Add(1)
+	stats.atlasBytes.Add(int64(len(img.Pix)))
+
+	sp := startSpan(StageUpload)
+	defer sp.end()
 
 	gl.ShadeModel(gl.FLAT)
 	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
-	for i, j := 0, uint32(config.Low); i < int(config.High-config.Low); i, j = i+1, j+1 { // uint32('A')
-		{ // prepare bitmap data
-			glyph := &config.Glyphs[i]
-			glyph.BitmapData = nil
-			for y := glyph.Height; 0 <= y; y-- {
-				var u uint8
-				for x := 0; x < int(glyph.Width); x++ {
-					c := img.At(x+int(glyph.X), int(y)+int(glyph.Y))
-					h := x % 8
-					if r, _, _, _ := c.RGBA(); 40000 < r {
-						u |= 1 << (7 - h)
-					}
-					if h == 7 || x == int(glyph.Width)-1 {
-						glyph.BitmapData = append(glyph.BitmapData, u)
-						u = 0
-					}
-				}
-			}
-		}
 
-		if f.MaxGlyphHeight < config.Glyphs[i].Height {
-			f.MaxGlyphHeight = config.Glyphs[i].Height
+	// Bitmaps of all glyphs are stored in a single buffer owned by
+	// the font, see Printf for the reason.
+	var bitmaps []uint8
+	starts := make([]int, len(config.Glyphs)+1)
+	for i := range config.Glyphs {
+		starts[i] = len(bitmaps)
+		bitmaps = glyphBitmap(bitmaps, img, &config.Glyphs[i])
+
+		if h := config.Glyphs[i].cell(); f.MaxGlyphHeight < h {
+			f.MaxGlyphHeight = h
 		}
 		if f.MaxGlyphWidth < config.Glyphs[i].Width {
 			f.MaxGlyphWidth = config.Glyphs[i].Width
 		}
 	}
+	starts[len(config.Glyphs)] = len(bitmaps)
+	for i := range config.Glyphs {
+		// full slice expression protects neighbours from appends
+		config.Glyphs[i].BitmapData = bitmaps[starts[i]:starts[i+1]:starts[i+1]]
+	}
+	f.bitmaps = bitmaps
 
-	err = checkGLError()
+	if err = checkGLError("loadFont"); err != nil {
+		// the font is not usable, do not leave it to the caller
+		warn("glsymbol: font released after GL error", "error", err)
+		f.Release()
+		return nil, err
+	}
 	return
 }
 
+// glyphBitmap appends the bitmap data of the glyph taken from the sprite
+// sheet to dst. Rows go from the bottom up, as gl.Bitmap expects.
+// Pixels with the red channel above 155 are set.
+//
+// Pixels are read from the sheet memory directly: this is the hot path
+// of loading large sprite sheets.
+func glyphBitmap(dst []uint8, img *image.RGBA, glyph *Glyph) []uint8 {
+	w := int(glyph.Width)
+	if w <= 0 {
+		return dst
+	}
+	rowBytes := (w + 7) / 8
+	start := len(dst)
+	dst = append(dst, make([]uint8, rowBytes*int(glyph.Height+1))...)
+
+	// part of the glyph rectangle inside of the sheet
+	b := img.Bounds()
+	x0, x1 := int(glyph.X), int(glyph.X)+w
+	if x0 < b.Min.X {
+		x0 = b.Min.X
+	}
+	if b.Max.X < x1 {
+		x1 = b.Max.X
+	}
+	row := dst[start:]
+	for y := int(glyph.Y + glyph.Height); int(glyph.Y) <= y; y-- {
+		if b.Min.Y <= y && y < b.Max.Y && x0 < x1 {
+			off := img.PixOffset(x0, y)
+			pix := img.Pix[off : off+(x1-x0)*4]
+			bit := x0 - int(glyph.X)
+			// bits are packed without branches: red values above 155
+			// carry into the ninth bit, whole bytes are packed at once
+			for ; bit&7 == 0 && 32 <= len(pix); bit += 8 {
+				p := pix[:32:32]
+				row[bit>>3] = uint8((uint32(p[0])+100)>>8<<7 |
+					(uint32(p[4])+100)>>8<<6 |
+					(uint32(p[8])+100)>>8<<5 |
+					(uint32(p[12])+100)>>8<<4 |
+					(uint32(p[16])+100)>>8<<3 |
+					(uint32(p[20])+100)>>8<<2 |
+					(uint32(p[24])+100)>>8<<1 |
+					(uint32(p[28])+100)>>8)
+				pix = pix[32:]
+			}
+			var u uint32
+			for i := 0; i < len(pix); i += 4 {
+				u = u<<1 | (uint32(pix[i])+100)>>8
+				if bit++; bit&7 == 0 {
+					row[bit>>3-1] |= uint8(u)
+					u = 0
+				}
+			}
+			if n := bit & 7; n != 0 {
+				row[bit>>3] |= uint8(u << (8 - n))
+			}
+		}
+		row = row[rowBytes:]
+	}
+	return dst
+}
+
 // Release releases font resources.
 // A font can no longer be used for rendering after this call completes.
 func (f *Font) Release() {
+	f.releasePages()
+	if f.Config != nil {
+		stats.fontsReleased.Add(1)
+	}
+	if f.img != nil {
+		stats.atlasBytes.Add(-int64(len(f.img.Pix)))
+	}
 	f.Config = nil
+	f.img = nil
+	f.bitmaps = nil
+	f.added = nil
+	f.missing = nil
+	f.lru = nil
+	if f.source != nil {
+		f.source.face.Close()
+		f.source = nil
+	}
 }
 
-// Printf draws the given string at the specified coordinates.
+// Printf draws the given string at the specified coordinates, which
+// are the bottom left corner of the first glyph cell, or the top left
+// corner with OriginTopLeft, see SetOrigin.
 // It expects the string to be a single line. Line breaks are not
 // handled as line breaks and are rendered as glyphs.
 //
 // In order to render multi-line text, it is up to the caller to split
 // the text up into individual lines of adequate length and then call
 // this method for each line seperately.
+//
+// At most MaxGlyphs glyphs are drawn, if the limit is set.
+//
+// Glyph bitmaps are passed to OpenGL as pointers into Go memory. This is
+// allowed by the cgo pointer rules, because the memory holds no Go pointers
+// and OpenGL copies a bitmap before gl.Bitmap returns. The bitmaps live in
+// a single buffer of the font, which is kept alive for the whole call.
+//
+// A font with a queue, see SetQueue, queues the string instead, then
+// Printf may be called from any goroutine. The queue reads the current
+// color and most settings of the font at Flush, see Queue.
 func (f *Font) Printf(x, y float32, str string) error {
+	return f.printOptions(x, y, str, f.options())
+}
+
+// drawOptions are settings of drawing of a single string, taken from
+// the font by default. Widgets pass their own options to draw, so fonts
+// shared with other widgets and goroutines are never changed.
+type drawOptions struct {
+	bidi BidiMode  // reordering of the string
+	rtl  bool      // base direction of BidiAuto lines without strong characters
+	dir  Direction // direction of the pen
+}
+
+// options returns the draw options of the font.
+func (f *Font) options() drawOptions {
+	return drawOptions{bidi: f.Bidi, dir: f.Direction}
+}
+
+// printOptions draws or queues the string as Printf with the options.
+func (f *Font) printOptions(x, y float32, str string, o drawOptions) error {
+	if q := f.queue; q != nil {
+		q.push(queueCmd{font: f, x: x, y: y, str: str, opt: o})
+		return nil
+	}
+	return f.printf(x, y, str, o)
+}
+
+// printf draws the string as Printf with the options.
+func (f *Font) printf(x, y float32, str string, o drawOptions) error {
+	sp := startSpan(StageDraw)
+	defer sp.end()
+
+	if !viewportVisible() {
+		// nothing is visible, for example a minimized window
+		return nil
+	}
+	f.record(x, y, str, nil)
+	if beginQuads() {
+		defer gl.PopAttrib()
+	}
+	if err := f.draw(x, y, str, nil, o); err != nil {
+		return err
+	}
+	return checkGLError("Printf")
+}
+
+// PrintfColors draws the string as Printf, with the color of every rune
+// taken from colors, which is parallel to the runes of the string: the
+// n-th rune is drawn by colors[n]. Runes without a color are drawn by the
+// current color, which is not changed by the call.
+//
+// This colors text by data, for example per-residue scores or severity
+// of log messages, without splitting it into several calls.
+//
+// A font with a queue queues the string as Printf, colors are copied.
+func (f *Font) PrintfColors(x, y float32, str string, colors []color.RGBA) error {
+	if q := f.queue; q != nil {
+		q.push(queueCmd{font: f, x: x, y: y, str: str, colors: append([]color.RGBA{}, colors...), opt: f.options()})
+		return nil
+	}
+	return f.printfColors(x, y, str, colors, f.options())
+}
+
+// printfColors draws the string as PrintfColors with the options.
+func (f *Font) printfColors(x, y float32, str string, colors []color.RGBA, o drawOptions) error {
+	sp := startSpan(StageDraw)
+	defer sp.end()
+
+	if !viewportVisible() {
+		return nil
+	}
+	f.record(x, y, str, colors)
+	if beginQuads() {
+		defer gl.PopAttrib()
+	}
+	gl.PushAttrib(gl.CURRENT_BIT)
+	err := f.draw(x, y, str, colors, o)
+	gl.PopAttrib()
+	if err != nil {
+		return err
+	}
+	return checkGLError("PrintfColors")
+}
+
+// A PlacedGlyph is a glyph of a string placed by EachGlyph.
+type PlacedGlyph struct {
+	Index   int         // index of the rune in the string
+	Offset  int         // byte offset of the rune in the string
+	Rune    rune        // the rune, the first one of a ligature
+	Text    string      // runes drawn by the glyph, see Ligatures
+	X       int32       // offset of the left edge of the glyph from x of Printf, see Direction
+	Y       int32       // offset of the glyph up from its place in the line, by shapers
+	Glyph   *Glyph      // glyph of the rune
+	Step    int32       // distance the pen moves over the glyph, see SetAdvance
+	Rotated bool        // the glyph is turned sideways in vertical text, see Sideways
+	Data    interface{} // data of the rune, see SetRuneData
+}
+
+// EachGlyph calls fn for every rune of the single line string with the
+// glyph placed as Printf places it, until fn returns false. It is the
+// base of drawing, so effects which draw glyphs themselves, for example
+// with scaling, place them the same way as Printf.
+func (f *Font) EachGlyph(str string, fn func(g PlacedGlyph) bool) {
+	f.place(str, fn)
+}
+
+// place calls fn for glyphs of the string as EachGlyph and returns the
+// distance the pen moves over them, and false if fn stops it.
+func (f *Font) place(str string, fn func(g PlacedGlyph) bool) (int32, bool) {
+	return f.placeWith(str, f.options(), fn)
+}
+
+// placeWith calls fn for glyphs of the string as place with the options.
+func (f *Font) placeWith(str string, o drawOptions, fn func(g PlacedGlyph) bool) (int32, bool) {
+	switch o.dir {
+	case RightToLeft:
+		return f.placeRTL(str, o, fn)
+	case TopToBottom:
+		return f.placeTTB(str, o, fn)
+	}
+	return f.placeLTR(str, o, fn)
+}
+
+// placeLTR calls fn for glyphs of the string as place with the pen
+// moving to the right.
+func (f *Font) placeLTR(str string, o drawOptions, fn func(g PlacedGlyph) bool) (int32, bool) {
+	if o.bidi != BidiOff || !f.NoJoining {
+		return f.eachDisplay(str, o, fn)
+	}
+	return f.eachGlyph(str, fn)
+}
+
+// eachGlyph calls fn for glyphs of the string in logical order as place.
+func (f *Font) eachGlyph(str string, fn func(g PlacedGlyph) bool) (int32, bool) {
+	if f.Shaper != nil {
+		return f.eachShaped(str, fn)
+	}
+	var x int32
+	n := 0
+	prev := rune(-1)
+	for ib := 0; ib < len(str); {
+		c := f.cluster(str[ib:])
+		x += f.kerning(prev, c.first)
+		prev = c.last
+		gx := x + c.glyph.LeftBearing
+		if !fn(PlacedGlyph{Index: n, Offset: ib, Rune: c.first, Text: str[ib : ib+c.bytes], X: gx, Glyph: c.glyph, Step: c.step, Data: f.data[c.first]}) {
+			return x, false
+		}
+		x += c.step
+		n += c.runes
+		ib += c.bytes
+	}
+	return x, true
+}
+
+// limit reports whether the glyph is within MaxGlyphs and calls
+// Overflow for the first glyph out of the limit.
+func (f *Font) limit(str string, g PlacedGlyph) bool {
+	if f.MaxGlyphs <= 0 || g.Index < f.MaxGlyphs {
+		return true
+	}
+	if f.Overflow != nil {
+		f.Overflow(g.Index, g.Index+utf8.RuneCountInString(str[g.Offset:]))
+	}
+	return false
+}
+
+// draw draws glyphs of the string, colored by colors if it is not nil.
+// In the deterministic mode glyphs are drawn as textured quads, the state
+// is set by beginQuads.
+func (f *Font) draw(x, y float32, str string, colors []color.RGBA, o drawOptions) error {
 	// gl.PushAttrib(gl.LIST_BIT | gl.CURRENT_BIT | gl.ENABLE_BIT | gl.TRANSFORM_BIT)
-	{
-		offset := int32(0)
-		for ib, b := range str {
-			i := b - f.Config.Low
-			if 0 < ib {
-				offset += f.Config.Glyphs[int(str[ib-1])-int(f.Config.Low)].Width
-			}
-			gl.RasterPos2i(int32(x)+offset, int32(y))
-			gl.Bitmap(
-				f.Config.Glyphs[i].Width, f.Config.Glyphs[i].Height,
-				0.0, 0.0,
-				0.0, 0.0,
-				(*uint8)(gl.Ptr(&f.Config.Glyphs[i].BitmapData[0])),
-			)
+	var pages []texturePage
+	if deterministic {
+		var err error
+		if pages, err = f.sheetPages(); err != nil {
+			return err
+		}
+	}
+	bound := -1
+	colored := false
+	var drawn uint64
+	pen, all := f.placeWith(str, o, func(g PlacedGlyph) bool {
+		if !f.limit(str, g) {
+			return false
+		}
+		glyph := g.Glyph
+		if len(glyph.BitmapData) == 0 {
+			// nothing to draw, for example a zero width glyph
+			return true
 		}
+		if g.Index < len(colors) {
+			// the raster color is taken by gl.RasterPos
+			c := colors[g.Index]
+			gl.Color4ub(c.R, c.G, c.B, c.A)
+			colored = true
+		} else if colored {
+			// restore the color saved by PrintfColors
+			gl.PopAttrib()
+			gl.PushAttrib(gl.CURRENT_BIT)
+			colored = false
+		}
+		bottom := above(cellBottom(y, float32(glyph.cell())), float32(glyph.BottomBearing+g.Y))
+		// both paths round alike, so they draw at the same pixels
+		px, py := f.rounding().Round(x)+g.X, f.rounding().Round(bottom)
+		if pages != nil {
+			f.drawQuad(pages, &bound, glyph, px, py)
+			drawn++
+			return true
+		}
+		gl.RasterPos2i(px, py)
+		gl.Bitmap(
+			glyph.Width, glyph.Height,
+			0.0, 0.0,
+			0.0, 0.0,
+			&glyph.BitmapData[0],
+		)
+		drawn++
+		return true
+	})
+	stats.glyphsDrawn.Add(drawn)
+	if all && o.dir != TopToBottom && o == f.options() {
+		checkPen(f, str, pen)
 	}
+	runtime.KeepAlive(f.bitmaps)
 	// gl.PopAttrib()
-	return checkGLError()
+	return nil
+}
+
+// SetupOrtho sets the viewport to the window size and an orthographic
+// projection with the origin selected by SetOrigin, which is the
+// coordinate system of Printf. Zero or negative sizes, for example of a
+// minimized window, are clamped, so no GL error is produced.
+//
+// The modelview matrix mode is selected after the call.
+func SetupOrtho(width, height int) {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	gl.Viewport(0, 0, int32(width), int32(height))
+	SetDisplaySize(width, height)
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	gl.MatrixMode(gl.PROJECTION)
+	gl.LoadIdentity()
+	if origin == OriginTopLeft {
+		gl.Ortho(0, float64(width), float64(height), 0, -1.0, 1.0)
+	} else {
+		gl.Ortho(0, float64(width), 0, float64(height), -1.0, 1.0)
+	}
+	gl.MatrixMode(gl.MODELVIEW)
+}
+
+// display holds the viewport size given by SetDisplaySize.
+var display struct {
+	known         bool
+	width, height int
+}
+
+// SetDisplaySize tells the package the current viewport size, which
+// should be updated on every window resize. Then drawing does not query
+// GL_VIEWPORT, which is a synchronous roundtrip to the driver.
+// A negative size forgets the size and the viewport is queried again.
+//
+// SetupOrtho calls SetDisplaySize itself.
+func SetDisplaySize(width, height int) {
+	display.known = 0 <= width && 0 <= height
+	display.width, display.height = width, height
+}
+
+// viewportSize returns the size of the current viewport.
+func viewportSize() (width, height int) {
+	if display.known {
+		return display.width, display.height
+	}
+	var vp [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &vp[0])
+	return int(vp[2]), int(vp[3])
+}
+
+// viewportVisible reports whether the current viewport has an area.
+func viewportVisible() bool {
+	w, h := viewportSize()
+	return 0 < w && 0 < h
+}
+
+// advanceSize returns the distance the pen moves while Printf
+// draws the given string.
+//
+// It is called by every layout, so glyphs of the range are looked up
+// in place and the string is not converted to runes.
+func (f *Font) advanceSize(str string) (size int32) {
+	if f.Bidi != BidiOff || !f.NoJoining {
+		// kerning and ligatures apply to neighbours on the screen
+		str, _ = f.display(str, f.options())
+	}
+	if f.Shaper != nil {
+		return f.shapedSize(str)
+	}
+	gs, low := f.Config.Glyphs, f.Config.Low
+	prev := rune(-1)
+	if len(f.Config.Ranges) != 0 || f.substitutes() || len(f.advances) != 0 || f.Ligatures {
+		for i := 0; i < len(str); {
+			c := f.cluster(str[i:])
+			size += c.step + f.kerning(prev, c.first)
+			prev = c.last
+			i += c.bytes
+		}
+		return
+	}
+	for _, r := range str {
+		if i := uint(r - low); i < uint(len(gs)) {
+			size += gs[i].step()
+		} else {
+			size += f.outGlyph(r).step()
+		}
+		size += f.kerning(prev, r)
+		prev = r
+	}
+	return
 }
 
-// Pow2 returns the first power-of-two value >= to n.
+// Pow2 returns the first power-of-two value >= x, and 1 for zero.
 // This can be used to create suitable texture dimensions.
+// Values above 1<<31 have no power of two of uint32, Pow2 returns zero
+// for them, see Pow2U64.
 func Pow2(x uint32) uint32 {
-	x--
-	x |= x >> 1
-	x |= x >> 2
-	x |= x >> 4
-	x |= x >> 8
-	x |= x >> 16
-	return x + 1
+	if 1<<31 < x {
+		return 0
+	}
+	if x <= 1 {
+		return 1
+	}
+	return 1 << bits.Len32(x-1)
+}
+
+// Pow2U64 returns the first power-of-two value >= x as Pow2, and zero
+// for values above 1<<63.
+func Pow2U64(x uint64) uint64 {
+	if 1<<63 < x {
+		return 0
+	}
+	if x <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(x-1)
+}
+
+// IsPow2 reports whether x is a power of two. Zero is not.
+func IsPow2(x uint32) bool {
+	return x != 0 && x&(x-1) == 0
+}
+
+// maxSheetSize is the largest width or height of a sprite sheet.
+const maxSheetSize = 1 << 15
+
+// sheetSize returns power-of-two dimensions of a sprite sheet holding
+// width by height pixels, or an error if the sheet is too large.
+func sheetSize(width, height int64) (w, h int, err error) {
+	pw, ph := Pow2U64(uint64(width)), Pow2U64(uint64(height))
+	if width < 0 || height < 0 || maxSheetSize < pw || maxSheetSize < ph {
+		return 0, 0, fmt.Errorf("glsymbol: sprite sheet of %dx%d pixels is larger than %d pixels",
+			width, height, maxSheetSize)
+	}
+	return int(pw), int(ph), nil
+}
+
+// LoadBitmap loads a raster font from a sprite sheet, for example
+// a pre-rendered fixed-width or variable-width bitmap font. The config
+// describes the glyphs, their rectangles are relative to the top left
+// corner of the image. Glyphs are white, or any light color, on a dark
+// or transparent background. The image and the config are copied, so
+// they may be changed after the call.
+func LoadBitmap(img image.Image, config *FontConfig) (*Font, error) {
+	if config == nil {
+		return nil, fmt.Errorf("glsymbol: no font config")
+	}
+	b := img.Bounds()
+	sheet := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(sheet, sheet.Bounds(), img, b.Min, draw.Src)
+	if err := config.Validate(sheet.Bounds()); err != nil {
+		return nil, err
+	}
+	fc := *config
+	fc.Glyphs = append(Charset(nil), config.Glyphs...)
+	return loadFont(sheet, &fc)
 }
 
 // http://www.freetype.org/freetype2/docs/tutorial/step2.html
 
 // LoadTruetype loads a truetype font from the given stream and
-// applies the given font scale in points.
+// applies the given font scale in points. OpenType fonts with CFF
+// outlines, usually .otf files, are loaded as well.
 //
 // The low and high values determine the lower and upper rune limits
 // we should load for this font. For standard ASCII this would be: 32, 127.
@@ -209,38 +855,64 @@ func LoadTruetype(r io.Reader, scale int32, low, high rune) (_ *Font, err error)
 	if err != nil {
 		return nil, err
 	}
+	return LoadTruetypeBytes(data, scale, low, high)
+}
 
-	// Read the truetype font.
-	ttf, err := truetype.Parse(data)
-	if err != nil {
-		return nil, err
-	}
+// LoadTruetypeBytes loads a truetype or OpenType font from the data as
+// LoadTruetype, for fonts which are already in memory. The data is not
+// modified. Use NewFaceBytes to parse the data once for many scales.
+func LoadTruetypeBytes(data []byte, scale int32, low, high rune) (*Font, error) {
+	return LoadTruetypeRanges(data, scale, RuneRange{low, high})
+}
 
+// rasterizeTruetype draws glyphs of runes of the ranges into a sprite
+// sheet with pad blank pixels around glyph cells. It stops with the error
+// of the context once it is done.
+func rasterizeTruetype(ctx context.Context, ttf *truetype.Font, scale int32, ranges []RuneRange, pad int) (_ *image.RGBA, _ *FontConfig, err error) {
 	// Create our FontConfig type.
-	var fc FontConfig
-	fc.Low = low
-	fc.High = high
-	fc.Glyphs = make(Charset, high-low+1)
-
-	// Create an image, large enough to store all requested glyphs.
-	//
-	// We limit the image to 16 glyphs per row. Then add as many rows as
-	// needed to encompass all glyphs, while making sure the resulting image
-	// has power-of-two dimensions.
-	gc := int32(len(fc.Glyphs))
-	glyphsPerRow := int32(16)
-	glyphsPerCol := (gc / glyphsPerRow) + 1
+	fc, err := newRangeConfig(ranges)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	// Cells are as high as the bounds of all glyphs, bitmaps hold only
+	// the ink of every glyph and are packed into rows of a sprite sheet
+	// with power-of-two dimensions.
 	gb := ttf.Bounds(fixed.Int26_6(scale))
-	gw := int32(gb.Max.X - gb.Min.X)
 	gh := int32((gb.Max.Y - gb.Min.Y) + 5)
-	iw := Pow2(uint32(gw * glyphsPerRow))
-	ih := Pow2(uint32(gh * glyphsPerCol))
 
-	rect := image.Rect(0, 0, int(iw), int(ih))
-	img := image.NewRGBA(rect)
+	// glyphs are drawn with the baseline below the middle of cells,
+	// bitmaps start from the row below the top of a cell
+	baseline := int(fixed.Int26_6(scale<<6) >> 8)
+	pen := int(gh/2) + baseline
+
+	// bounds of ink of glyphs, relative to the pen, Y goes up
+	var buf truetype.GlyphBuf
+	left := make([]int, len(fc.Glyphs))
+	widths := make([]int, len(fc.Glyphs))
+	heights := make([]int, len(fc.Glyphs))
+	tops := make([]int, len(fc.Glyphs))
+	for i := range fc.Glyphs {
+		err = buf.Load(ttf, fixed.Int26_6(scale<<6), ttf.Index(fc.RuneAt(i)), font.HintingNone)
+		if err != nil {
+			return nil, nil, err
+		}
+		heights[i] = int(gh)
+		if b := buf.Bounds; b.Min.X < b.Max.X {
+			left[i] = b.Min.X.Floor()
+			widths[i] = b.Max.X.Ceil() - left[i]
+			tops[i], heights[i] = tightRows(pen-b.Max.Y.Ceil(), pen-b.Min.Y.Floor(), int(gh))
+		}
+	}
+	pos, size, err := packCells(widths, heights, pad)
+	if err != nil {
+		return nil, nil, err
+	}
+	img := image.NewRGBA(image.Rectangle{Max: size})
 
 	// Use a freetype context to do the drawing.
+	sp := startSpan(StageRasterize)
+	defer sp.end()
 	c := freetype.NewContext()
 	c.SetDPI(72)
 	c.SetFont(ttf)
@@ -248,41 +920,36 @@ func LoadTruetype(r io.Reader, scale int32, low, high rune) (_ *Font, err error)
 	c.SetClip(img.Bounds())
 	c.SetDst(img)
 	c.SetSrc(image.White)
+	fc.Baseline = gh - gh/2 - int32(baseline) + 1
 
-	// Iterate over all relevant glyphs in the truetype font and
-	// draw them all to the image buffer.
-	//
-	// For each glyph, we also create a corresponding Glyph structure
-	// for our Charset. It contains the appropriate glyph coordinate offsets.
-	var gi int
-	var gx, gy int32
-
-	for ch := low; ch <= high; ch++ {
-		index := ttf.Index(ch)
-		metric := ttf.HMetric(fixed.Int26_6(scale), index)
-
-		fc.Glyphs[gi].Advance = int32(metric.AdvanceWidth)
-		fc.Glyphs[gi].X = int32(gx)
-		fc.Glyphs[gi].Y = int32(gy) - int32(gh)/2 // shif up half a row so that we actually get the character in frame
-		fc.Glyphs[gi].Width = int32(gw)
-		fc.Glyphs[gi].Height = int32(gh)
-		pt := freetype.Pt(int(gx), int(gy)+int(c.PointToFixed(float64(scale))>>8))
-		_, err = c.DrawString(string(ch), pt)
-		if err != nil {
-			err = fmt.Errorf("DrawString: %v", err)
-			return
+	for i := range fc.Glyphs {
+		if i%16 == 0 {
+			if err = ctx.Err(); err != nil {
+				return nil, nil, err
+			}
 		}
-
-		if gi%16 == 0 {
-			gx = 0
-			gy += gh
-		} else {
-			gx += gw
+		ch := fc.RuneAt(i)
+		metric := ttf.HMetric(fixed.Int26_6(scale), ttf.Index(ch))
+		advance := int32(metric.AdvanceWidth)
+		at := pos[i]
+		fc.Glyphs[i] = Glyph{
+			X: int32(at.X), Y: int32(at.Y),
+			Width: int32(widths[i]), Height: int32(heights[i]),
+			Advance:       advance,
+			LeftBearing:   int32(left[i]),
+			RightBearing:  advance - int32(left[i]+widths[i]),
+			TopBearing:    int32(tops[i]),
+			BottomBearing: gh - int32(tops[i]+heights[i]),
+		}
+		if widths[i] == 0 {
+			continue
+		}
+		pt := freetype.Pt(at.X-left[i], at.Y+pen-tops[i])
+		if _, err = c.DrawString(string(ch), pt); err != nil {
+			return nil, nil, fmt.Errorf("DrawString: %v", err)
 		}
-
-		gi++
 	}
-	return loadFont(img, &fc)
+	return img, fc, nil
 }
 
 // GlyphBounds returns the largest width and height for any of the glyphs
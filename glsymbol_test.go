@@ -1,13 +1,16 @@
 package glsymbol
 
 import (
+	"bytes"
 	"fmt"
+	"image/color"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"golang.org/x/image/font/gofont/goregular"
 )
 
 func TestDefault(t *testing.T) {
@@ -53,17 +56,7 @@ func TestDefault(t *testing.T) {
 		gl.ClearColor(0, 0, 0, 0)
 
 		w, h := window.GetSize()
-		if w < 10 || h < 10 {
-			// TODO: fix resizing window
-			// PROBLEM with text rendering
-			continue
-		}
-
-		gl.Viewport(0, 0, int32(w), int32(h))
-		gl.MatrixMode(gl.PROJECTION)
-		gl.LoadIdentity()
-		gl.Ortho(0, float64(w), 0, float64(h), -1.0, 1.0)
-		gl.MatrixMode(gl.MODELVIEW)
+		SetupOrtho(w, h)
 
 		// Render the string.
 		gl.Color4f(1, 1, 0, 1)
@@ -156,17 +149,7 @@ func Test(t *testing.T) {
 		gl.ClearColor(0, 0, 0, 0)
 
 		w, h := window.GetSize()
-		if w < 10 || h < 10 {
-			// TODO: fix resizing window
-			// PROBLEM with text rendering
-			continue
-		}
-
-		gl.Viewport(0, 0, int32(w), int32(h))
-		gl.MatrixMode(gl.PROJECTION)
-		gl.LoadIdentity()
-		gl.Ortho(0, float64(w), 0, float64(h), -1.0, 1.0)
-		gl.MatrixMode(gl.MODELVIEW)
+		SetupOrtho(w, h)
 
 		for id := range fonts {
 			for i, size := 0, 15; i < size; i++ {
@@ -197,3 +180,206 @@ func Test(t *testing.T) {
 		// break // one iteration
 	}
 }
+
+// initWindow creates a hidden window with current OpenGL context
+// for tests drawing a single frame.
+func initWindow(t *testing.T) (terminate func()) {
+	t.Helper()
+	if err := glfw.Init(); err != nil {
+		t.Fatalf("failed to initialize glfw: %v", err)
+	}
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	glfw.WindowHint(glfw.ContextVersionMajor, 2)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	window, err := glfw.CreateWindow(300, 300, "test", nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		t.Fatal(err)
+	}
+	window.MakeContextCurrent()
+	if err = gl.Init(); err != nil {
+		glfw.Terminate()
+		t.Fatal(err)
+	}
+	SetupOrtho(300, 300)
+	return glfw.Terminate
+}
+
+func TestMaxGlyphs(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	var drawn, total int
+	font.MaxGlyphs = 5
+	font.Overflow = func(d, t int) {
+		drawn, total = d, t
+	}
+	if err := font.Printf(10, 10, "Hello world"); err != nil {
+		t.Fatal(err)
+	}
+	if drawn != 5 || total != 11 {
+		t.Errorf("unexpected overflow: %d of %d", drawn, total)
+	}
+
+	drawn, total = 0, 0
+	if err := font.Printf(10, 10, "Hello"); err != nil {
+		t.Fatal(err)
+	}
+	if drawn != 0 || total != 0 {
+		t.Errorf("unexpected overflow: %d of %d", drawn, total)
+	}
+}
+
+// TestPrintfPointers checks pointers passed to OpenGL by Printf.
+// Run it with the race detector and cgo checks:
+//
+//	GODEBUG=cgocheck=1 go test -race -run TestPrintfPointers
+func TestPrintfPointers(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	// glyph without bitmap
+	space := &font.Config.Glyphs[' '-font.Config.Low]
+	space.Width = 0
+	space.BitmapData = nil
+
+	for i := 0; i < 100; i++ {
+		if err := font.Printf(10, 10, "Hello world "); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// bitmaps of neighbour glyphs are independent
+	a := font.Config.Glyphs['a'-font.Config.Low].BitmapData
+	b := font.Config.Glyphs['b'-font.Config.Low].BitmapData
+	b0 := b[0]
+	_ = append(a, 0xFF)
+	if b[0] != b0 {
+		t.Errorf("append to bitmap data overwrites neighbour glyph")
+	}
+}
+
+func TestZeroViewport(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	for _, size := range [][2]int{{0, 0}, {0, 300}, {1, 1}, {5, 0}, {-1, -1}, {300, 300}} {
+		SetupOrtho(size[0], size[1])
+		if err := font.Printf(10, 10, "Hello world"); err != nil {
+			t.Errorf("size %v: %v", size, err)
+		}
+	}
+}
+
+func TestPrintfColors(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	gl.Color4f(0, 0, 1, 1)
+	colors := []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}}
+	if err := font.PrintfColors(10, 10, "MMM", colors); err != nil {
+		t.Fatal(err)
+	}
+
+	var current [4]float32
+	gl.GetFloatv(gl.CURRENT_COLOR, &current[0])
+	if current != [4]float32{0, 0, 1, 1} {
+		t.Errorf("current color is changed: %v", current)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := DefaultFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+
+	o, err := NewOverlay(64, 32, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Release()
+
+	if err := o.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	gl.Color4f(1, 1, 1, 1)
+	if err := font.Printf(0, 0, "MMMM"); err != nil {
+		t.Fatal(err)
+	}
+	pix := make([]uint8, 64*32*4)
+	gl.ReadPixels(0, 0, 64, 32, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	if err := o.End(); err != nil {
+		t.Fatal(err)
+	}
+	var opaque, transparent int
+	for i := 3; i < len(pix); i += 4 {
+		switch pix[i] {
+		case 0xff:
+			opaque++
+		case 0:
+			transparent++
+		}
+	}
+	if opaque == 0 || transparent == 0 || opaque+transparent != 64*32 {
+		t.Errorf("alpha of the overlay: %d opaque, %d transparent", opaque, transparent)
+	}
+}
+
+func TestLoadBitmap(t *testing.T) {
+	defer initWindow(t)()
+
+	img, config := testAtlas()
+	font, err := LoadBitmap(img, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+	if config.Glyphs[0].BitmapData != nil {
+		t.Errorf("config of the caller is changed")
+	}
+	if w := font.advanceSize("ab"); w != 15 {
+		t.Errorf("advance size %d", w)
+	}
+	if err := font.Printf(0, 0, "ab"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTruetypeCollection(t *testing.T) {
+	defer initWindow(t)()
+
+	font, err := LoadTruetypeCollection(bytes.NewReader(goregular.TTF), 0, 16, 32, 127)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer font.Release()
+	if err := font.Printf(0, 0, "collection"); err != nil {
+		t.Fatal(err)
+	}
+}
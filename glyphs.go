@@ -0,0 +1,229 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+)
+
+// glyph returns the glyph of the rune. Runes out of the range of Config
+// have glyphs added to the font, rasterized on demand or supplied by
+// Fallback, other runes have the glyph of the Unknown policy.
+func (f *Font) glyph(r rune) *Glyph {
+	if f.substitutes() {
+		if g := f.substitute(r); g != nil {
+			return g
+		}
+	}
+	// runes below Low wrap around to large unsigned indexes
+	c := f.Config
+	if len(c.Ranges) == 0 {
+		if i := uint(r - c.Low); i < uint(len(c.Glyphs)) {
+			return &c.Glyphs[i]
+		}
+	} else if i := c.Index(r); 0 <= i {
+		return &c.Glyphs[i]
+	}
+	return f.outGlyph(r)
+}
+
+// outGlyph returns the glyph of the rune out of the range of Config.
+func (f *Font) outGlyph(r rune) *Glyph {
+	c := f.Config
+	if g, ok := f.added[r]; ok {
+		countCache(true)
+		f.touch(r)
+		if g == nil {
+			// Fallback has no image for the rune
+			return f.unknownGlyph()
+		}
+		return g
+	}
+	if g := f.sourceGlyph(r); g != nil {
+		return g
+	}
+	if f.Fallback == nil {
+		f.warnMissing(r)
+		return f.unknownGlyph()
+	}
+	countCache(false)
+	img := f.Fallback(r)
+	if img == nil {
+		if f.added == nil {
+			f.added = make(map[rune]*Glyph)
+		}
+		f.added[r] = nil
+		warn("glsymbol: rune has no glyph", "rune", r)
+		return f.unknownGlyph()
+	}
+	warn("glsymbol: glyph supplied by fallback", "rune", r)
+	// the image stands on the baseline, or on the bottom of the cell
+	// if it is too high for that, and is cropped at the top of the cell
+	h := f.cellHeight(img)
+	top := h - int(c.Baseline) - img.Bounds().Dy()
+	if top < 0 {
+		top = h - img.Bounds().Dy()
+	}
+	g, err := f.addImage(img, top, 0, img.Bounds().Dx())
+	if err != nil {
+		warn("glsymbol: glyph is not added", "rune", r, "error", err)
+		return f.unknownGlyph()
+	}
+	f.addedGlyph(r, g)
+	f.evictable(r)
+	return g
+}
+
+// GlyphMetrics places an image added by AddGlyph relative to the pen
+// position on the baseline. Values are in pixels.
+type GlyphMetrics struct {
+	BearingX int32 // from the pen position right to the left edge of the image
+	BearingY int32 // from the baseline up to the top edge of the image
+	Advance  int32 // distance the pen moves over the glyph
+}
+
+// AddGlyph adds the image as the glyph of the rune, replacing a glyph
+// of the font, so custom symbols, for example icons in the private use
+// area, flow through text layout and drawing as normal glyphs. Pixels
+// with alpha of at least a half are drawn, the part of the image out of
+// glyph cells of the font is cropped.
+func (f *Font) AddGlyph(r rune, img *image.Alpha, metrics GlyphMetrics) error {
+	switch {
+	case f.Config == nil:
+		return fmt.Errorf("glsymbol: font is released")
+	case img == nil:
+		return fmt.Errorf("glsymbol: no image of glyph %q", r)
+	case metrics.Advance < 0:
+		return fmt.Errorf("glsymbol: negative advance %d of glyph %q", metrics.Advance, r)
+	}
+	h := f.cellHeight(img)
+	top := h - int(f.Config.Baseline) - int(metrics.BearingY)
+	g, err := f.addImage(img, top, int(metrics.BearingX), int(metrics.Advance))
+	if err != nil {
+		return err
+	}
+	if c := f.Config; c.Has(r) {
+		c.Glyphs[c.Index(r)] = *g
+		f.identity = 0
+		f.avgStep = 0
+		return nil
+	}
+	f.addedGlyph(r, g)
+	delete(f.lru, r) // the glyph can not be rasterized again
+	return nil
+}
+
+// cellHeight returns the height of glyph cells of the font, which is
+// the height of the image for a font without glyphs.
+func (f *Font) cellHeight(img image.Image) int {
+	if h := int(f.MaxGlyphHeight); 0 < h {
+		return h
+	}
+	return img.Bounds().Dy()
+}
+
+// addedGlyph stores the glyph of a rune out of the range of Config.
+func (f *Font) addedGlyph(r rune, g *Glyph) {
+	if f.added == nil {
+		f.added = make(map[rune]*Glyph)
+	}
+	f.added[r] = g
+	f.identity = 0
+}
+
+// addImage packs the image into the sprite sheet and returns its glyph. Pixels with alpha of at least a half are
+// drawn. The image is placed in the glyph cell at the distance top from
+// its top edge and left from the pen position, the pen moves by advance.
+//
+// Added glyphs are placed in rows at the bottom of the sheet, which
+// grows as needed, up to Budget. The texture of the sheet is updated on
+// the next use.
+func (f *Font) addImage(img image.Image, top, left, advance int) (*Glyph, error) {
+	if err := faultError("addImage"); err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), f.cellHeight(img)
+
+	var sheet image.Rectangle
+	if f.img != nil {
+		sheet = f.img.Bounds()
+	}
+	shelf := f.shelf
+	if shelf == (image.Point{}) || sheet.Dx() < shelf.X+w {
+		// start a new row below the sheet
+		shelf = image.Pt(0, sheet.Dy())
+	}
+	size := image.Pt(sheet.Dx(), sheet.Dy())
+	if size.X < shelf.X+w {
+		size.X = shelf.X + w
+	}
+	if size.Y < shelf.Y+h+1 {
+		size.Y = shelf.Y + h + 1
+	}
+	at := shelf
+	if size != sheet.Size() && f.overBudget(size) {
+		slot, ok := f.evict(w, h)
+		if !ok {
+			return nil, &BudgetError{Budget: f.Budget, Need: sheetBytes(size)}
+		}
+		at, size = slot, sheet.Size()
+	} else {
+		f.shelf = image.Pt(shelf.X+w, shelf.Y)
+	}
+	if size != sheet.Size() {
+		warn("glsymbol: sprite sheet grows", "width", size.X, "height", size.Y)
+		grown := image.NewRGBA(image.Rectangle{Max: size})
+		if f.img != nil {
+			draw.Draw(grown, sheet, f.img, image.Point{}, draw.Src)
+			stats.atlasBytes.Add(-int64(len(f.img.Pix)))
+		}
+		stats.atlasBytes.Add(int64(len(grown.Pix)))
+		f.img = grown
+	}
+
+	// every cell has a blank row above it, see loadFont
+	for py := 0; py < b.Dy(); py++ {
+		cy := top + py
+		if cy < 0 || h <= cy {
+			continue
+		}
+		for px := 0; px < w; px++ {
+			a := color.AlphaModel.Convert(img.At(b.Min.X+px, b.Min.Y+py)).(color.Alpha).A
+			if 0x80 <= a {
+				f.img.Set(at.X+px, at.Y+1+cy, color.White)
+			}
+		}
+	}
+
+	g := &Glyph{
+		X: int32(at.X), Y: int32(at.Y),
+		Width: int32(w), Height: int32(h),
+		Advance:      int32(advance),
+		LeftBearing:  int32(left),
+		RightBearing: int32(advance - left - w),
+	}
+	g.BitmapData = glyphBitmap(nil, f.img, g)
+	if f.MaxGlyphWidth < g.Width {
+		f.MaxGlyphWidth = g.Width
+	}
+	if f.MaxGlyphHeight < g.cell() {
+		f.MaxGlyphHeight = g.cell()
+	}
+	f.stale = true
+	return g, nil
+}
+
+// addedRunes returns runes of added glyphs in increasing order.
+func (f *Font) addedRunes() []rune {
+	rs := make([]rune, 0, len(f.added))
+	for r, g := range f.added {
+		if g != nil {
+			rs = append(rs, r)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i] < rs[j] })
+	return rs
+}
@@ -0,0 +1,154 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	f := testFont()
+	f.Config.Baseline = 4
+	calls := map[rune]int{}
+	f.Fallback = func(r rune) image.Image {
+		calls[r]++
+		if r != '★' {
+			return nil
+		}
+		img := image.NewAlpha(image.Rect(10, 10, 15, 16))
+		img.SetAlpha(10, 10, color.Alpha{0xff}) // top left
+		img.SetAlpha(14, 15, color.Alpha{0x7f}) // below the half
+		return img
+	}
+	id := f.Identity()
+
+	if w := f.advanceSize("a★b★"); w != 8+5+8+5 {
+		t.Errorf("advance %d", w)
+	}
+	if w := f.advanceSize("é"); w != 0 {
+		t.Errorf("advance of missing rune %d", w)
+	}
+	f.advanceSize("éé")
+	if calls['★'] != 1 || calls['é'] != 1 {
+		t.Errorf("calls %v", calls)
+	}
+	if f.Identity() == id {
+		t.Errorf("identity is not changed")
+	}
+
+	g := f.glyph('★')
+	if g.Width != 5 || g.Height != 16 || g.Advance != 5 || g.step() != 5 {
+		t.Fatalf("glyph %+v", g)
+	}
+	// the image stands on the baseline
+	top := int(g.Y) + 1 + 16 - 4 - 6
+	if c := f.img.RGBAAt(int(g.X), top); c.R != 0xff {
+		t.Errorf("top left pixel is not drawn")
+	}
+	if c := f.img.RGBAAt(int(g.X)+4, top+5); c.R != 0 {
+		t.Errorf("pixel below the half is drawn")
+	}
+	if len(g.BitmapData) == 0 || !f.stale {
+		t.Errorf("glyph is not prepared for drawing")
+	}
+}
+
+func TestFallbackShelf(t *testing.T) {
+	f := testFont()
+	f.img = image.NewRGBA(image.Rect(0, 0, 12, 17))
+	f.Fallback = func(r rune) image.Image {
+		return image.NewAlpha(image.Rect(0, 0, 5, 20))
+	}
+	a, b, c := *f.glyph('α'), *f.glyph('β'), *f.glyph('γ')
+	if a.X != 0 || a.Y != 17 || b.X != 5 || b.Y != 17 || c.X != 0 || c.Y != 34 {
+		t.Errorf("glyphs are placed at %d,%d %d,%d %d,%d", a.X, a.Y, b.X, b.Y, c.X, c.Y)
+	}
+	if s := f.img.Bounds().Size(); s != image.Pt(12, 51) {
+		t.Errorf("sheet size %v", s)
+	}
+}
+
+func TestAddGlyph(t *testing.T) {
+	f := testFont()
+	f.Config.Baseline = 4
+	img := image.NewAlpha(image.Rect(0, 0, 6, 3))
+	img.SetAlpha(0, 0, color.Alpha{0xff})
+
+	id, avg := f.Identity(), f.averageStep()
+	if err := f.AddGlyph('\ue000', img, GlyphMetrics{BearingX: 1, BearingY: 5, Advance: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.advanceSize("a\ue000"); w != 8+9 {
+		t.Errorf("advance %d", w)
+	}
+	g := f.glyph('\ue000')
+	if g.LeftBearing != 1 || g.RightBearing != 2 || g.Height != 16 {
+		t.Errorf("glyph %+v", g)
+	}
+	// top edge is 5 pixels above the baseline
+	if c := f.img.RGBAAt(int(g.X), int(g.Y)+1+16-4-5); c.R != 0xff {
+		t.Errorf("image is not placed by BearingY")
+	}
+	if f.Identity() == id {
+		t.Errorf("identity is not changed")
+	}
+
+	// replace a glyph of the range
+	id = f.Identity()
+	if err := f.AddGlyph('a', img, GlyphMetrics{Advance: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.advanceSize("a"); w != 20 {
+		t.Errorf("advance of replaced glyph %d", w)
+	}
+	if f.Identity() == id || f.averageStep() == avg {
+		t.Errorf("cached values are not changed")
+	}
+
+	if err := f.AddGlyph('b', nil, GlyphMetrics{}); err == nil {
+		t.Errorf("no error for nil image")
+	}
+	if err := f.AddGlyph('b', img, GlyphMetrics{Advance: -1}); err == nil {
+		t.Errorf("no error for negative advance")
+	}
+}
+
+func TestUnknownRune(t *testing.T) {
+	f := testFont()
+	f.Config.Glyphs['?'-f.Config.Low].Advance = 6
+	f.Config.Glyphs['?'-f.Config.Low].Width = 6
+	hash := f.TextHash("aé", nil)
+	for _, tc := range []struct {
+		policy UnknownRune
+		width  int32
+	}{
+		{UnknownZero, 0},
+		{UnknownMaxWidth, 8},
+		{UnknownAverage, 7}, // 92 glyphs of 8, 2 of 4 and 1 of 6 pixels, floored
+		{UnknownReplacement, 6},
+	} {
+		f.Unknown = tc.policy
+		if w := f.advanceSize("aé"); w != 8+tc.width {
+			t.Errorf("policy %d: advance %d", tc.policy, w)
+		}
+		// measured as drawn
+		var end int32
+		f.EachGlyph("aé", func(g PlacedGlyph) bool {
+			end = g.X - g.Glyph.LeftBearing + g.Glyph.step()
+			return true
+		})
+		if end != 8+tc.width {
+			t.Errorf("policy %d: drawn advance %d", tc.policy, end)
+		}
+		if 0 < tc.policy && f.TextHash("aé", nil) == hash {
+			t.Errorf("policy %d: text hash is not changed", tc.policy)
+		}
+	}
+
+	// runes without Fallback images follow the policy too
+	f.Unknown = UnknownMaxWidth
+	f.Fallback = func(rune) image.Image { return nil }
+	if w := f.advanceSize("éé"); w != 16 {
+		t.Errorf("advance with fallback %d", w)
+	}
+}
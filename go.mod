@@ -10,3 +10,5 @@ require (
 require github.com/go-gl/glfw/v3.3/glfw v0.0.0-20221017161538-93cebf72946b
 
 require github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+
+require golang.org/x/text v0.13.0
@@ -0,0 +1,44 @@
+package glsymbol
+
+import "math"
+
+// A BaselineGrid is a set of horizontal lines, which baselines of text
+// are snapped to. Fonts of different sizes sharing a grid place their
+// lines on the same baselines, so adjacent columns of text stay aligned.
+//
+// Grid lines are at Offset + k*Step for every integer k, in the
+// coordinates of drawing functions.
+type BaselineGrid struct {
+	Offset float32
+	Step   int32 // distance between grid lines, zero disables snapping
+}
+
+// snap moves the baseline down to the nearest grid line at or below it.
+func (g *BaselineGrid) snap(y float32) float32 {
+	k := float64(y-g.Offset) / float64(g.Step)
+	if origin == OriginTopLeft {
+		k = math.Ceil(k)
+	} else {
+		k = math.Floor(k)
+	}
+	return g.Offset + float32(k)*float32(g.Step)
+}
+
+// active reports whether the grid snaps baselines.
+func (g *BaselineGrid) active() bool {
+	return g != nil && 0 < g.Step
+}
+
+// lineY returns the coordinate to draw a line by Printf, whose top edge
+// is at y. Glyphs are vertically centered in the line and, if the font
+// has a baseline grid, moved down to the grid.
+func (f *Font) lineY(top float32) float32 {
+	h := float32(f.MaxGlyphHeight)
+	cellTop := below(top, float32(f.halfLeading()))
+	if f.Grid.active() {
+		descent := float32(f.Config.Baseline)
+		base := f.Grid.snap(below(cellTop, h-descent))
+		cellTop = above(base, h-descent)
+	}
+	return linePos(cellTop, h)
+}
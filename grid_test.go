@@ -0,0 +1,47 @@
+package glsymbol
+
+import "testing"
+
+func TestBaselineGrid(t *testing.T) {
+	grid := &BaselineGrid{Offset: 2, Step: 10}
+	small := testFont()
+	small.Config.Baseline = 4
+	small.Grid = grid
+	big := testFont()
+	big.MaxGlyphHeight = 24
+	big.Config.Baseline = 6
+	big.Grid = grid
+
+	if h := small.lineHeight(); h != 20 {
+		t.Errorf("line height of small font %d", h)
+	}
+	if h := big.lineHeight(); h != 30 {
+		t.Errorf("line height of big font %d", h)
+	}
+
+	for _, tc := range []struct {
+		origin     Origin
+		small, big float32 // baselines of lines with the top at 100
+	}{
+		{OriginBottomLeft, 82, 72},
+		{OriginTopLeft, 122, 122},
+	} {
+		SetOrigin(tc.origin)
+		for _, f := range []struct {
+			font   *Font
+			expect float32
+		}{
+			{small, tc.small},
+			{big, tc.big},
+		} {
+			// baseline of glyphs drawn at y
+			y := f.font.lineY(100)
+			base := above(cellBottom(y, float32(f.font.MaxGlyphHeight)), float32(f.font.Config.Baseline))
+			if base != f.expect {
+				t.Errorf("origin %d, font height %d: baseline %v, expect %v",
+					tc.origin, f.font.MaxGlyphHeight, base, f.expect)
+			}
+		}
+		SetOrigin(OriginBottomLeft)
+	}
+}
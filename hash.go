@@ -0,0 +1,144 @@
+package glsymbol
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"image/color"
+	"math"
+)
+
+// Hashes are 64-bit FNV-1a of a fixed little-endian encoding, so they
+// are the same in every run and on every platform. Applications key
+// their render-to-texture caches by TextHash, which changes whenever
+// caches of the package are invalidated: on a change of glyphs or of
+// settings changing the layout.
+
+// hashWriter encodes values into a hash.
+type hashWriter struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+func newHashWriter() *hashWriter {
+	return &hashWriter{h: fnv.New64a()}
+}
+
+func (w *hashWriter) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.buf[:], v)
+	w.h.Write(w.buf[:])
+}
+
+func (w *hashWriter) int32(v int32) {
+	binary.LittleEndian.PutUint32(w.buf[:4], uint32(v))
+	w.h.Write(w.buf[:4])
+}
+
+func (w *hashWriter) float32(v float32) {
+	w.int32(int32(math.Float32bits(v)))
+}
+
+func (w *hashWriter) string(s string) {
+	w.uint64(uint64(len(s)))
+	w.h.Write([]byte(s))
+}
+
+// Identity returns a stable hash of the glyphs of the font: the range,
+// metrics and bitmaps of glyphs, added glyphs included. Fonts loaded from the same data have
+// the same identity. It is computed on the first call.
+func (f *Font) Identity() uint64 {
+	if f.identity != 0 {
+		return f.identity
+	}
+	w := newHashWriter()
+	if c := f.Config; c != nil {
+		w.int32(c.Low)
+		w.int32(c.High)
+		w.int32(c.Baseline)
+		for _, rg := range c.Ranges {
+			w.int32(rg.Low)
+			w.int32(rg.High)
+		}
+		for _, p := range c.Kerning {
+			w.int32(p.Left)
+			w.int32(p.Right)
+			w.int32(p.Adjust)
+		}
+		for i := range c.Glyphs {
+			g := &c.Glyphs[i]
+			for _, v := range [...]int32{g.X, g.Y, g.Width, g.Height, g.Advance, g.LeftBearing, g.RightBearing, g.TopBearing, g.BottomBearing, g.VerticalAdvance} {
+				w.int32(v)
+			}
+			w.string(string(g.BitmapData))
+		}
+	}
+	for _, r := range f.addedRunes() {
+		g := f.added[r]
+		w.int32(r)
+		for _, v := range [...]int32{g.X, g.Y, g.Width, g.Height, g.Advance, g.LeftBearing, g.RightBearing, g.TopBearing, g.BottomBearing, g.VerticalAdvance} {
+			w.int32(v)
+		}
+		w.string(string(g.BitmapData))
+	}
+	f.identity = w.h.Sum64()
+	return f.identity
+}
+
+// TextHash returns a stable hash of the string drawn by the font with
+// the colors, as PrintfColors draws it: the identity of the font, the
+// settings changing the layout and the origin of SetOrigin. Colors may
+// be nil for text drawn by the current color.
+func (f *Font) TextHash(str string, colors []color.RGBA) uint64 {
+	w := newHashWriter()
+	w.uint64(f.Identity())
+	w.float32(f.LineHeight.factor)
+	w.int32(f.LineHeight.pixels)
+	w.int32(int32(f.rounding()))
+	if f.Grid.active() {
+		w.float32(f.Grid.Offset)
+		w.int32(f.Grid.Step)
+	} else {
+		w.int32(0)
+	}
+	w.uint64(uint64(f.MaxGlyphs))
+	w.int32(int32(f.Unknown))
+	w.int32(int32(origin))
+	if f.NoKerning {
+		w.int32(1)
+	} else {
+		w.int32(0)
+	}
+	if f.Ligatures {
+		w.int32(1)
+	} else {
+		w.int32(0)
+	}
+	w.int32(int32(f.Bidi))
+	w.int32(int32(f.Direction))
+	if f.Sideways {
+		w.int32(1)
+	} else {
+		w.int32(0)
+	}
+	if f.NoJoining {
+		w.int32(1)
+	} else {
+		w.int32(0)
+	}
+	if f.Shaper != nil {
+		w.int32(1)
+	} else {
+		w.int32(0)
+	}
+	f.hashAdvances(w)
+	w.uint64(uint64(len(f.Features)))
+	for _, tag := range f.Features {
+		w.string(tag)
+	}
+	w.string(str)
+	w.uint64(uint64(len(colors)))
+	for _, c := range colors {
+		w.h.Write([]byte{c.R, c.G, c.B, c.A})
+	}
+	return w.h.Sum64()
+}
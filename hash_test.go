@@ -0,0 +1,57 @@
+package glsymbol
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestTextHash(t *testing.T) {
+	a, b := testFont(), testFont()
+	if a.Identity() != b.Identity() {
+		t.Errorf("fonts of the same data have different identities")
+	}
+	h := a.TextHash("text", nil)
+	if h != b.TextHash("text", nil) {
+		t.Errorf("hashes of the same text differ")
+	}
+	// stable across runs and platforms
+	if h != a.TextHash("text", nil) {
+		t.Errorf("hash is not deterministic")
+	}
+
+	for name, change := range map[string]func(f *Font) uint64{
+		"string":      func(f *Font) uint64 { return f.TextHash("other", nil) },
+		"colors":      func(f *Font) uint64 { return f.TextHash("text", []color.RGBA{{R: 1}}) },
+		"line height": func(f *Font) uint64 { f.LineHeight = LineMultiple(1.5); return f.TextHash("text", nil) },
+		"rounding":    func(f *Font) uint64 { f.Rounding = RoundCeil; return f.TextHash("text", nil) },
+		"grid":        func(f *Font) uint64 { f.Grid = &BaselineGrid{Step: 4}; return f.TextHash("text", nil) },
+		"glyphs": func(f *Font) uint64 {
+			f.Config.Glyphs[0].Width++
+			f.identity = 0
+			return f.TextHash("text", nil)
+		},
+	} {
+		if change(testFont()) == h {
+			t.Errorf("hash does not depend on the %s", name)
+		}
+	}
+	// inactive grid does not change the layout
+	f := testFont()
+	f.Grid = &BaselineGrid{}
+	if f.TextHash("text", nil) != h {
+		t.Errorf("inactive grid changes the hash")
+	}
+}
+
+func TestTextRemeasure(t *testing.T) {
+	f := testFont()
+	text := NewText(f, "ii")
+	if w, _ := text.Size(); w != 8 {
+		t.Fatalf("width %d", w)
+	}
+	f.Config.Glyphs['i'-f.Config.Low].Width = 8
+	f.identity = 0
+	if w, _ := text.Size(); w != 16 {
+		t.Errorf("width %d after a change of glyphs", w)
+	}
+}
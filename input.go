@@ -0,0 +1,360 @@
+package glsymbol
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Key is a key of the keyboard handled by widgets. Applications translate
+// keys of their window library, for example glfw, into keys of the package.
+type Key int
+
+// Keys handled by widgets.
+const (
+	KeyUnknown Key = iota
+	KeyLeft
+	KeyRight
+	KeyUp
+	KeyDown
+	KeyHome
+	KeyEnd
+	KeyBackspace
+	KeyDelete
+	KeyEnter
+	KeyTab
+	KeyEscape
+	KeyUndo
+	KeyRedo
+)
+
+// An Input is a single line text field.
+//
+// Text is stored in logical order and drawn in visual order, so text of
+// mixed directions, for example Hebrew with numbers and Latin words, is
+// shown as readers expect. The caret has a logical position, a byte
+// offset used for editing, and a visual position on the screen. Arrow
+// keys move the caret visually, see MoveVisual, while MoveLogical moves
+// it through the text in reading order.
+//
+// Typed text may be restricted by a Mask and MaxLength. Text which is
+// not Valid, for example an incomplete number, is drawn with the error
+// color of the theme.
+//
+// Edits are undone by Undo word by word: runes typed or removed one
+// after another are undone together, until a pause, a space or a move
+// of the caret.
+type Input struct {
+	Font *Font
+
+	// RTL is the base direction of text without strong characters,
+	// otherwise the direction is given by the first strong character.
+	RTL bool
+
+	Mask      Mask // restricts typed text if not nil
+	MaxLength int  // maximal amount of runes, zero means no limit
+
+	// Secret hides text as a password, every rune is drawn as Bullet,
+	// or as '•' if Bullet is zero and the font has it, otherwise '*'.
+	Secret bool
+	Bullet rune
+
+	// Blink is the period of caret blinking, zero means a steady caret.
+	// The caret is shown after every edit or move.
+	Blink time.Duration
+	Clock Clock // time of blinking, SystemClock if nil
+
+	text  string
+	line  *bidiLine
+	caret int // byte offset of the caret
+	slot  int // visual position of the caret
+
+	moved time.Time // time of the last edit or move of the caret
+
+	history history
+}
+
+// NewInput creates a text field with the caret at the end of the text.
+func NewInput(f *Font, text string) *Input {
+	in := &Input{Font: f}
+	in.SetText(text)
+	return in
+}
+
+// String returns the text of the field.
+func (in *Input) String() string {
+	return in.text
+}
+
+// SetText replaces the text of the field, the caret is moved to the end.
+// Edits made before can not be undone.
+func (in *Input) SetText(text string) {
+	in.history = history{}
+	in.text = text
+	in.line = in.newLine()
+	in.caret = len(text)
+	in.slot = in.line.offsetSlot(in.caret)
+	in.moved = now(in.Clock)
+}
+
+// Caret returns the byte offset of the caret.
+func (in *Input) Caret() int {
+	return in.caret
+}
+
+// SetCaret moves the caret to the byte offset.
+func (in *Input) SetCaret(offset int) error {
+	if offset < 0 || len(in.text) < offset {
+		return fmt.Errorf("glsymbol: offset %d is out of text of length %d", offset, len(in.text))
+	}
+	if offset < len(in.text) && !utf8.RuneStart(in.text[offset]) {
+		return fmt.Errorf("glsymbol: offset %d is inside of a rune", offset)
+	}
+	in.caret = offset
+	in.slot = in.line.offsetSlot(offset)
+	in.moved = now(in.Clock)
+	in.history.open = false
+	return nil
+}
+
+// MoveLogical moves the caret by n runes in reading order,
+// forward if n is positive.
+func (in *Input) MoveLogical(n int) {
+	for ; 0 < n && in.caret < len(in.text); n-- {
+		_, size := utf8.DecodeRuneInString(in.text[in.caret:])
+		in.caret += size
+	}
+	for ; n < 0 && 0 < in.caret; n++ {
+		_, size := utf8.DecodeLastRuneInString(in.text[:in.caret])
+		in.caret -= size
+	}
+	in.slot = in.line.offsetSlot(in.caret)
+	in.moved = now(in.Clock)
+	in.history.open = false
+}
+
+// MoveVisual moves the caret by n positions on the screen,
+// right if n is positive.
+func (in *Input) MoveVisual(n int) {
+	in.slot += n
+	if in.slot < 0 {
+		in.slot = 0
+	}
+	if runes := len(in.line.runes); runes < in.slot {
+		in.slot = runes
+	}
+	in.caret = in.line.slotOffset(in.slot)
+	in.moved = now(in.Clock)
+	in.history.open = false
+}
+
+// Insert inserts the string at the caret and moves the caret after it.
+// It reports false and does not change the text if the result exceeds
+// MaxLength or is not accepted by the Mask.
+func (in *Input) Insert(s string) bool {
+	text := in.text[:in.caret] + s + in.text[in.caret:]
+	if 0 < in.MaxLength && in.MaxLength < utf8.RuneCountInString(text) {
+		return false
+	}
+	if in.Mask != nil && !in.Mask.Accept(text) {
+		return false
+	}
+	if s != "" {
+		in.history.record(textEdit{offset: in.caret, inserted: s}, now(in.Clock))
+	}
+	in.text = text
+	in.line = in.newLine()
+	in.caret += len(s)
+	in.slot = in.line.offsetSlot(in.caret)
+	in.moved = now(in.Clock)
+	return true
+}
+
+// Undo reverts the last group of edits and reports whether there was
+// one. The caret is placed at the reverted edits.
+func (in *Input) Undo() bool {
+	edits, ok := in.history.undo()
+	if ok {
+		in.applyEdits(edits)
+	}
+	return ok
+}
+
+// Redo makes the last undone group of edits again and reports whether
+// there was one. The caret is placed after the edits.
+func (in *Input) Redo() bool {
+	edits, ok := in.history.redo()
+	if ok {
+		in.applyEdits(edits)
+	}
+	return ok
+}
+
+// applyEdits changes the text by the edits without recording them.
+func (in *Input) applyEdits(edits []textEdit) {
+	in.text, in.caret = applyEdits(in.text, edits)
+	in.line = in.newLine()
+	in.slot = in.line.offsetSlot(in.caret)
+	in.moved = now(in.Clock)
+}
+
+// Valid reports whether the text is valid for the Mask.
+func (in *Input) Valid() bool {
+	return in.Mask == nil || in.Mask.Valid(in.text)
+}
+
+// newLine orders the text of the field, secret text is ordered as
+// bullets, so the direction of the text is not revealed.
+func (in *Input) newLine() *bidiLine {
+	if !in.Secret {
+		return newBidiLine(in.text, in.RTL)
+	}
+	l := newBidiLine(in.shown(), in.RTL)
+	l.offsets = l.offsets[:0]
+	for i := range in.text {
+		l.offsets = append(l.offsets, i)
+	}
+	l.offsets = append(l.offsets, len(in.text))
+	return l
+}
+
+// bullet returns the rune drawn for runes of secret text.
+func (in *Input) bullet() rune {
+	if in.Bullet != 0 {
+		return in.Bullet
+	}
+	if c := in.Font.Config; c != nil && c.Has('•') {
+		return '•'
+	}
+	return '*'
+}
+
+// Backspace removes the rune before the caret.
+func (in *Input) Backspace() {
+	if in.caret == 0 {
+		return
+	}
+	_, size := utf8.DecodeLastRuneInString(in.text[:in.caret])
+	in.remove(in.caret-size, in.caret)
+}
+
+// Delete removes the rune after the caret.
+func (in *Input) Delete() {
+	if in.caret == len(in.text) {
+		return
+	}
+	_, size := utf8.DecodeRuneInString(in.text[in.caret:])
+	in.remove(in.caret, in.caret+size)
+}
+
+// remove removes bytes from start up to end and puts the caret at start.
+func (in *Input) remove(start, end int) {
+	in.history.record(textEdit{offset: start, removed: in.text[start:end]}, now(in.Clock))
+	in.text = in.text[:start] + in.text[end:]
+	in.line = in.newLine()
+	in.caret = start
+	in.slot = in.line.offsetSlot(start)
+	in.moved = now(in.Clock)
+}
+
+// HandleKey edits the text or moves the caret by the key and reports
+// whether the key is handled. Home and End move the caret to the left
+// and right edges of the field.
+func (in *Input) HandleKey(k Key) bool {
+	switch k {
+	case KeyLeft:
+		in.MoveVisual(-1)
+	case KeyRight:
+		in.MoveVisual(1)
+	case KeyHome:
+		in.MoveVisual(-len(in.line.runes))
+	case KeyEnd:
+		in.MoveVisual(len(in.line.runes))
+	case KeyBackspace:
+		in.Backspace()
+	case KeyDelete:
+		in.Delete()
+	case KeyUndo:
+		in.Undo()
+	case KeyRedo:
+		in.Redo()
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleChar inserts the typed rune at the caret and reports whether
+// it is inserted, see Insert.
+func (in *Input) HandleChar(r rune) bool {
+	return in.Insert(string(r))
+}
+
+// CaretVisible reports whether the blinking caret is shown now.
+func (in *Input) CaretVisible() bool {
+	if in.Blink <= 0 {
+		return true
+	}
+	return now(in.Clock).Sub(in.moved)%in.Blink < in.Blink/2
+}
+
+// CaretX returns the distance from the left edge of the text
+// to the caret on the screen.
+func (in *Input) CaretX() int32 {
+	// the caret is before the glyph of the rune at its visual position
+	n := 0
+	x, _ := in.Font.placeWith(in.shown(), in.options(), func(g PlacedGlyph) bool {
+		if in.slot <= n {
+			return false
+		}
+		n += utf8.RuneCountInString(g.Text)
+		return true
+	})
+	return x
+}
+
+// shown returns the text drawn by the field in logical order, bullets
+// for secret text.
+func (in *Input) shown() string {
+	if !in.Secret {
+		return in.text
+	}
+	return strings.Repeat(string(in.bullet()), utf8.RuneCountInString(in.text))
+}
+
+// options returns options of drawing of the text of the field: it is
+// reordered as the line of the field and drawn left to right, whatever
+// the bidi mode and the direction of the font.
+func (in *Input) options() drawOptions {
+	o := in.Font.options()
+	o.bidi, o.rtl, o.dir = BidiAuto, in.RTL, LeftToRight
+	return o
+}
+
+// Draw draws the text in visual order with the current color and the
+// caret, the coordinates are the same as for Printf. Text which is not
+// Valid is drawn with the error color of the theme, if it is set.
+func (in *Input) Draw(x, y float32) error {
+	if c := theme.color(ColorError); !in.Valid() && c != nil {
+		gl.PushAttrib(gl.CURRENT_BIT)
+		setColor(c)
+		err := in.Font.printOptions(x, y, in.shown(), in.options())
+		gl.PopAttrib()
+		if err != nil {
+			return err
+		}
+	} else if err := in.Font.printOptions(x, y, in.shown(), in.options()); err != nil {
+		return err
+	}
+	if !in.CaretVisible() {
+		return checkGLError("Input.Draw")
+	}
+	cx := x + float32(in.CaretX())
+	h := float32(in.Font.MaxGlyphHeight)
+	// the caret has the color of the theme or the current color
+	fillRect(Rect{X: cx, Y: linePos(above(cellBottom(y, h), h), h), Width: 1, Height: h}, theme.color(ColorCaret))
+	return checkGLError("Input.Draw")
+}
@@ -0,0 +1,111 @@
+package glsymbol
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestBidiLine(t *testing.T) {
+	for _, tc := range []struct {
+		in, visual string
+		rtl        bool
+	}{
+		{"abc", "abc", false},
+		{"abc אבג", "abc גבא", false},
+		{"abc אבג 123 דהו", "abc והד 123 גבא", false},
+		{"אבג abc", "abc גבא", true},
+		{"אבג 12", "12 גבא", true},
+		{"", "", false},
+	} {
+		l := newBidiLine(tc.in, false)
+		if s := l.String(); s != tc.visual || l.rtl != tc.rtl {
+			t.Errorf("%q: visual %q, rtl %v", tc.in, s, l.rtl)
+		}
+	}
+}
+
+func TestInputCaret(t *testing.T) {
+	// "ab" and two Hebrew letters: a b ב א on the screen
+	in := NewInput(testFont(), "abאב")
+	offsets := func(move func()) (out []int) {
+		for i := 0; i < 6; i++ {
+			out = append(out, in.Caret())
+			move()
+		}
+		return
+	}
+
+	in.SetCaret(0)
+	if o := offsets(func() { in.MoveLogical(1) }); !reflect.DeepEqual(o, []int{0, 1, 2, 4, 6, 6}) {
+		t.Errorf("logical order %v", o)
+	}
+	in.HandleKey(KeyHome)
+	// the left edge of ב is the end of the text, the right edge of א is
+	// the start of the Hebrew word
+	if o := offsets(func() { in.HandleKey(KeyRight) }); !reflect.DeepEqual(o, []int{0, 1, 6, 4, 2, 2}) {
+		t.Errorf("visual order %v", o)
+	}
+	if o := offsets(func() { in.HandleKey(KeyLeft) }); !reflect.DeepEqual(o, []int{2, 4, 6, 1, 0, 0}) {
+		t.Errorf("visual order to the left %v", o)
+	}
+
+	// typing at the caret between Latin and Hebrew
+	in.SetCaret(2)
+	in.HandleChar('c')
+	in.HandleKey(KeyBackspace)
+	in.HandleKey(KeyDelete)
+	if s := in.String(); s != "abב" || in.Caret() != 2 {
+		t.Errorf("text %q, caret %d", s, in.Caret())
+	}
+	if err := in.SetCaret(3); err == nil {
+		t.Errorf("caret inside of a rune")
+	}
+}
+
+func TestInputCaretX(t *testing.T) {
+	in := NewInput(testFont(), "aib")
+	if x := in.CaretX(); x != 20 {
+		t.Errorf("caret at the end: %d", x)
+	}
+	in.MoveVisual(-2)
+	if x := in.CaretX(); x != 8 {
+		t.Errorf("caret after the first rune: %d", x)
+	}
+}
+
+func TestInputJoining(t *testing.T) {
+	f := testFont()
+	// initial and final forms of beh
+	for _, r := range []rune{0xfe91, 0xfe90} {
+		if err := f.AddGlyph(r, image.NewAlpha(image.Rect(0, 0, 6, 6)), GlyphMetrics{Advance: 6}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Bidi = BidiLTR
+	in := NewInput(f, "بب")
+	// letters are joined, the line is reordered by the field
+	if x := in.CaretX(); x != 0 {
+		t.Errorf("caret at the end of right-to-left text: %d", x)
+	}
+	in.MoveVisual(2)
+	if x := in.CaretX(); x != 12 {
+		t.Errorf("caret at the right edge: %d", x)
+	}
+	if f.Bidi != BidiLTR || f.NoJoining {
+		t.Errorf("font is changed by the field")
+	}
+}
+
+func TestInputDirection(t *testing.T) {
+	f := testFont()
+	f.Direction = RightToLeft
+	in := NewInput(f, "aib")
+	// the field is drawn left to right on fonts of any direction
+	if x := in.CaretX(); x != 20 {
+		t.Errorf("caret at the end: %d", x)
+	}
+	if f.Direction != RightToLeft {
+		t.Errorf("font is changed by the field")
+	}
+}
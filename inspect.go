@@ -0,0 +1,131 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// AtlasStats describes the use of sprite sheets of a font.
+type AtlasStats struct {
+	Pages  int // amount of texture pages, one before textures are created
+	Width  int // size of a sheet in pixels
+	Height int
+	Glyphs int     // distinct glyph cells in sheets
+	Fill   float64 // part of the sheet area covered by glyph cells, 0..1
+
+	// Packed is the size of the part of a sheet holding glyph cells,
+	// which is rounded up to powers of two for the sheet size.
+	Packed image.Point
+}
+
+// AtlasStats returns the use of the sprite sheet of the font.
+func (f *Font) AtlasStats() AtlasStats {
+	var s AtlasStats
+	if f.img == nil {
+		return s
+	}
+	b := f.img.Bounds()
+	s.Pages, s.Width, s.Height = 1, b.Dx(), b.Dy()
+	if 1 < len(f.pages) {
+		s.Pages = len(f.pages)
+	}
+
+	// cells shared by many runes are counted once
+	cells := make(map[image.Rectangle]bool)
+	add := func(g *Glyph) {
+		if 0 < g.Width && 0 < g.Height {
+			cells[image.Rect(int(g.X), int(g.Y)+1, int(g.X+g.Width), int(g.Y+g.Height)+1)] = true
+		}
+	}
+	if f.Config != nil {
+		for i := range f.Config.Glyphs {
+			add(&f.Config.Glyphs[i])
+		}
+	}
+	for _, r := range f.addedRunes() {
+		add(f.added[r])
+	}
+	var area int
+	for c := range cells {
+		c = c.Intersect(b)
+		area += c.Dx() * c.Dy()
+		if s.Packed.X < c.Max.X {
+			s.Packed.X = c.Max.X
+		}
+		if s.Packed.Y < c.Max.Y {
+			s.Packed.Y = c.Max.Y
+		}
+	}
+	s.Glyphs = len(cells)
+	if n := s.Width * s.Height; n != 0 {
+		s.Fill = float64(area) / float64(n)
+	}
+	return s
+}
+
+// DrawAtlas draws the sprite sheet of the font for debugging, scaled to
+// fit a square of the size in the corner of the viewport, over a dark
+// background with a line of the page, the fill ratio and the sheet size.
+// The line is drawn by the label font, or the font itself if label is
+// nil. Viewport of SetupOrtho is expected.
+func (f *Font) DrawAtlas(label *Font, corner Corner, size float32) error {
+	if label == nil {
+		label = f
+	}
+	pages, err := f.sheetPages()
+	if err != nil {
+		return err
+	}
+	s := f.AtlasStats()
+	text := fmt.Sprintf("page 1/%d %.0f%% %dx%d", s.Pages, s.Fill*100, s.Width, s.Height)
+	// the first page is drawn
+	tex := pages[0].tex
+	s.Height = pages[0].y1 - pages[0].y0
+
+	scale := size / float32(s.Width)
+	if hs := size / float32(s.Height); hs < scale {
+		scale = hs
+	}
+	const margin, pad = 8, 4
+	lh := float32(label.lineHeight())
+	w := float32(s.Width)*scale + 2*pad
+	if tw := float32(label.advanceSize(text)) + 2*pad; w < tw {
+		w = tw
+	}
+	h := float32(s.Height)*scale + lh + 3*pad
+	vw, vh := viewportSize()
+	box := corner.place(float32(vw), float32(vh), margin, w, h)
+
+	fillRect(box, color.NRGBA{A: 0xc0})
+	top := rectTop(box)
+	gl.PushAttrib(gl.CURRENT_BIT)
+	gl.Color4f(1, 1, 1, 1)
+	drawTexture(tex, box.X+pad, below(top, pad), float32(s.Width)*scale, float32(s.Height)*scale)
+	err = label.Printf(label.startX(box.X+pad, text), label.lineY(below(top, h-pad-lh)), text)
+	gl.PopAttrib()
+	if err != nil {
+		return err
+	}
+	return checkGLError("DrawAtlas")
+}
+
+// place returns the rectangle of the size at the margin from the corner
+// of the viewport of the size width and height.
+func (c Corner) place(width, height, margin, w, h float32) Rect {
+	x := margin
+	if c == CornerTopRight || c == CornerBottomRight {
+		x = width - margin - w
+	}
+	// distance of the top edge from the top of the viewport
+	top := margin
+	if c == CornerBottomRight || c == CornerBottomLeft {
+		top = height - margin - h
+	}
+	if origin != OriginTopLeft {
+		top = height - top
+	}
+	return Rect{X: x, Y: linePos(top, h), Width: w, Height: h}
+}
@@ -0,0 +1,44 @@
+package glsymbol
+
+import (
+	"image"
+	"testing"
+)
+
+func TestAtlasStats(t *testing.T) {
+	f := testSheetFont()
+	s := f.AtlasStats()
+	// all 95 glyphs are in a single row of cells 16 pixels high
+	if s.Pages != 1 || s.Glyphs != 95 || s.Height != 18 || s.Fill != 16.0/18 || s.Packed.Y != 17 {
+		t.Errorf("stats %+v", s)
+	}
+
+	// an added glyph grows the sheet
+	f.Fallback = func(rune) image.Image { return image.NewAlpha(image.Rect(0, 0, 4, 4)) }
+	f.glyph('é')
+	if s := f.AtlasStats(); s.Glyphs != 96 || s.Height != 35 {
+		t.Errorf("stats with added glyph %+v", s)
+	}
+	if s := testFont().AtlasStats(); s.Pages != 0 || s.Fill != 0 {
+		t.Errorf("stats of font without sheet %+v", s)
+	}
+}
+
+func TestCornerPlace(t *testing.T) {
+	defer SetOrigin(OriginBottomLeft)
+	for _, tc := range []struct {
+		origin Origin
+		corner Corner
+		expect Rect
+	}{
+		{OriginBottomLeft, CornerTopLeft, Rect{X: 8, Y: 72, Width: 30, Height: 20}},
+		{OriginBottomLeft, CornerBottomRight, Rect{X: 162, Y: 8, Width: 30, Height: 20}},
+		{OriginTopLeft, CornerTopRight, Rect{X: 162, Y: 8, Width: 30, Height: 20}},
+		{OriginTopLeft, CornerBottomLeft, Rect{X: 8, Y: 72, Width: 30, Height: 20}},
+	} {
+		SetOrigin(tc.origin)
+		if r := tc.corner.place(200, 100, 8, 30, 20); r != tc.expect {
+			t.Errorf("origin %v corner %v: %+v", tc.origin, tc.corner, r)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !glsymbol_invariants
+
+package glsymbol
+
+// checkPen checks the distance the pen moved while the string is drawn.
+// Checks of invariants are available with the glsymbol_invariants build
+// tag only.
+func checkPen(f *Font, str string, pen int32) {}
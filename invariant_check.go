@@ -0,0 +1,24 @@
+//go:build glsymbol_invariants
+
+package glsymbol
+
+import "fmt"
+
+// checkPen panics if the distance the pen moved while Printf drew the
+// single line string differs from its width by Metrics, which breaks
+// layouts made by Metrics. Every feature placing glyphs, for example
+// kerning or ligatures, has to keep them equal, which is checked by
+// tests of applications built with the tag:
+//
+//	go test -tags glsymbol_invariants
+func checkPen(f *Font, str string, pen int32) {
+	if len(splitLines(str)) != 1 {
+		// lines of multi-line strings are measured apart
+		return
+	}
+	if w := f.Metrics(str).X; int32(w) != pen {
+		msg := fmt.Sprintf("glsymbol: pen moved by %d pixels over %q, Metrics width is %d", pen, str, w)
+		warn(msg)
+		panic(msg)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build glsymbol_invariants
+
+package glsymbol
+
+import "testing"
+
+func TestCheckPen(t *testing.T) {
+	f := testFont()
+	checkPen(f, "ab", f.advanceSize("ab"))
+	checkPen(f, "a\nbc", 0)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for a wrong pen")
+		}
+	}()
+	checkPen(f, "ab", 1)
+}
@@ -0,0 +1,40 @@
+package glsymbol
+
+import (
+	"image"
+	"testing"
+)
+
+// TestPenMetrics checks that glyphs are placed over the width measured by
+// Metrics with every feature placing glyphs.
+func TestPenMetrics(t *testing.T) {
+	texts := []string{"", "Hello, world", "AVAfi ffl", "ab אב (12)", "\u200bzero"}
+	for _, tc := range []struct {
+		name string
+		set  func(f *Font)
+	}{
+		{"plain", func(f *Font) {}},
+		{"kerning", func(f *Font) { f.kern = kernMap([]KernPair{{'A', 'V', -2}, {'V', 'A', -1}}) }},
+		{"advances", func(f *Font) { f.SetAdvance(' ', 11); f.SetAdvance('\u200b', 0) }},
+		{"ligatures", func(f *Font) {
+			f.Ligatures = true
+			f.AddGlyph('ﬁ', image.NewAlpha(image.Rect(0, 0, 10, 10)), GlyphMetrics{Advance: 10})
+		}},
+		{"bidi", func(f *Font) { f.Bidi = BidiAuto }},
+		{"rtl", func(f *Font) { f.Bidi = BidiRTL }},
+		{"shaper", func(f *Font) { f.Shaper = SimpleShaper{} }},
+		{"ranges", func(f *Font) { f.Config.Ranges = []RuneRange{{32, 126}} }},
+	} {
+		f := testFont()
+		tc.set(f)
+		for _, s := range texts {
+			pen, all := f.place(s, func(PlacedGlyph) bool { return true })
+			if w := f.Metrics(s).X; !all || int32(w) != pen {
+				t.Errorf("%s %q: pen moved by %d, width %d", tc.name, s, pen, w)
+			}
+			if w := f.MetricsBatch([]string{s})[0].X; int32(w) != pen {
+				t.Errorf("%s %q: pen moved by %d, batch width %d", tc.name, s, pen, w)
+			}
+		}
+	}
+}
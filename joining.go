@@ -0,0 +1,174 @@
+package glsymbol
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Joining types of Arabic letters by Unicode ArabicShaping.txt.
+const (
+	joinNone        = iota // does not join, for example hamza
+	joinRight              // joins the preceding letter only, for example alef
+	joinDual               // joins letters on both sides
+	joinCausing            // joins letters on both sides without changing, tatweel
+	joinTransparent        // marks between letters, which are skipped
+)
+
+// arabicLetter is a letter of Arabic with its joining type and its
+// isolated form, followed by the final form for right joining letters,
+// and by final, initial and medial ones for dual joining letters.
+type arabicLetter struct {
+	join     uint8
+	isolated rune
+}
+
+// arabicLetters are letters of the Arabic block with presentation forms,
+// by runes from U+0621.
+var arabicLetters = func() []arabicLetter {
+	// presentation forms B follow letters in order
+	types := "URRRRDRDRDDDDDRRRRDDDDDDDD" + // hamza .. ghain
+		"     C" + // U+063B .. tatweel
+		"DDDDDDDRRD" // feh .. yeh
+	out := make([]arabicLetter, len(types))
+	form := rune(0xfe80)
+	for i, t := range types {
+		switch t {
+		case 'U':
+			out[i] = arabicLetter{joinNone, form}
+			form++
+		case 'R':
+			out[i] = arabicLetter{joinRight, form}
+			form += 2
+		case 'D':
+			out[i] = arabicLetter{joinDual, form}
+			form += 4
+		case 'C':
+			out[i] = arabicLetter{join: joinCausing}
+		}
+	}
+	return out
+}()
+
+// persianLetters are letters of Persian and Urdu with presentation forms
+// A, which are not in order of letters.
+var persianLetters = map[rune]arabicLetter{
+	'پ': {joinDual, 0xfb56},
+	'چ': {joinDual, 0xfb7a},
+	'ژ': {joinRight, 0xfb8a},
+	'ک': {joinDual, 0xfb8e},
+	'گ': {joinDual, 0xfb92},
+	'ی': {joinDual, 0xfbfc},
+}
+
+// lamAlef are isolated forms of mandatory ligatures of lam with alefs,
+// final forms follow them.
+var lamAlef = map[rune]rune{
+	'آ': 0xfef5,
+	'أ': 0xfef7,
+	'إ': 0xfef9,
+	'ا': 0xfefb,
+}
+
+// arabicLetterOf returns the letter of the rune, with zero isolated form
+// for runes without presentation forms.
+func arabicLetterOf(r rune) arabicLetter {
+	switch {
+	case 0x621 <= r && r < 0x621+rune(len(arabicLetters)):
+		return arabicLetters[r-0x621]
+	case r == 0x200d:
+		// zero width joiner
+		return arabicLetter{join: joinCausing}
+	case unicode.Is(unicode.Mn, r):
+		return arabicLetter{join: joinTransparent}
+	}
+	return persianLetters[r]
+}
+
+// hasArabic reports whether the string may have Arabic letters: runes of
+// U+0600 .. U+06FF start with bytes 0xd8 .. 0xdb.
+func hasArabic(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if 0xd8 <= s[i] && s[i] <= 0xdb {
+			return true
+		}
+	}
+	return false
+}
+
+// join returns the string with Arabic letters replaced by their
+// presentation forms joining neighbour letters, as the init, medi, fina
+// and rlig features of OpenType fonts do, and logical indexes of runes of
+// the result in the string, or nil indexes if no letter is replaced.
+// Forms the font has no glyphs of are not used.
+func (f *Font) join(str string) (string, []int) {
+	if f.NoJoining || !hasArabic(str) {
+		return str, nil
+	}
+	runes := f.Arena.Runes(utf8.RuneCountInString(str))[:0]
+	for _, r := range str {
+		runes = append(runes, r)
+	}
+	// neighbour returns the joining type of the closest letter in the
+	// direction, skipping marks
+	neighbour := func(i, dir int) uint8 {
+		for i += dir; 0 <= i && i < len(runes); i += dir {
+			if t := arabicLetterOf(runes[i]).join; t != joinTransparent {
+				return t
+			}
+		}
+		return joinNone
+	}
+	out := f.Arena.Runes(len(runes))[:0]
+	index := f.Arena.Ints(len(runes))[:0]
+	changed := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		l := arabicLetterOf(r)
+		if l.isolated == 0 {
+			out, index = append(out, r), append(index, i)
+			continue
+		}
+		prev := neighbour(i, -1)
+		joinsPrev := l.join != joinNone && (prev == joinDual || prev == joinCausing)
+		if alef, ok := lamAlef[runeAt(runes, i+1)]; ok && r == 'ل' {
+			form := alef
+			if joinsPrev {
+				form++
+			}
+			if f.hasGlyph(form) {
+				out, index = append(out, form), append(index, i)
+				changed = true
+				i++
+				continue
+			}
+		}
+		next := neighbour(i, 1)
+		joinsNext := l.join == joinDual && (next == joinDual || next == joinRight || next == joinCausing)
+		form := l.isolated
+		switch {
+		case joinsPrev && joinsNext:
+			form += 3
+		case joinsPrev:
+			form++
+		case joinsNext:
+			form += 2
+		}
+		if form != r && f.hasGlyph(form) {
+			r = form
+			changed = true
+		}
+		out, index = append(out, r), append(index, i)
+	}
+	if !changed {
+		return str, nil
+	}
+	return string(out), index
+}
+
+// runeAt returns the rune with the index, or -1 out of the runes.
+func runeAt(runes []rune, i int) rune {
+	if i < len(runes) {
+		return runes[i]
+	}
+	return -1
+}
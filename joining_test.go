@@ -0,0 +1,57 @@
+package glsymbol
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	f := testFont()
+	// forms of beh, final alef and the lam alef ligature
+	for _, r := range []rune{0xfe8f, 0xfe90, 0xfe91, 0xfe92, 0xfe8e, 0xfefb} {
+		if err := f.AddGlyph(r, image.NewAlpha(image.Rect(0, 0, 6, 6)), GlyphMetrics{Advance: 6}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		in, out string
+		index   []int
+	}{
+		{"abc", "abc", nil},
+		{"ب", "ﺏ", []int{0}},
+		{"ببب", "ﺑﺒﺐ", []int{0, 1, 2}},
+		{"با", "ﺑﺎ", []int{0, 1}},
+		{"ابب", "اﺑﺐ", []int{0, 1, 2}},
+		{"بَب", "ﺑَﺐ", []int{0, 1, 2}},
+		{"ءب", "ءﺏ", []int{0, 1}},
+		{"لا ب", "ﻻ ﺏ", []int{0, 2, 3}},
+		// there is no glyph of the initial form of lam
+		{"لب", "لﺐ", []int{0, 1}},
+	} {
+		s, index := f.join(tc.in)
+		if s != tc.out || !reflect.DeepEqual(index, tc.index) {
+			t.Errorf("%q: joined %q %v, expect %q %v", tc.in, s, index, tc.out, tc.index)
+		}
+	}
+
+	f.Bidi = BidiAuto
+	var runes []rune
+	var index []int
+	f.EachGlyph("لا ب", func(g PlacedGlyph) bool {
+		runes = append(runes, []rune(g.Text)...)
+		index = append(index, g.Index)
+		return true
+	})
+	if string(runes) != "ﺏ ﻻ" || !reflect.DeepEqual(index, []int{3, 2, 0}) {
+		t.Errorf("glyphs %q of runes %v", string(runes), index)
+	}
+	if w := f.advanceSize("لا ب"); w != 6+4+6 {
+		t.Errorf("width %d", w)
+	}
+
+	f.NoJoining = true
+	if s, index := f.join("ببب"); s != "ببب" || index != nil {
+		t.Errorf("joined %q %v", s, index)
+	}
+}
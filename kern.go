@@ -0,0 +1,169 @@
+package glsymbol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+)
+
+// A KernPair adjusts the distance between glyphs of two runes: the pen
+// moves by Adjust pixels more after the glyph of Left if the glyph of
+// Right follows, less if Adjust is negative, so pairs as "AV", "To" or
+// "We" are not spaced too loosely.
+type KernPair struct {
+	Left, Right rune
+	Adjust      int32
+}
+
+// kerning returns the adjustment of the pen between glyphs of the runes,
+// see FontConfig.Kerning.
+func (f *Font) kerning(left, right rune) int32 {
+	if f.NoKerning || len(f.kern) == 0 {
+		return 0
+	}
+	return f.kern[[2]rune{left, right}]
+}
+
+// kernMap returns pairs of the config by runes.
+func kernMap(pairs []KernPair) map[[2]rune]int32 {
+	if len(pairs) == 0 {
+		return nil
+	}
+	m := make(map[[2]rune]int32, len(pairs))
+	for _, p := range pairs {
+		m[[2]rune{p.Left, p.Right}] = p.Adjust
+	}
+	return m
+}
+
+// addKerning sets kerning pairs of the config read from the data of
+// the truetype font ttf or, if it is nil, of the OpenType font otf.
+func addKerning(fc *FontConfig, data []byte, ttf *truetype.Font, otf *opentype.Font, scale int32) (err error) {
+	if ttf != nil {
+		fc.Kerning, err = kernPairs(data, fc, func(r rune) uint16 {
+			return uint16(ttf.Index(r))
+		}, int(ttf.FUnitsPerEm()), scale)
+		return err
+	}
+	var buf sfnt.Buffer
+	fc.Kerning, err = kernPairs(data, fc, func(r rune) uint16 {
+		i, _ := otf.GlyphIndex(&buf, r)
+		return uint16(i)
+	}, int(otf.UnitsPerEm()), scale)
+	return err
+}
+
+// kernPairs reads kerning pairs of runes of the config from the GPOS
+// table of the truetype or OpenType font data, or from its kern table if
+// GPOS has no pairs, with values scaled from font units to pixels of
+// the font scale. Index returns the glyph index of a rune.
+func kernPairs(data []byte, fc *FontConfig, index func(r rune) uint16, unitsPerEm int, scale int32) ([]KernPair, error) {
+	if unitsPerEm <= 0 {
+		return nil, nil
+	}
+	// runes of glyph indexes
+	runes := make(map[uint16][]rune)
+	for i := range fc.Glyphs {
+		r := fc.RuneAt(i)
+		if g := index(r); g != 0 {
+			runes[g] = append(runes[g], r)
+		}
+	}
+	keep := func(g uint16) bool { return runes[g] != nil }
+
+	var units map[[2]uint16]int
+	gpos, err := sfntTable(data, "GPOS")
+	if err == nil && gpos != nil {
+		units, err = pairAdjustments(gpos, keep)
+	}
+	if err == nil && len(units) == 0 {
+		units, err = kernUnits(data, keep)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out []KernPair
+	for k, v := range units {
+		adjust := int32(math.Round(float64(v) * float64(scale) / float64(unitsPerEm)))
+		if adjust == 0 {
+			continue
+		}
+		for _, left := range runes[k[0]] {
+			for _, right := range runes[k[1]] {
+				out = append(out, KernPair{Left: left, Right: right, Adjust: adjust})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Left != out[j].Left {
+			return out[i].Left < out[j].Left
+		}
+		return out[i].Right < out[j].Right
+	})
+	return out, nil
+}
+
+// kernUnits reads kerning values of pairs of glyphs for which keep
+// returns true from the kern table of the font data, in font units.
+func kernUnits(data []byte, keep func(g uint16) bool) (map[[2]uint16]int, error) {
+	kern, err := sfntTable(data, "kern")
+	if err != nil || kern == nil {
+		return nil, err
+	}
+	be := binary.BigEndian
+	if len(kern) < 4 {
+		return nil, fmt.Errorf("glsymbol: kern table is truncated")
+	}
+	if be.Uint16(kern) != 0 {
+		// the table of Apple fonts, version 1.0 takes 32 bits
+		return nil, nil
+	}
+
+	units := make(map[[2]uint16]int)
+	n, off := int(be.Uint16(kern[2:])), 4
+	for t := 0; t < n; t++ {
+		if len(kern) < off+6 {
+			return nil, fmt.Errorf("glsymbol: kern table is truncated")
+		}
+		coverage := be.Uint16(kern[off+4:])
+		if coverage>>8 != 0 {
+			// only format 0 is common, others are skipped
+			off += int(be.Uint16(kern[off+2:]))
+			continue
+		}
+		if len(kern) < off+14 {
+			return nil, fmt.Errorf("glsymbol: kern table is truncated")
+		}
+		nPairs := int(be.Uint16(kern[off+6:]))
+		pairs := kern[off+14:]
+		// the length of large subtables overflows 16 bits
+		off += 14 + 6*nPairs
+		if len(pairs) < 6*nPairs {
+			return nil, fmt.Errorf("glsymbol: kern table is truncated")
+		}
+		// horizontal kerning values only, no minimums or cross-stream ones
+		if coverage&0x7 != 1 {
+			continue
+		}
+		override := coverage&0x8 != 0
+		for i := 0; i < nPairs; i++ {
+			p := pairs[6*i:]
+			left, right := be.Uint16(p), be.Uint16(p[2:])
+			if !keep(left) || !keep(right) {
+				continue
+			}
+			k := [2]uint16{left, right}
+			if override {
+				units[k] = 0
+			}
+			units[k] += int(int16(be.Uint16(p[4:])))
+		}
+	}
+	return units, nil
+}
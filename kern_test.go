@@ -0,0 +1,126 @@
+package glsymbol
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// withKernTable returns the font data with a kern table of the pairs of
+// glyph indexes and values in font units added.
+func withKernTable(data []byte, pairs [][3]int) []byte {
+	be := binary.BigEndian
+	kern := make([]byte, 4+14+6*len(pairs))
+	be.PutUint16(kern[2:], 1)                       // one subtable
+	be.PutUint16(kern[6:], uint16(14+6*len(pairs))) // length
+	be.PutUint16(kern[8:], 1)                       // format 0, horizontal
+	be.PutUint16(kern[10:], uint16(len(pairs)))     // pairs
+	for i, p := range pairs {
+		q := kern[18+6*i:]
+		be.PutUint16(q, uint16(p[0]))
+		be.PutUint16(q[2:], uint16(p[1]))
+		be.PutUint16(q[4:], uint16(int16(p[2])))
+	}
+
+	return withTable(data, "kern", kern)
+}
+
+// withTable returns the font data with the table of the tag added.
+func withTable(data []byte, tag string, table []byte) []byte {
+	be := binary.BigEndian
+	n := int(be.Uint16(data[4:]))
+	// tables start at 4-byte boundaries
+	pad := (4 - len(data)%4) % 4
+	out := make([]byte, 0, len(data)+16+len(table))
+	out = append(out, data[:12]...)
+	be.PutUint16(out[4:], uint16(n+1))
+	rec := make([]byte, 16)
+	copy(rec, tag)
+	be.PutUint32(rec[8:], uint32(len(data)+16+pad))
+	be.PutUint32(rec[12:], uint32(len(table)))
+	added := false
+	for i := 0; i < n; i++ {
+		r := append([]byte(nil), data[12+16*i:28+16*i]...)
+		if !added && tag < string(r[:4]) {
+			out, added = append(out, rec...), true
+		}
+		be.PutUint32(r[8:], be.Uint32(r[8:])+16)
+		out = append(out, r...)
+	}
+	if !added {
+		out = append(out, rec...)
+	}
+	out = append(out, data[12+16*n:]...)
+	out = append(out, make([]byte, pad)...)
+	return append(out, table...)
+}
+
+func TestKernPairs(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, v := int(ttf.Index('A')), int(ttf.Index('V'))
+	upm := int(ttf.FUnitsPerEm())
+	data := withKernTable(goregular.TTF, [][3]int{{a, v, -upm / 8}, {v, a, -upm / 16}, {a, 0xfff0, -upm}})
+	ttf, err = truetype.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, fc, err := rasterizeData(context.Background(), data, 16, ASCII, 0)
+	if err != nil || img == nil {
+		t.Fatal(err)
+	}
+	expect := []KernPair{{'A', 'V', -2}, {'V', 'A', -1}}
+	if len(fc.Kerning) != len(expect) {
+		t.Fatalf("pairs %v", fc.Kerning)
+	}
+	for i := range expect {
+		if fc.Kerning[i] != expect[i] {
+			t.Errorf("pair %d: %v, expect %v", i, fc.Kerning[i], expect[i])
+		}
+	}
+	if _, fc, err := rasterizeData(context.Background(), goregular.TTF, 16, ASCII, 0); err != nil || fc.Kerning != nil {
+		t.Errorf("pairs of font without kern table: %v, %v", fc.Kerning, err)
+	}
+}
+
+func TestKerning(t *testing.T) {
+	f := testFont()
+	f.Config.Kerning = []KernPair{{'A', 'V', -2}, {'T', 'o', -1}}
+	f.kern = kernMap(f.Config.Kerning)
+	if w := f.advanceSize("AVTo"); w != 4*8-3 {
+		t.Errorf("width %d", w)
+	}
+	var xs []int32
+	f.EachGlyph("AVA", func(g PlacedGlyph) bool {
+		xs = append(xs, g.X)
+		return true
+	})
+	if len(xs) != 3 || xs[1] != 6 || xs[2] != 14 {
+		t.Errorf("glyphs at %v", xs)
+	}
+	if m := f.MetricsBatch([]string{"AV"}); m[0].X != 14 {
+		t.Errorf("batch width %d", m[0].X)
+	}
+	f.NoKerning = true
+	if w := f.advanceSize("AVTo"); w != 4*8 {
+		t.Errorf("width without kerning %d", w)
+	}
+
+	data, err := json.Marshal(f.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc FontConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Kerning) != 2 || fc.Kerning[1] != f.Config.Kerning[1] {
+		t.Errorf("pairs %v of %s", fc.Kerning, data)
+	}
+}
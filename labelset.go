@@ -0,0 +1,101 @@
+package glsymbol
+
+// A LabelSet holds annotations of a 3D scene with their projections
+// cached between frames. Annotations are projected again only when the
+// matrix or the viewport of the Annotator changes, or when they are
+// changed, so a static camera costs almost nothing per frame.
+type LabelSet struct {
+	labels []Annotation
+	proj   []projection
+	stale  []bool // annotations changed since the last projection
+
+	mvp      Mat4
+	viewport [4]int32
+	valid    bool // mvp and viewport hold the view of cached projections
+	dirty    bool // projections changed since the last ClearDirty
+}
+
+// Add adds the annotation and returns its index.
+func (s *LabelSet) Add(ann Annotation) int {
+	s.labels = append(s.labels, ann)
+	s.proj = append(s.proj, projection{})
+	s.stale = append(s.stale, true)
+	return len(s.labels) - 1
+}
+
+// Set replaces the annotation with the index.
+func (s *LabelSet) Set(i int, ann Annotation) {
+	s.labels[i] = ann
+	s.stale[i] = true
+}
+
+// Label returns the annotation with the index.
+func (s *LabelSet) Label(i int) Annotation {
+	return s.labels[i]
+}
+
+// Len returns the amount of annotations.
+func (s *LabelSet) Len() int {
+	return len(s.labels)
+}
+
+// Clear removes all annotations.
+func (s *LabelSet) Clear() {
+	s.labels = s.labels[:0]
+	s.proj = s.proj[:0]
+	s.stale = s.stale[:0]
+	s.dirty = true
+}
+
+// Update projects annotations by the view of the annotator, if the view
+// or annotations are changed since the last Update. It reports whether
+// any annotation is projected.
+func (s *LabelSet) Update(a *Annotator) bool {
+	all := !s.valid || s.mvp != a.MVP || s.viewport != a.Viewport
+	s.mvp, s.viewport, s.valid = a.MVP, a.Viewport, true
+	changed := false
+	for i := range s.labels {
+		if !all && !s.stale[i] {
+			continue
+		}
+		s.proj[i] = a.project(&s.labels[i])
+		s.stale[i] = false
+		changed = true
+	}
+	if changed {
+		s.dirty = true
+	}
+	return changed
+}
+
+// Dirty reports whether projections changed since the last ClearDirty,
+// so the caller may skip layout and drawing of an unchanged frame.
+func (s *LabelSet) Dirty() bool {
+	return s.dirty
+}
+
+// ClearDirty resets the dirty flag.
+func (s *LabelSet) ClearDirty() {
+	s.dirty = false
+}
+
+// Position returns the window coordinates of the anchor of the
+// annotation with the index from the last Update, and whether it is
+// inside of the view frustum.
+func (s *LabelSet) Position(i int) (x, y float32, ok bool) {
+	p := &s.proj[i]
+	return p.x, p.y, p.inside
+}
+
+// AddSet updates projections and adds visible labels to the batch as
+// Annotator.Add. It returns the amount of added labels.
+func (a *Annotator) AddSet(b *Batch, layer int, f *Font, s *LabelSet) int {
+	s.Update(a)
+	n := 0
+	for i := range s.labels {
+		if a.add(b, layer, f, &s.labels[i], &s.proj[i]) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,41 @@
+package glsymbol
+
+import "testing"
+
+func TestLabelSet(t *testing.T) {
+	a := Annotator{MVP: testMVP(), Viewport: [4]int32{0, 0, 200, 100}}
+	var s LabelSet
+	s.Add(Annotation{Pos: [3]float32{5, -5, 0}, Text: "A"})
+	s.Add(Annotation{Pos: [3]float32{20, 0, 0}, Text: "B"})
+
+	if !s.Update(&a) || !s.Dirty() {
+		t.Fatalf("first update does not project")
+	}
+	if x, y, ok := s.Position(0); !ok || x != 150 || y != 25 {
+		t.Errorf("position %v %v %v", x, y, ok)
+	}
+	if _, _, ok := s.Position(1); ok {
+		t.Errorf("label outside of the frustum")
+	}
+	s.ClearDirty()
+	if s.Update(&a) || s.Dirty() {
+		t.Errorf("static view is projected again")
+	}
+
+	s.Set(1, Annotation{Pos: [3]float32{0, 0, 0}, Text: "B"})
+	if !s.Update(&a) {
+		t.Errorf("changed label is not projected")
+	}
+	if x, y, ok := s.Position(1); !ok || x != 100 || y != 50 {
+		t.Errorf("position %v %v %v", x, y, ok)
+	}
+
+	a.Viewport[2] = 100
+	var b Batch
+	if n := a.AddSet(&b, LayerLabel, testFont(), &s); n != 2 || b.Len() != 2 {
+		t.Errorf("added %d labels", n)
+	}
+	if x, _, _ := s.Position(1); x != 50 {
+		t.Errorf("changed view is not projected, x = %v", x)
+	}
+}
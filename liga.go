@@ -0,0 +1,95 @@
+package glsymbol
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// ligatureForms are standard ligatures of Unicode presentation forms,
+// longer ones first.
+var ligatureForms = [...]struct {
+	runes string
+	form  rune
+}{
+	{"ffi", 'ﬃ'},
+	{"ffl", 'ﬄ'},
+	{"ff", 'ﬀ'},
+	{"fi", 'ﬁ'},
+	{"fl", 'ﬂ'},
+}
+
+// cluster is a glyph drawn for one or more runes at the start of a
+// string, see Font.Ligatures.
+type cluster struct {
+	glyph       *Glyph
+	id          sfnt.GlyphIndex // index of a ligature glyph of the GSUB table, or zero
+	form        rune            // rune of the glyph if id is zero
+	step        int32           // distance the pen moves over the glyph
+	first, last rune            // the first and the last rune of the glyph
+	runes       int             // amount of runes of the glyph
+	bytes       int             // length of runes of the glyph in bytes
+}
+
+// cluster returns the glyph drawn for runes at the start of the not
+// empty string: the glyph of the ligature of its first runes, or of its
+// first rune.
+func (f *Font) cluster(s string) cluster {
+	r, n := utf8.DecodeRuneInString(s)
+	if f.Ligatures {
+		if c, ok := f.ligature(s, r); ok {
+			return c
+		}
+	}
+	g := f.glyph(r)
+	return cluster{glyph: g, form: r, step: f.step(r, g), first: r, last: r, runes: 1, bytes: n}
+}
+
+// ligature returns the ligature of the first runes of the string, which
+// starts with the rune, or false if there is none.
+func (f *Font) ligature(s string, r rune) (cluster, bool) {
+	if f.subst != nil {
+		for _, l := range f.subst.ligas[r] {
+			if !strings.HasPrefix(s, l.runes) {
+				continue
+			}
+			if g := f.substGlyph(l.glyph); g != nil {
+				c := ligatureCluster(g, l.runes)
+				c.id = l.glyph
+				return c, true
+			}
+		}
+	}
+	if r != 'f' {
+		return cluster{}, false
+	}
+	for _, l := range ligatureForms {
+		if strings.HasPrefix(s, l.runes) && f.hasGlyph(l.form) {
+			c := ligatureCluster(f.glyph(l.form), l.runes)
+			c.form = l.form
+			return c, true
+		}
+	}
+	return cluster{}, false
+}
+
+// ligatureCluster returns the cluster of the ligature glyph of the runes.
+func ligatureCluster(g *Glyph, runes string) cluster {
+	last, _ := utf8.DecodeLastRuneInString(runes)
+	first, _ := utf8.DecodeRuneInString(runes)
+	return cluster{
+		glyph: g, step: g.step(),
+		first: first, last: last,
+		runes: utf8.RuneCountInString(runes), bytes: len(runes),
+	}
+}
+
+// hasGlyph reports whether the font has an own glyph of the rune, not
+// one of Fallback or of the Unknown policy.
+func (f *Font) hasGlyph(r rune) bool {
+	if f.Config.Has(r) || f.added[r] != nil {
+		return true
+	}
+	return f.source != nil && f.source.has(r)
+}
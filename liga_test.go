@@ -0,0 +1,84 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// glyphTexts returns texts of glyphs placed by EachGlyph.
+func glyphTexts(f *Font, s string) []string {
+	var out []string
+	f.EachGlyph(s, func(g PlacedGlyph) bool {
+		out = append(out, g.Text)
+		return true
+	})
+	return out
+}
+
+func TestLigatureForms(t *testing.T) {
+	f := testFont()
+	if err := f.AddGlyph('ﬁ', image.NewAlpha(image.Rect(0, 0, 10, 10)), GlyphMetrics{Advance: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if got := glyphTexts(f, "ffix"); len(got) != 4 {
+		t.Errorf("glyphs %q without ligatures", got)
+	}
+	plain := f.advanceSize("ffix")
+
+	f.Ligatures = true
+	// there is no glyph of ffi, i is 4 pixels wide
+	if got := glyphTexts(f, "ffix"); len(got) != 3 || got[1] != "fi" {
+		t.Errorf("glyphs %q", got)
+	}
+	if w := f.advanceSize("ffix"); w != plain-12+10 {
+		t.Errorf("width %d, expect %d", w, plain-12+10)
+	}
+	if w := f.MetricsBatch([]string{"ffix"})[0].X; w != int(plain-12+10) {
+		t.Errorf("batch width %d", w)
+	}
+	if s := f.Ellipsize("fifififi", 35); s != "fi..." {
+		t.Errorf("ellipsized %q", s)
+	}
+}
+
+func TestLigatureSubstitutions(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fg, ig, ag := int(ttf.Index('f')), int(ttf.Index('i')), int(ttf.Index('W'))
+	gsub := otlTable([]otlFeature{
+		// f i -> W
+		{"liga", 4, words(nil, 1, 18, 1, 8, 1, 4, ag, 2, ig, 1, 1, fg)},
+	})
+	ligas, err := ligatureSubstitutions(gsub)
+	if err != nil || len(ligas) != 1 || len(ligas[0].glyphs) != 2 || int(ligas[0].glyph) != ag {
+		t.Fatalf("ligatures %v, %v", ligas, err)
+	}
+
+	data := withTable(goregular.TTF, "GSUB", gsub)
+	img, fc, err := rasterizeData(context.Background(), data, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.subst, err = newSubstitutions(data, 16, fc); err != nil || f.subst == nil {
+		t.Fatal(f.subst, err)
+	}
+	if got := glyphTexts(f, "fix"); len(got) != 3 {
+		t.Errorf("glyphs %q without ligatures", got)
+	}
+	f.Ligatures = true
+	var steps []int32
+	f.EachGlyph("fix", func(g PlacedGlyph) bool {
+		steps = append(steps, g.Step)
+		return true
+	})
+	if w := fc.Glyphs[fc.Index('W')].step(); len(steps) != 2 || steps[0] != w {
+		t.Errorf("steps %v, step of the ligature %d", steps, w)
+	}
+}
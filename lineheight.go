@@ -0,0 +1,88 @@
+package glsymbol
+
+// LineHeight is the distance between baselines of adjacent lines,
+// modelled after the CSS line-height property. The zero value is the
+// normal line height, which is the height of glyph cells of the font.
+//
+// As in CSS, the difference between the line height and the height of
+// glyph cells is the leading, half of it is added above glyphs and half
+// below, so text stays vertically centered in its lines.
+type LineHeight struct {
+	factor float32 // multiplier of the normal line height, if not zero
+	pixels int32   // fixed line height, if not zero
+}
+
+// LineNormal returns the normal line height.
+func LineNormal() LineHeight {
+	return LineHeight{}
+}
+
+// LineMultiple returns a line height of m times the height of glyph cells,
+// like a unitless CSS line-height: 1.5 gives one and a half spaced lines.
+func LineMultiple(m float32) LineHeight {
+	return LineHeight{factor: m}
+}
+
+// LinePixels returns a fixed line height in pixels.
+func LinePixels(px int32) LineHeight {
+	return LineHeight{pixels: px}
+}
+
+// Pixels returns the line height for glyph cells of the given height.
+// The result is never negative.
+func (lh LineHeight) Pixels(cell int32) int32 {
+	return lh.PixelsRounded(cell, RoundDefault)
+}
+
+// PixelsRounded returns the line height as Pixels, a fractional height
+// is rounded by the policy.
+func (lh LineHeight) PixelsRounded(cell int32, r Rounding) (h int32) {
+	switch {
+	case lh.pixels != 0:
+		h = lh.pixels
+	case lh.factor != 0:
+		if r == RoundDefault {
+			r = RoundNearest
+		}
+		h = r.Round(lh.factor * float32(cell))
+	default:
+		h = cell
+	}
+	if h < 0 {
+		h = 0
+	}
+	return
+}
+
+// lineHeight returns the distance between lines of the font.
+func (f *Font) lineHeight() int32 {
+	h := f.LineHeight.PixelsRounded(f.MaxGlyphHeight, f.rounding())
+	if f.Grid.active() {
+		if r := h % f.Grid.Step; r != 0 || h == 0 {
+			h += f.Grid.Step - r
+		}
+	}
+	return h
+}
+
+// halfLeading returns the distance from the top of a line to the top
+// of its glyph cells, negative if lines are lower than glyph cells.
+func (f *Font) halfLeading() int32 {
+	return (f.lineHeight() - f.MaxGlyphHeight) / 2
+}
+
+// BoundingBox returns the size of the text drawn line by line, with
+// lines broken by the newline policy and placed with the line height
+// of the font.
+func (f *Font) BoundingBox(text string) (width, height int32) {
+	sp := startSpan(StageLayout)
+	defer sp.end()
+
+	lines := splitLines(text)
+	for _, l := range lines {
+		if w := f.advanceSize(l); width < w {
+			width = w
+		}
+	}
+	return width, int32(len(lines)) * f.lineHeight()
+}
@@ -0,0 +1,79 @@
+package glsymbol
+
+import "testing"
+
+func TestLineHeight(t *testing.T) {
+	for _, tc := range []struct {
+		lh     LineHeight
+		r      Rounding
+		expect int32
+	}{
+		{LineNormal(), RoundFloor, 16},
+		{LineMultiple(1.5), RoundFloor, 24},
+		{LineMultiple(1.2), RoundFloor, 19},
+		{LineMultiple(1.2), RoundNearest, 19},
+		{LineMultiple(1.2), RoundCeil, 20},
+		{LineMultiple(1.25), RoundNearest, 20},
+		{LinePixels(20), RoundCeil, 20},
+		{LinePixels(-3), RoundFloor, 0},
+	} {
+		if h := tc.lh.PixelsRounded(16, tc.r); h != tc.expect {
+			t.Errorf("%+v: %d != %d", tc.lh, h, tc.expect)
+		}
+	}
+	// fractional heights are rounded to the nearest by default
+	if h := LineMultiple(1.22).Pixels(16); h != 20 {
+		t.Errorf("default height %d", h)
+	}
+	if h := LineMultiple(1.22).PixelsRounded(16, RoundFloor); h != 19 {
+		t.Errorf("floor height %d", h)
+	}
+	f := testFont()
+	f.LineHeight = LineMultiple(1.22)
+	if h := f.lineHeight(); h != 20 {
+		t.Errorf("line height of the font %d", h)
+	}
+
+	f = testFont()
+	f.LineHeight = LineMultiple(1.5)
+	if lead := f.halfLeading(); lead != 4 {
+		t.Errorf("half leading %d", lead)
+	}
+	if w, h := f.BoundingBox("ab\nabc\n"); w != 24 || h != 72 {
+		t.Errorf("bounding box %d,%d", w, h)
+	}
+	text := NewText(f, "ab\nabc")
+	if w, h := text.Size(); w != 24 || h != 48 {
+		t.Errorf("text size %d,%d", w, h)
+	}
+	lines, height := f.LayoutParagraphs("ab\nabc", 100, ParagraphStyle{})
+	if height != 48 || lines[1].Y != 24 {
+		t.Errorf("paragraphs: height %d, lines %v", height, lines)
+	}
+
+	for _, tc := range []struct {
+		dx, dy float32
+		offset int
+	}{
+		{0, 0, 0},
+		{3, 10, 0},
+		{5, 23, 1},
+		{100, 0, 2},
+		{9, 24, 4},
+		{100, 1000, 6},
+		{-5, -5, 0},
+	} {
+		if o := text.OffsetAt(tc.dx, tc.dy); o != tc.offset {
+			t.Errorf("offset at %v,%v is %d, expect %d", tc.dx, tc.dy, o, tc.offset)
+		}
+	}
+	for offset := 0; offset <= text.Len(); offset++ {
+		dx, dy, err := text.CaretPos(offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o := text.OffsetAt(float32(dx), float32(dy)); o != offset {
+			t.Errorf("caret of offset %d at %d,%d gives offset %d", offset, dx, dy, o)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package glsymbol
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A ListView draws a virtualized list of single line items, such as
+// lines of a log: only rows visible in the viewport are requested from
+// Item, laid out and drawn, so the amount of items is not limited.
+// Rows are as high as lines of the font, text wider than the list is
+// cut with an ellipsis and the selected row is highlighted by the
+// highlight color of the theme.
+//
+// Up, Down, Home and End keys move the selection and scroll to it.
+type ListView struct {
+	Font *Font
+
+	Count int                // amount of items
+	Item  func(i int) string // text of the item with the index
+
+	Width, Height float32 // size of the viewport of the list
+	Scroll        float32 // distance from the top of the list to the top of the viewport
+	Selected      int     // index of the selected item, -1 for none
+
+	// OnSelect is called when the selection changes by keys or clicks.
+	OnSelect func(i int)
+}
+
+// A ListRow is a visible row of a ListView.
+type ListRow struct {
+	Index int
+	Text  string // text of the item cut to the width of the list
+	Box   Rect
+}
+
+// rowHeight returns the height of rows.
+func (l *ListView) rowHeight() float32 {
+	return float32(l.Font.lineHeight())
+}
+
+// ContentHeight returns the height of all rows.
+func (l *ListView) ContentHeight() float32 {
+	return float32(l.Count) * l.rowHeight()
+}
+
+// clampScroll keeps the scroll offset inside of the content.
+func (l *ListView) clampScroll() {
+	if max := l.ContentHeight() - l.Height; max < l.Scroll {
+		l.Scroll = max
+	}
+	if l.Scroll < 0 {
+		l.Scroll = 0
+	}
+}
+
+// Rows lays out rows visible in the viewport with the top left corner
+// at x, y. Rows partially visible at the edges are included.
+func (l *ListView) Rows(x, y float32) (rows []ListRow) {
+	l.clampScroll()
+	rh := l.rowHeight()
+	if rh <= 0 || l.Count == 0 {
+		return nil
+	}
+	first := int(l.Scroll / rh)
+	last := int(math.Ceil(float64((l.Scroll + l.Height) / rh)))
+	if l.Count < last {
+		last = l.Count
+	}
+	for i := first; i < last; i++ {
+		top := below(y, float32(i)*rh-l.Scroll)
+		rows = append(rows, ListRow{
+			Index: i,
+			Text:  l.Font.Ellipsize(l.Item(i), int32(l.Width)),
+			Box:   Rect{X: x, Y: linePos(top, rh), Width: l.Width, Height: rh},
+		})
+	}
+	return
+}
+
+// RowAt returns the index of the item at the point, for the list placed
+// with the top left corner at x, y, or -1.
+func (l *ListView) RowAt(x, y, px, py float32) int {
+	if px < x || x+l.Width <= px {
+		return -1
+	}
+	d := py - y // distance from the top of the viewport
+	if origin != OriginTopLeft {
+		d = -d
+	}
+	if d < 0 || l.Height <= d {
+		return -1
+	}
+	i := int((d + l.Scroll) / l.rowHeight())
+	if l.Count <= i {
+		return -1
+	}
+	return i
+}
+
+// Click selects the item at the point, see RowAt, and reports whether
+// there is one.
+func (l *ListView) Click(x, y, px, py float32) bool {
+	i := l.RowAt(x, y, px, py)
+	if i < 0 {
+		return false
+	}
+	l.Select(i)
+	return true
+}
+
+// Select selects the item, scrolls to it and calls OnSelect if the
+// selection changes.
+func (l *ListView) Select(i int) {
+	if l.Count <= i {
+		i = l.Count - 1
+	}
+	if i < 0 {
+		i = -1
+	}
+	changed := i != l.Selected
+	l.Selected = i
+	if 0 <= i {
+		l.ScrollTo(i)
+	}
+	if changed && l.OnSelect != nil {
+		l.OnSelect(i)
+	}
+}
+
+// ScrollTo scrolls the list the least to show the whole item.
+func (l *ListView) ScrollTo(i int) {
+	rh := l.rowHeight()
+	top := float32(i) * rh
+	if top < l.Scroll {
+		l.Scroll = top
+	} else if l.Scroll+l.Height < top+rh {
+		l.Scroll = top + rh - l.Height
+	}
+	l.clampScroll()
+}
+
+// HandleKey moves the selection by the key.
+func (l *ListView) HandleKey(k Key) bool {
+	if l.Count == 0 {
+		return false
+	}
+	switch k {
+	case KeyUp:
+		if l.Selected <= 0 {
+			return false
+		}
+		l.Select(l.Selected - 1)
+	case KeyDown:
+		if l.Count-1 <= l.Selected {
+			return false
+		}
+		l.Select(l.Selected + 1)
+	case KeyHome:
+		l.Select(0)
+	case KeyEnd:
+		l.Select(l.Count - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw draws visible rows with the top left corner at x, y. Rows are
+// clipped by the viewport of the list.
+func (l *ListView) Draw(x, y float32) error {
+	rows := l.Rows(x, y)
+	gl.PushAttrib(gl.SCISSOR_BIT | gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	clip := Rect{X: x, Y: linePos(y, l.Height), Width: l.Width, Height: l.Height}
+	scissor(clip)
+	for _, r := range rows {
+		if r.Index == l.Selected {
+			fillRect(r.Box, theme.color(ColorHighlight))
+		}
+		if err := l.Font.Printf(l.Font.startX(r.Box.X, r.Text), l.Font.lineY(rectTop(r.Box)), r.Text); err != nil {
+			return err
+		}
+	}
+	return checkGLError("ListView.Draw")
+}
+
+// scissor enables the scissor test for the rectangle given in the
+// coordinates of SetupOrtho.
+func scissor(r Rect) {
+	y := r.Y
+	if origin == OriginTopLeft {
+		_, h := viewportSize()
+		y = float32(h) - r.Y - r.Height
+	}
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(math.Floor(float64(r.X))), int32(math.Floor(float64(y))),
+		int32(math.Ceil(float64(r.Width))), int32(math.Ceil(float64(r.Height))))
+}
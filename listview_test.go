@@ -0,0 +1,72 @@
+package glsymbol
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEllipsize(t *testing.T) {
+	f := testFont()
+	for _, tc := range []struct {
+		in    string
+		width int32
+		out   string
+	}{
+		{"abc", 24, "abc"},
+		{"abcdef", 40, "ab..."},
+		{"abcdef", 24, "..."},
+		{"abcdef", 20, ""},
+	} {
+		if s := f.Ellipsize(tc.in, tc.width); s != tc.out {
+			t.Errorf("%q in %d: %q, expect %q", tc.in, tc.width, s, tc.out)
+		}
+	}
+}
+
+func TestListView(t *testing.T) {
+	f := testFont()
+	rh := float32(f.lineHeight())
+	requested := 0
+	l := ListView{
+		Font:  f,
+		Count: 1000000,
+		Item: func(i int) string {
+			requested++
+			return "item " + strconv.Itoa(i)
+		},
+		Width:    200,
+		Height:   3.5 * rh,
+		Scroll:   10 * rh,
+		Selected: -1,
+	}
+	rows := l.Rows(0, 100)
+	if len(rows) != 4 || rows[0].Index != 10 || requested != 4 {
+		t.Fatalf("rows %+v, %d requested", rows, requested)
+	}
+	if rectTop(rows[0].Box) != 100 || rectTop(rows[1].Box) != 100-rh {
+		t.Errorf("boxes %v %v", rows[0].Box, rows[1].Box)
+	}
+	if i := l.RowAt(0, 100, 5, 100-rh-1); i != 11 {
+		t.Errorf("row at the point %d", i)
+	}
+	if i := l.RowAt(0, 100, 5, 101); i != -1 {
+		t.Errorf("row above the list %d", i)
+	}
+
+	var selected []int
+	l.OnSelect = func(i int) { selected = append(selected, i) }
+	l.Click(0, 100, 5, 100-1)
+	for i := 0; i < 4; i++ {
+		l.HandleKey(KeyDown)
+	}
+	if l.Selected != 14 || l.Scroll != 15*rh-l.Height {
+		t.Errorf("selected %d, scroll %v", l.Selected, l.Scroll)
+	}
+	l.HandleKey(KeyEnd)
+	if l.Scroll != l.ContentHeight()-l.Height {
+		t.Errorf("scroll at the end %v", l.Scroll)
+	}
+	if len(selected) != 6 || selected[5] != l.Count-1 {
+		t.Errorf("selections %v", selected)
+	}
+}
@@ -0,0 +1,62 @@
+package glsymbol
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// A Locale formats values by the rules of a language: decimal separator,
+// digit grouping and date layout. Engineering tools then show numbers the
+// way their users read them without formatting strings themselves.
+type Locale struct {
+	Tag language.Tag
+
+	// DateLayout is the time.Time layout of dates, for example
+	// "02.01.2006 15:04" for German.
+	DateLayout string
+
+	printer *message.Printer
+}
+
+// NewLocale creates a locale for the language. An empty date layout
+// means "2006-01-02 15:04:05".
+func NewLocale(tag language.Tag, dateLayout string) *Locale {
+	if dateLayout == "" {
+		dateLayout = "2006-01-02 15:04:05"
+	}
+	return &Locale{
+		Tag:        tag,
+		DateLayout: dateLayout,
+		printer:    message.NewPrinter(tag),
+	}
+}
+
+// Sprintf formats according to the format specifier as fmt.Sprintf with
+// numbers formatted by the rules of the locale. Values of time.Time are
+// formatted by DateLayout. A nil locale formats as the "und" language.
+func (l *Locale) Sprintf(format string, args ...any) string {
+	if l == nil {
+		l = defaultLocale
+	}
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			if i == 0 {
+				// do not modify arguments of the caller
+				args = append([]any(nil), args...)
+			}
+			args[i] = t.Format(l.DateLayout)
+		}
+	}
+	return l.printer.Sprintf(format, args...)
+}
+
+var defaultLocale = NewLocale(language.Und, "")
+
+// PrintfValue formats the values by the locale as Locale.Sprintf and
+// draws the result as Printf, x is the place of the pen: the right edge
+// of the result in the RightToLeft direction.
+func (f *Font) PrintfValue(x, y float32, l *Locale, format string, args ...any) error {
+	return f.Printf(x, y, l.Sprintf(format, args...))
+}
@@ -0,0 +1,31 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocale(t *testing.T) {
+	date := time.Date(2023, 9, 1, 14, 30, 0, 0, time.UTC)
+	de := NewLocale(language.German, "02.01.2006")
+	en := NewLocale(language.English, "01/02/2006")
+	for _, tc := range []struct {
+		l      *Locale
+		format string
+		args   []any
+		expect string
+	}{
+		{de, "%.2f", []any{1234567.891}, "1.234.567,89"},
+		{en, "%.2f", []any{1234567.891}, "1,234,567.89"},
+		{de, "%d mm", []any{12000}, "12.000 mm"},
+		{de, "%v", []any{date}, "01.09.2023"},
+		{en, "date %v, %d", []any{date, 5}, "date 09/01/2023, 5"},
+		{nil, "%v", []any{date}, "2023-09-01 14:30:00"},
+	} {
+		if s := tc.l.Sprintf(tc.format, tc.args...); s != tc.expect {
+			t.Errorf("%q: %q, expect %q", tc.format, s, tc.expect)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package glsymbol
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Localize sets variants of glyphs for text of the language, as the
+// 'locl' feature of OpenType fonts does: runes of spans tagged with the
+// language, see Span.Lang, are drawn and measured by glyphs of the
+// variant runes. Variants are usually glyphs of private use runes added
+// by AddGlyph, for example the Serbian italic be or the Polish kreska
+// accents. Variants of a language apply to its subtags as well, so
+// variants of "sr" are used for "sr-Cyrl-RS" unless it has own ones.
+// Nil variants remove variants of the language.
+func (f *Font) Localize(tag language.Tag, variants map[rune]rune) {
+	if len(variants) == 0 {
+		delete(f.locl, tag)
+		return
+	}
+	if f.locl == nil {
+		f.locl = make(map[language.Tag]map[rune]rune)
+	}
+	f.locl[tag] = variants
+}
+
+// variants returns variants of glyphs of the language or of its closest
+// parent, or nil.
+func (f *Font) variants(tag language.Tag) map[rune]rune {
+	if len(f.locl) == 0 {
+		return nil
+	}
+	for {
+		if v, ok := f.locl[tag]; ok {
+			return v
+		}
+		if tag.IsRoot() {
+			return nil
+		}
+		tag = tag.Parent()
+	}
+}
+
+// localize returns the text of the language with runes replaced by their
+// variants.
+func (f *Font) localize(text string, tag language.Tag) string {
+	v := f.variants(tag)
+	if v == nil {
+		return text
+	}
+	return strings.Map(func(r rune) rune {
+		if alt, ok := v[r]; ok {
+			return alt
+		}
+		return r
+	}, text)
+}
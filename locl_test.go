@@ -0,0 +1,34 @@
+package glsymbol
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocalize(t *testing.T) {
+	f := testFont()
+	f.Localize(language.Serbian, map[rune]rune{'b': 'i'})
+	for _, tc := range []struct {
+		tag    language.Tag
+		expect string
+	}{
+		{language.Und, "abc"},
+		{language.Russian, "abc"},
+		{language.Serbian, "aic"},
+		{language.MustParse("sr-Cyrl-RS"), "aic"},
+	} {
+		if s := f.localize("abc", tc.tag); s != tc.expect {
+			t.Errorf("%v: %q, expect %q", tc.tag, s, tc.expect)
+		}
+	}
+	// 'i' is narrower than 'b'
+	spans := []Span{{Text: "ab"}, {Text: "ab", Lang: language.Serbian}}
+	if w := f.SpansWidth(spans); w != 8+8+8+4 {
+		t.Errorf("width %d", w)
+	}
+	f.Localize(language.Serbian, nil)
+	if s := f.localize("abc", language.Serbian); s != "abc" {
+		t.Errorf("variants are not removed: %q", s)
+	}
+}
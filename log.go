@@ -0,0 +1,41 @@
+package glsymbol
+
+// Logger receives warnings of the package about problems which do not
+// fail the current operation: runes without glyphs, growth of sprite
+// sheets, glyphs supplied by Font.Fallback and recovered GL errors.
+//
+// Arguments after the message are alternating keys and values, so
+// *slog.Logger implements Logger, as do thin adapters of other
+// structured loggers.
+type Logger interface {
+	Warn(msg string, args ...interface{})
+}
+
+// logger receives warnings, nil if they are dropped.
+var logger Logger
+
+// SetLogger sets the logger of warnings of the package, or drops
+// warnings if l is nil. Warnings are logged on the goroutine doing
+// the work.
+func SetLogger(l Logger) {
+	logger = l
+}
+
+// warn logs the warning if a logger is set.
+func warn(msg string, args ...interface{}) {
+	if logger != nil {
+		logger.Warn(msg, args...)
+	}
+}
+
+// warnMissing logs a rune without a glyph, once per rune of the font.
+func (f *Font) warnMissing(r rune) {
+	if logger == nil || f.missing[r] {
+		return
+	}
+	if f.missing == nil {
+		f.missing = make(map[rune]bool)
+	}
+	f.missing[r] = true
+	warn("glsymbol: rune has no glyph", "rune", r)
+}
@@ -0,0 +1,52 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+)
+
+// testLogger records warnings as lines.
+type testLogger []string
+
+func (l *testLogger) Warn(msg string, args ...interface{}) {
+	*l = append(*l, strings.TrimSpace(msg+" "+fmt.Sprintln(args...)))
+}
+
+func TestLogger(t *testing.T) {
+	var log testLogger
+	SetLogger(&log)
+	defer SetLogger(nil)
+
+	f := testFont()
+	f.advanceSize("aЖЖb")
+	f.advanceSize("Ж")
+	if len(log) != 1 || !strings.Contains(log[0], "rune has no glyph") {
+		t.Fatalf("warnings %q", log)
+	}
+
+	log = nil
+	f.Fallback = func(r rune) image.Image {
+		if r == 'Я' {
+			return image.NewAlpha(image.Rect(0, 0, 6, 10))
+		}
+		return nil
+	}
+	f.advanceSize("ЯЯ¤")
+	expect := []string{
+		"glsymbol: glyph supplied by fallback rune 1071",
+		"glsymbol: sprite sheet grows width 6 height 17",
+		"glsymbol: rune has no glyph rune 164",
+	}
+	if strings.Join(log, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("warnings %q", log)
+	}
+
+	SetLogger(nil)
+	log = nil
+	f.advanceSize("Ы")
+	if len(log) != 0 {
+		t.Errorf("warnings without logger %q", log)
+	}
+}
@@ -0,0 +1,116 @@
+package glsymbol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Mask restricts text typed into an Input.
+type Mask interface {
+	// Accept reports whether the text may be typed, it may be
+	// an incomplete value, for example "-" or "1e" of a float.
+	Accept(text string) bool
+
+	// Valid reports whether the text is a complete value.
+	Valid(text string) bool
+}
+
+// MaskDigits accepts decimal digits only.
+var MaskDigits Mask = digitsMask{}
+
+type digitsMask struct{}
+
+func (digitsMask) Accept(text string) bool {
+	for _, r := range text {
+		if r < '0' || '9' < r {
+			return false
+		}
+	}
+	return true
+}
+
+func (m digitsMask) Valid(text string) bool {
+	return text != "" && m.Accept(text)
+}
+
+// MaskFloat returns a mask of a floating-point number as strconv parses
+// it, for example "-1.5e3", followed by an optional unit of the list
+// after optional spaces, for example "2.5 mm". See ParseFloatUnit.
+func MaskFloat(units ...string) Mask {
+	return floatMask(units)
+}
+
+type floatMask []string
+
+func (m floatMask) Accept(text string) bool {
+	n, _ := scanFloat(text)
+	rest := strings.TrimLeft(text[n:], " ")
+	if rest == "" {
+		return true
+	}
+	return n != 0 && m.unit(rest, strings.HasPrefix)
+}
+
+func (m floatMask) Valid(text string) bool {
+	n, complete := scanFloat(text)
+	if !complete {
+		return false
+	}
+	rest := strings.TrimLeft(text[n:], " ")
+	return rest == "" || m.unit(rest, func(u, s string) bool { return u == s })
+}
+
+// unit reports whether the string matches a unit of the mask.
+func (m floatMask) unit(s string, match func(unit, s string) bool) bool {
+	for _, u := range m {
+		if match(u, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFloat returns the length of the leading floating-point number of
+// the string, which may be incomplete, and whether it is complete.
+func scanFloat(s string) (n int, complete bool) {
+	digits := func() (found bool) {
+		for n < len(s) && '0' <= s[n] && s[n] <= '9' {
+			n++
+			found = true
+		}
+		return
+	}
+	if n < len(s) && (s[n] == '+' || s[n] == '-') {
+		n++
+	}
+	mantissa := digits()
+	if n < len(s) && s[n] == '.' {
+		n++
+		if digits() {
+			mantissa = true
+		}
+	}
+	if !mantissa {
+		return n, false
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		n++
+		if n < len(s) && (s[n] == '+' || s[n] == '-') {
+			n++
+		}
+		return n, digits()
+	}
+	return n, true
+}
+
+// ParseFloatUnit parses text accepted by MaskFloat with the units and
+// returns the number and the unit, which is empty if not given.
+func ParseFloatUnit(text string, units ...string) (v float64, unit string, err error) {
+	if !MaskFloat(units...).Valid(text) {
+		return 0, "", fmt.Errorf("glsymbol: %q is not a number", text)
+	}
+	n, _ := scanFloat(text)
+	v, err = strconv.ParseFloat(text[:n], 64)
+	return v, strings.TrimLeft(text[n:], " "), err
+}
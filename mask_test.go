@@ -0,0 +1,72 @@
+package glsymbol
+
+import "testing"
+
+func TestMaskFloat(t *testing.T) {
+	m := MaskFloat("mm", "m")
+	for _, tc := range []struct {
+		text          string
+		accept, valid bool
+	}{
+		{"", true, false},
+		{"-", true, false},
+		{"1.5", true, true},
+		{".5", true, true},
+		{"1e", true, false},
+		{"1e-3", true, true},
+		{"2.5 m", true, true},
+		{"2.5 mm", true, true},
+		{"2.5 ", true, true},
+		{"2.5 k", false, false},
+		{"mm", false, false},
+		{"1..", false, false},
+	} {
+		if a, v := m.Accept(tc.text), m.Valid(tc.text); a != tc.accept || v != tc.valid {
+			t.Errorf("%q: accept %v, valid %v", tc.text, a, v)
+		}
+	}
+	v, unit, err := ParseFloatUnit("-2.5e1 mm", "mm")
+	if err != nil || v != -25 || unit != "mm" {
+		t.Errorf("parse %v %q %v", v, unit, err)
+	}
+	if _, _, err := ParseFloatUnit("1e", "mm"); err == nil {
+		t.Errorf("incomplete number is parsed")
+	}
+}
+
+func TestInputMask(t *testing.T) {
+	in := NewInput(testFont(), "")
+	in.Mask = MaskDigits
+	in.MaxLength = 3
+	for _, r := range "1a23 4" {
+		in.HandleChar(r)
+	}
+	if in.String() != "123" || !in.Valid() {
+		t.Errorf("text %q", in.String())
+	}
+
+	in = NewInput(testFont(), "")
+	in.Mask = MaskFloat("mm")
+	for _, r := range "1e" {
+		in.HandleChar(r)
+	}
+	if in.Valid() {
+		t.Errorf("incomplete number is valid")
+	}
+}
+
+func TestInputSecret(t *testing.T) {
+	in := &Input{Font: testFont(), Secret: true}
+	in.SetText("abc")
+	if s := in.line.String(); s != "***" {
+		t.Errorf("secret text is drawn as %q", s)
+	}
+	in.MoveLogical(-1)
+	in.Backspace()
+	if in.String() != "ac" || in.Caret() != 1 {
+		t.Errorf("text %q, caret %d", in.String(), in.Caret())
+	}
+	if x := in.CaretX(); x != testFont().advanceSize("*") {
+		t.Errorf("caret x %d", x)
+	}
+}
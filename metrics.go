@@ -0,0 +1,88 @@
+package glsymbol
+
+import (
+	"image"
+	"math"
+)
+
+// averageStep returns the mean distance the pen moves over a glyph of
+// the font, computed on the first call.
+func (f *Font) averageStep() float64 {
+	if f.avgStep != 0 || f.Config == nil {
+		return f.avgStep
+	}
+	var sum int64
+	for i := range f.Config.Glyphs {
+		sum += int64(f.Config.Glyphs[i].step())
+	}
+	if n := len(f.Config.Glyphs); n != 0 {
+		f.avgStep = float64(sum) / float64(n)
+	}
+	return f.avgStep
+}
+
+// ApproxWidth estimates the width of a single line string of n runes
+// by the average advance of glyphs of the font, without layout. It is
+// exact for monospaced fonts. Virtualized lists use it to estimate
+// scroll extents of many rows and lay out only visible ones.
+func (f *Font) ApproxWidth(nRunes int) int {
+	return int(math.Round(f.averageStep() * float64(nRunes)))
+}
+
+// Metrics returns the exact size of the text as BoundingBox.
+func (f *Font) Metrics(text string) image.Point {
+	w, h := f.BoundingBox(text)
+	return image.Pt(int(w), int(h))
+}
+
+// MetricsBatch returns sizes of many strings as Metrics, for example to
+// size table columns over thousands of cells. The strings are measured
+// in a single pass with a table of glyph steps taken from Arena, so
+// there is no per-string overhead.
+func (f *Font) MetricsBatch(lines []string) []image.Point {
+	sp := startSpan(StageLayout)
+	defer sp.end()
+
+	out := make([]image.Point, len(lines))
+	steps := f.Arena.Int32s(len(f.Config.Glyphs))
+	for i := range steps {
+		steps[i] = f.step(f.Config.RuneAt(i), &f.Config.Glyphs[i])
+	}
+	c := f.Config
+	lh := int(f.lineHeight())
+	for i, s := range lines {
+		var w, lw int32
+		n := 1
+		for {
+			end, next := nextBreak(s)
+			lw = 0
+			prev := rune(-1)
+			line := cleanLine(s[:end])
+			if f.Ligatures || f.Shaper != nil || f.Bidi != BidiOff || !f.NoJoining && hasArabic(line) {
+				// steps of ligatures and shaped glyphs are not in the table,
+				// neither are the visual order and joined letters
+				lw = f.advanceSize(line)
+				line = ""
+			}
+			for _, r := range line {
+				if i := c.Index(r); 0 <= i && !f.substitutes() {
+					lw += steps[i]
+				} else {
+					lw += f.step(r, f.glyph(r))
+				}
+				lw += f.kerning(prev, r)
+				prev = r
+			}
+			if w < lw {
+				w = lw
+			}
+			if next < 0 {
+				break
+			}
+			s = s[next:]
+			n++
+		}
+		out[i] = image.Pt(int(w), n*lh)
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package glsymbol
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestApproxWidth(t *testing.T) {
+	f := testFont()
+	// 93 glyphs of 8 pixels and 2 of 4 pixels
+	if w := f.ApproxWidth(95); w != 93*8+2*4 {
+		t.Errorf("approximate width %d", w)
+	}
+	if w := f.ApproxWidth(0); w != 0 {
+		t.Errorf("approximate width of nothing %d", w)
+	}
+	if p := f.Metrics("ab\ni"); p != image.Pt(16, 2*int(f.lineHeight())) {
+		t.Errorf("metrics %v", p)
+	}
+}
+
+func TestMetricsBatch(t *testing.T) {
+	f := testFont()
+	f.Arena = new(FrameArena)
+	lines := []string{"", "abc", "i\r\nii", "long line"}
+	got := f.MetricsBatch(lines)
+	for i, l := range lines {
+		if p := f.Metrics(l); got[i] != p {
+			t.Errorf("%q: %v, expect %v", l, got[i], p)
+		}
+	}
+}
+
+func TestAdvanceSizeOutOfRange(t *testing.T) {
+	f := testFont()
+	// runes below and above the range, and an invalid rune
+	for _, s := range []string{"\x00", "\t", "é", "\U0010ffff", "\xff", string(rune(-1))} {
+		if w := f.advanceSize("a" + s + "a"); w != 16 {
+			t.Errorf("%q: advance %d", s, w)
+		}
+	}
+}
+
+func BenchmarkAdvanceSize(b *testing.B) {
+	f := testFont()
+	s := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 4)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.advanceSize(s)
+	}
+}
+
+func BenchmarkAdvanceSizeFallback(b *testing.B) {
+	f := testFont()
+	f.Fallback = func(rune) image.Image { return nil }
+	s := strings.Repeat("Größe über 30 °C. ", 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.advanceSize(s)
+	}
+}
@@ -0,0 +1,160 @@
+package glsymbol
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A Minimap is an overview of a large text at a tiny scale, as in the
+// side bar of code editors. Every line is a strip of LineHeight pixels
+// and every rune is a column of one pixel, whose luminance depends on
+// the kind of the rune, so no glyphs are drawn.
+//
+// The overview is rendered into an image on Update and uploaded into
+// a texture when it is drawn.
+type Minimap struct {
+	Width      int // width in pixels, longer lines are clipped
+	LineHeight int // height of a line in pixels, usually 1..3
+	TabWidth   int // columns of a tab, zero means 4
+
+	img     *image.RGBA
+	lines   int
+	texture uint32
+	dirty   bool // img is not uploaded into the texture
+}
+
+// NewMinimap creates a minimap of the given width and line height.
+func NewMinimap(width, lineHeight int) *Minimap {
+	if lineHeight < 1 {
+		lineHeight = 1
+	}
+	return &Minimap{Width: width, LineHeight: lineHeight}
+}
+
+// Lines is a source of lines of text, for example Text.
+type Lines interface {
+	Lines() int
+	Line(i int) string
+}
+
+// Update renders the lines into the minimap.
+func (m *Minimap) Update(t Lines) {
+	m.lines = t.Lines()
+	rect := image.Rect(0, 0, m.Width, m.lines*m.LineHeight)
+	if m.img == nil || m.img.Bounds() != rect {
+		m.img = image.NewRGBA(rect)
+	} else {
+		for i := range m.img.Pix {
+			m.img.Pix[i] = 0
+		}
+	}
+	tab := m.TabWidth
+	if tab <= 0 {
+		tab = 4
+	}
+	// strips of lines higher than a pixel are separated by a gap
+	strip := m.LineHeight
+	if 1 < strip {
+		strip--
+	}
+	for i := 0; i < m.lines; i++ {
+		x := 0
+		for _, r := range t.Line(i) {
+			if r == '\t' {
+				x += tab - x%tab
+				continue
+			}
+			if m.Width <= x {
+				break
+			}
+			if a := runeLuminance(r); a != 0 {
+				for dy := 0; dy < strip; dy++ {
+					m.img.SetRGBA(x, i*m.LineHeight+dy, color.RGBA{a, a, a, a})
+				}
+			}
+			x++
+		}
+	}
+	m.dirty = true
+}
+
+// runeLuminance returns the luminance of a rune in the minimap.
+func runeLuminance(r rune) uint8 {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 0xff
+	default:
+		return 0x80
+	}
+}
+
+// Image returns the rendered minimap, white strips on a transparent
+// background. The image is owned by the minimap.
+func (m *Minimap) Image() *image.RGBA {
+	return m.img
+}
+
+// Size returns the size of the minimap in pixels.
+func (m *Minimap) Size() (width, height int32) {
+	return int32(m.Width), int32(m.lines * m.LineHeight)
+}
+
+// Viewport returns the rectangle of the visible lines from first up to
+// first+count, relative to the top left corner of the minimap with Y
+// going down, to draw the viewport indicator.
+func (m *Minimap) Viewport(first, count int) Rect {
+	return Rect{
+		Y:      float32(first * m.LineHeight),
+		Width:  float32(m.Width),
+		Height: float32(count * m.LineHeight),
+	}
+}
+
+// LineAt returns the line at the distance dy from the top of the minimap,
+// clamped to the lines of the text, for scrolling by clicks.
+func (m *Minimap) LineAt(dy float32) int {
+	i := 0
+	if 0 < dy {
+		i = int(dy) / m.LineHeight
+	}
+	if m.lines <= i {
+		i = m.lines - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// Draw draws the minimap with the top left corner at x, y modulated by
+// the current color. The texture is updated if the minimap is changed.
+func (m *Minimap) Draw(x, y float32) error {
+	if m.img == nil || m.img.Bounds().Empty() {
+		return nil
+	}
+	if m.dirty || m.texture == 0 {
+		tex, err := uploadTexture(m.texture, m.img, gl.NEAREST)
+		if err != nil {
+			return err
+		}
+		m.texture = tex
+		m.dirty = false
+	}
+	w, h := m.Size()
+	drawTexture(m.texture, x, y, float32(w), float32(h))
+	return checkGLError("Minimap.Draw")
+}
+
+// Release releases the texture of the minimap.
+func (m *Minimap) Release() {
+	if m.texture != 0 {
+		gl.DeleteTextures(1, &m.texture)
+		m.texture = 0
+	}
+	m.img = nil
+}
@@ -0,0 +1,47 @@
+package glsymbol
+
+import (
+	"strings"
+	"testing"
+)
+
+type testLines []string
+
+func (l testLines) Lines() int        { return len(l) }
+func (l testLines) Line(i int) string { return l[i] }
+
+func TestMinimap(t *testing.T) {
+	m := NewMinimap(6, 3)
+	m.Update(testLines(strings.Split("ab c\n\n\tx;\nlong line", "\n")))
+	if w, h := m.Size(); w != 6 || h != 12 {
+		t.Fatalf("size %d,%d", w, h)
+	}
+	for _, tc := range []struct {
+		x, y int
+		a    uint8
+	}{
+		{0, 0, 0xff},
+		{1, 1, 0xff},
+		{2, 0, 0},
+		{3, 0, 0xff},
+		{0, 2, 0}, // gap between strips
+		{0, 3, 0}, // empty line
+		{4, 6, 0xff},
+		{5, 6, 0x80},
+		{4, 9, 0},
+		{5, 9, 0xff},
+	} {
+		if a := m.Image().RGBAAt(tc.x, tc.y).A; a != tc.a {
+			t.Errorf("pixel %d,%d: %#x, expect %#x", tc.x, tc.y, a, tc.a)
+		}
+	}
+
+	if r := m.Viewport(1, 2); r != (Rect{0, 3, 6, 6}) {
+		t.Errorf("viewport %v", r)
+	}
+	for dy, line := range map[float32]int{-1: 0, 0: 0, 4: 1, 11: 3, 100: 3} {
+		if l := m.LineAt(dy); l != line {
+			t.Errorf("line at %v: %d, expect %d", dy, l, line)
+		}
+	}
+}
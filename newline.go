@@ -0,0 +1,91 @@
+package glsymbol
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NewlinePolicy selects the characters which break lines of multi-line
+// text, such as Text, BoundingBox, DrawTextBox and LayoutParagraphs.
+// Single line functions, such as Printf, never break lines.
+type NewlinePolicy int
+
+// Newline policies.
+const (
+	// NewlineAll breaks lines at "\r\n", '\n', a lone '\r', vertical
+	// tab, form feed, NEL U+0085 and the Unicode line and paragraph
+	// separators U+2028 and U+2029. Text from any platform is shown
+	// without stray glyphs.
+	NewlineAll NewlinePolicy = iota
+
+	// NewlineCRLF breaks lines at '\n' and "\r\n" and drops lone
+	// '\r', other separators are drawn as glyphs.
+	NewlineCRLF
+
+	// NewlineLF breaks lines at '\n' only, other characters are
+	// drawn as glyphs.
+	NewlineLF
+)
+
+// newlines is the policy given by SetNewlinePolicy.
+var newlines NewlinePolicy
+
+// SetNewlinePolicy selects the line breaks of multi-line functions.
+// The default is NewlineAll.
+func SetNewlinePolicy(p NewlinePolicy) {
+	newlines = p
+}
+
+// nextBreak returns the end of the first line of s and the start of
+// the next line, or len(s) and -1 if s has a single line.
+func nextBreak(s string) (end, next int) {
+	if newlines == NewlineLF {
+		if i := strings.IndexByte(s, '\n'); 0 <= i {
+			return i, i + 1
+		}
+		return len(s), -1
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			if newlines == NewlineCRLF && 0 < i && s[i-1] == '\r' {
+				return i - 1, i + 1
+			}
+			return i, i + 1
+		case newlines == NewlineCRLF:
+		case c == '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				return i, i + 2
+			}
+			return i, i + 1
+		case c == '\v' || c == '\f':
+			return i, i + 1
+		case utf8.RuneStart(c) && utf8.RuneSelf <= c:
+			switch r, size := utf8.DecodeRuneInString(s[i:]); r {
+			case '\u0085', '\u2028', '\u2029':
+				return i, i + size
+			}
+		}
+	}
+	return len(s), -1
+}
+
+// splitLines splits the text into lines by the newline policy.
+func splitLines(text string) (lines []string) {
+	for {
+		end, next := nextBreak(text)
+		lines = append(lines, cleanLine(text[:end]))
+		if next < 0 {
+			return
+		}
+		text = text[next:]
+	}
+}
+
+// cleanLine removes lone carriage returns from the line for NewlineCRLF.
+func cleanLine(line string) string {
+	if newlines != NewlineCRLF || strings.IndexByte(line, '\r') < 0 {
+		return line
+	}
+	return strings.ReplaceAll(line, "\r", "")
+}
@@ -0,0 +1,40 @@
+package glsymbol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	defer SetNewlinePolicy(NewlineAll)
+	text := "a\r\nb\rc\nd\u2028e\u2029f\r"
+	for _, tc := range []struct {
+		policy NewlinePolicy
+		lines  []string
+	}{
+		{NewlineAll, []string{"a", "b", "c", "d", "e", "f", ""}},
+		{NewlineCRLF, []string{"a", "bc", "d\u2028e\u2029f"}},
+		{NewlineLF, []string{"a\r", "b\rc", "d\u2028e\u2029f\r"}},
+	} {
+		SetNewlinePolicy(tc.policy)
+		if lines := splitLines(text); !reflect.DeepEqual(lines, tc.lines) {
+			t.Errorf("policy %d: %q, expect %q", tc.policy, lines, tc.lines)
+		}
+	}
+}
+
+func TestTextNewlines(t *testing.T) {
+	text := NewText(testFont(), "one\r\ntwo\rthree")
+	if s := text.String(); s != "one\ntwo\nthree" {
+		t.Errorf("text %q", s)
+	}
+	if err := text.InsertAt(3, "\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if s := text.String(); s != "one\n\ntwo\nthree" || text.LineStart(2) != 5 {
+		t.Errorf("text %q", s)
+	}
+	if _, h := testFont().BoundingBox("a\r\nb"); h != 2*testFont().lineHeight() {
+		t.Errorf("height %d", h)
+	}
+}
@@ -0,0 +1,217 @@
+package glsymbol
+
+import (
+	"image/color"
+	"strconv"
+	"time"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Severity is the importance of a notification.
+type Severity int
+
+// Severities of notifications.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// Corner is a corner of the viewport.
+type Corner int
+
+// Corners of the viewport.
+const (
+	CornerTopRight Corner = iota
+	CornerTopLeft
+	CornerBottomRight
+	CornerBottomLeft
+)
+
+// Notifications is a queue of short messages, toasts, shown over the
+// scene for a time. Messages are wrapped to Width and stacked in the
+// Corner, the newest one nearest to it. They fade out during the last
+// Fade of their time. Messages exceeding Max or the height of the
+// viewport are not shown, their amount is shown as "+N" instead.
+//
+// Frames are drawn as the text frame of the theme with the border of
+// the "warning" or "error" color for those severities.
+type Notifications struct {
+	Font *Font
+
+	Corner  Corner
+	Width   float32 // width of messages, 300 if zero
+	Margin  float32 // distance from edges of the viewport
+	Spacing float32 // distance between messages
+
+	TTL  time.Duration // time of messages pushed by Push, 5 seconds if zero
+	Fade time.Duration // fading time at the end of messages
+	Max  int           // maximal amount of shown messages, zero means no limit
+
+	Clock Clock // time of messages, SystemClock if nil
+
+	items []notification
+}
+
+type notification struct {
+	text     string
+	severity Severity
+	expires  time.Time
+}
+
+// toast is a placed notification.
+type toast struct {
+	item  *notification
+	box   Rect
+	lines []LineBox
+	alpha float32
+}
+
+// Push adds the message for the default time.
+func (n *Notifications) Push(text string, s Severity) {
+	ttl := n.TTL
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+	n.PushTTL(text, s, ttl)
+}
+
+// PushTTL adds the message for the time.
+func (n *Notifications) PushTTL(text string, s Severity, ttl time.Duration) {
+	n.items = append(n.items, notification{text: text, severity: s, expires: now(n.Clock).Add(ttl)})
+}
+
+// Len returns the amount of messages which are not expired.
+func (n *Notifications) Len() int {
+	return len(n.items)
+}
+
+// Clear removes all messages.
+func (n *Notifications) Clear() {
+	n.items = n.items[:0]
+}
+
+// expire removes expired messages.
+func (n *Notifications) expire(t time.Time) {
+	k := 0
+	for _, it := range n.items {
+		if t.Before(it.expires) {
+			n.items[k] = it
+			k++
+		}
+	}
+	n.items = n.items[:k]
+}
+
+// layout places messages in the viewport of the size at the time and
+// returns them with the amount of messages which are not shown.
+func (n *Notifications) layout(at time.Time, width, height float32) (toasts []toast, hidden int) {
+	n.expire(at)
+	w := n.Width
+	if w <= 0 {
+		w = 300
+	}
+	pad := theme.Padding
+	frame := theme.TextFrame()
+	l, t, r, b := frame.Insets()
+	lh := float32(n.Font.lineHeight())
+
+	// distance from the edge of the corner along the stack
+	d := n.Margin
+	for i := len(n.items) - 1; 0 <= i; i-- {
+		it := &n.items[i]
+		if 0 < n.Max && n.Max <= len(toasts) {
+			hidden = i + 1
+			break
+		}
+		lines, th := n.Font.LayoutParagraphs(it.text, int32(w-l-r-2*pad), ParagraphStyle{})
+		if th < int32(lh) {
+			th = int32(lh)
+		}
+		h := float32(th) + t + b + 2*pad
+		if height < d+h+n.Margin {
+			hidden = i + 1
+			break
+		}
+		x := n.Margin
+		if n.Corner == CornerTopRight || n.Corner == CornerBottomRight {
+			x = width - n.Margin - w
+		}
+		// distance of the top edge from the top of the viewport
+		top := d
+		if n.Corner == CornerBottomRight || n.Corner == CornerBottomLeft {
+			top = height - d - h
+		}
+		if origin != OriginTopLeft {
+			top = height - top
+		}
+		alpha := float32(1)
+		if left := it.expires.Sub(at); left < n.Fade {
+			alpha = float32(left) / float32(n.Fade)
+		}
+		toasts = append(toasts, toast{
+			item:  it,
+			box:   Rect{X: x, Y: linePos(top, h), Width: w, Height: h},
+			lines: lines,
+			alpha: alpha,
+		})
+		d += h + n.Spacing
+	}
+	return
+}
+
+// Draw removes expired messages and draws others in the viewport of the
+// size, usually the size given to SetupOrtho.
+func (n *Notifications) Draw(width, height float32) error {
+	toasts, hidden := n.layout(now(n.Clock), width, height)
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	pad := theme.Padding
+	for _, ts := range toasts {
+		frame := theme.textFrame()
+		switch ts.item.severity {
+		case SeverityWarning:
+			frame.Border = theme.color(ColorWarning)
+		case SeverityError:
+			frame.Border = theme.color(ColorError)
+		}
+		frame.Fill = fadeColor(frame.Fill, ts.alpha)
+		frame.Border = fadeColor(frame.Border, ts.alpha)
+		if err := frame.Draw(ts.box); err != nil {
+			return err
+		}
+		l, t, _, _ := frame.Insets()
+		c := theme.color(ColorText)
+		if c == nil {
+			c = color.White
+		}
+		setColor(fadeColor(c, ts.alpha))
+		if err := n.Font.drawLines(ts.box.X+l+pad, below(rectTop(ts.box), t+pad), ts.lines); err != nil {
+			return err
+		}
+	}
+	if hidden == 0 || len(toasts) == 0 {
+		return nil
+	}
+	// amount of hidden messages after the farthest message
+	last := toasts[len(toasts)-1].box
+	text := "+" + strconv.Itoa(hidden)
+	x := last.X + last.Width - float32(n.Font.advanceSize(text))
+	y := below(rectTop(last), last.Height+n.Spacing)
+	if n.Corner == CornerBottomRight || n.Corner == CornerBottomLeft {
+		y = above(rectTop(last), n.Spacing)
+	}
+	return n.Font.Printf(n.Font.startX(x, text), n.Font.lineY(y), text)
+}
+
+// fadeColor returns the color with the alpha multiplied by a, or nil
+// for nil.
+func fadeColor(c color.Color, a float32) color.Color {
+	if c == nil || a == 1 {
+		return c
+	}
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nc.A = uint8(float32(nc.A) * a)
+	return nc
+}
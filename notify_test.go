@@ -0,0 +1,56 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationsLayout(t *testing.T) {
+	n := Notifications{Font: testFont(), Width: 100, Margin: 10, Spacing: 5, Max: 2, Fade: time.Second}
+	n.PushTTL("first", SeverityInfo, time.Minute)
+	n.PushTTL("second", SeverityWarning, time.Minute)
+	n.PushTTL("third", SeverityError, time.Minute)
+	n.PushTTL("expired", SeverityInfo, -time.Second)
+
+	now := time.Now()
+	toasts, hidden := n.layout(now, 400, 300)
+	if n.Len() != 3 || len(toasts) != 2 || hidden != 1 {
+		t.Fatalf("%d messages, %d toasts, %d hidden", n.Len(), len(toasts), hidden)
+	}
+	if toasts[0].item.text != "third" || toasts[1].item.text != "second" {
+		t.Errorf("newest message is not first")
+	}
+	lh := float32(testFont().lineHeight())
+	b0, b1 := toasts[0].box, toasts[1].box
+	if b0.X != 290 || b0.Width != 100 || b0.Height != lh {
+		t.Errorf("box %v", b0)
+	}
+	// bottom-left origin, stacked down from the top right corner
+	if rectTop(b0) != 290 || rectTop(b1) != 290-lh-5 {
+		t.Errorf("boxes %v %v", b0, b1)
+	}
+	if toasts[0].alpha != 1 {
+		t.Errorf("alpha %v", toasts[0].alpha)
+	}
+
+	// fading and wrapping
+	n = Notifications{Font: testFont(), Width: 20, Fade: 2 * time.Second, Corner: CornerBottomLeft}
+	n.PushTTL("long long message", SeverityInfo, time.Second)
+	now = time.Now()
+	toasts, _ = n.layout(now, 400, 300)
+	if len(toasts) != 1 || len(toasts[0].lines) < 2 {
+		t.Fatalf("message is not wrapped %+v", toasts)
+	}
+	if a := toasts[0].alpha; a <= 0 || 0.5 < a {
+		t.Errorf("alpha %v", a)
+	}
+	if b := toasts[0].box; b.X != 0 || b.Y != 0 {
+		t.Errorf("box in the bottom left corner %v", b)
+	}
+
+	// messages higher than the viewport
+	toasts, hidden = n.layout(now, 400, lh)
+	if len(toasts) != 0 || hidden != 1 {
+		t.Errorf("%d toasts, %d hidden", len(toasts), hidden)
+	}
+}
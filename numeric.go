@@ -0,0 +1,323 @@
+package glsymbol
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// numberRange is the range of values of numeric widgets.
+type numberRange struct {
+	Min, Max float64
+	Step     float64 // values are snapped to Min + n*Step if positive
+}
+
+// clamp returns the value limited by the range and snapped to steps.
+func (r *numberRange) clamp(v float64) float64 {
+	if 0 < r.Step {
+		n := math.Round((v - r.Min) / r.Step)
+		if inv := math.Round(1 / r.Step); math.Abs(inv-1/r.Step) < 1e-9 {
+			// decimal steps as 0.1 are exact by division
+			v = r.Min + n/inv
+		} else {
+			v = r.Min + n*r.Step
+		}
+	}
+	if r.Max < v {
+		v = r.Max
+	}
+	if v < r.Min {
+		v = r.Min
+	}
+	return v
+}
+
+// formatNumber formats the value by the function or as strconv does.
+func formatNumber(format func(float64) string, v float64) string {
+	if format != nil {
+		return format(v)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// A SpinBox is a numeric field with buttons which step the value down
+// and up. The value may be typed, it is applied by Enter, while Escape
+// restores the text of the current value. Up and Down keys step it.
+type SpinBox struct {
+	Font *Font
+
+	Min, Max float64
+	Step     float64 // step of buttons and keys, values are snapped to it
+
+	// Format returns the text of the value, as strconv.FormatFloat
+	// with the shortest representation if nil.
+	Format func(v float64) string
+
+	// OnChange is called when the value changes.
+	OnChange func(v float64)
+
+	value float64
+	input *Input
+
+	field, down, up Rect // placed by Layout
+}
+
+// NewSpinBox creates a spin box with the value in the range.
+func NewSpinBox(f *Font, value, min, max, step float64) *SpinBox {
+	s := &SpinBox{Font: f, Min: min, Max: max, Step: step}
+	s.input = NewInput(f, "")
+	s.input.Mask = MaskFloat()
+	s.value = s.rng().clamp(value)
+	s.input.SetText(formatNumber(s.Format, s.value))
+	return s
+}
+
+func (s *SpinBox) rng() *numberRange {
+	return &numberRange{Min: s.Min, Max: s.Max, Step: s.Step}
+}
+
+// Value returns the value.
+func (s *SpinBox) Value() float64 {
+	return s.value
+}
+
+// SetValue sets the value limited by the range and calls OnChange if
+// the value changes.
+func (s *SpinBox) SetValue(v float64) {
+	v = s.rng().clamp(v)
+	s.input.SetText(formatNumber(s.Format, v))
+	if v == s.value {
+		return
+	}
+	s.value = v
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+// Text returns the text of the field, which differs from the value
+// while it is typed.
+func (s *SpinBox) Text() string {
+	return s.input.String()
+}
+
+// HandleKey steps the value by Up and Down, applies typed text by Enter
+// and edits the text by other keys.
+func (s *SpinBox) HandleKey(k Key) bool {
+	switch k {
+	case KeyUp:
+		s.SetValue(s.value + s.Step)
+	case KeyDown:
+		s.SetValue(s.value - s.Step)
+	case KeyEnter:
+		if v, _, err := ParseFloatUnit(s.input.String()); err == nil {
+			s.SetValue(v)
+		} else {
+			s.input.SetText(formatNumber(s.Format, s.value))
+		}
+	case KeyEscape:
+		s.input.SetText(formatNumber(s.Format, s.value))
+	default:
+		return s.input.HandleKey(k)
+	}
+	return true
+}
+
+// HandleChar types the rune into the field, see Input.Insert.
+func (s *SpinBox) HandleChar(r rune) bool {
+	return s.input.HandleChar(r)
+}
+
+// Layout places the spin box of the width with the top left corner at
+// x, y and returns its box. The buttons are on the right of the field.
+func (s *SpinBox) Layout(x, y, width float32) Rect {
+	h := float32(s.Font.lineHeight()) + 2*theme.Padding
+	bw := float32(s.Font.advanceSize("+")) + 2*theme.Padding
+	if bw < h {
+		bw = h
+	}
+	top := linePos(y, h)
+	s.field = Rect{X: x, Y: top, Width: width - 2*bw, Height: h}
+	s.down = Rect{X: x + width - 2*bw, Y: top, Width: bw, Height: h}
+	s.up = Rect{X: x + width - bw, Y: top, Width: bw, Height: h}
+	return Rect{X: x, Y: top, Width: width, Height: h}
+}
+
+// Click steps the value if the point is at a button placed by the last
+// Layout or Draw and reports whether it is.
+func (s *SpinBox) Click(x, y float32) bool {
+	switch {
+	case s.down.Contains(x, y):
+		s.SetValue(s.value - s.Step)
+	case s.up.Contains(x, y):
+		s.SetValue(s.value + s.Step)
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw lays out the spin box as Layout and draws it with the frame and
+// colors of the theme. It returns the box.
+func (s *SpinBox) Draw(x, y, width float32) (Rect, error) {
+	box := s.Layout(x, y, width)
+	frame := theme.TextFrame()
+	for _, r := range []Rect{s.field, s.down, s.up} {
+		if err := frame.Draw(r); err != nil {
+			return box, err
+		}
+	}
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	if c := theme.color(ColorText); c != nil {
+		setColor(c)
+	}
+	p := theme.Padding
+	if err := s.input.Draw(s.field.X+p, s.Font.lineY(below(rectTop(s.field), p))); err != nil {
+		return box, err
+	}
+	for _, b := range []struct {
+		r    Rect
+		text string
+	}{{s.down, "-"}, {s.up, "+"}} {
+		w := s.Font.advanceSize(b.text)
+		bx := b.r.X + (b.r.Width-float32(w))/2 + float32(s.Font.anchor(w))
+		if err := s.Font.Printf(bx, s.Font.lineY(below(rectTop(b.r), p)), b.text); err != nil {
+			return box, err
+		}
+	}
+	return box, nil
+}
+
+// A Slider is a horizontal track with a handle and a label with the
+// value above it. Left and Down keys decrease the value, Right and Up
+// increase it by Step, Home and End set the limits.
+type Slider struct {
+	Font  *Font
+	Label string // shown before the value as "Label: value"
+
+	Min, Max float64
+	Step     float64 // step of keys, values are snapped to it if positive
+
+	// Format returns the text of the value, as strconv.FormatFloat
+	// with the shortest representation if nil.
+	Format func(v float64) string
+
+	// OnChange is called when the value changes.
+	OnChange func(v float64)
+
+	value float64
+	track Rect // placed by Layout
+}
+
+// NewSlider creates a slider with the value in the range.
+func NewSlider(f *Font, label string, value, min, max, step float64) *Slider {
+	s := &Slider{Font: f, Label: label, Min: min, Max: max, Step: step}
+	s.value = s.rng().clamp(value)
+	return s
+}
+
+func (s *Slider) rng() *numberRange {
+	return &numberRange{Min: s.Min, Max: s.Max, Step: s.Step}
+}
+
+// Value returns the value.
+func (s *Slider) Value() float64 {
+	return s.value
+}
+
+// SetValue sets the value limited by the range and calls OnChange if
+// the value changes.
+func (s *Slider) SetValue(v float64) {
+	v = s.rng().clamp(v)
+	if v == s.value {
+		return
+	}
+	s.value = v
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+// Text returns the label with the value.
+func (s *Slider) Text() string {
+	v := formatNumber(s.Format, s.value)
+	if s.Label == "" {
+		return v
+	}
+	return s.Label + ": " + v
+}
+
+// HandleKey changes the value by the key.
+func (s *Slider) HandleKey(k Key) bool {
+	step := s.Step
+	if step <= 0 {
+		step = (s.Max - s.Min) / 100
+	}
+	switch k {
+	case KeyLeft, KeyDown:
+		s.SetValue(s.value - step)
+	case KeyRight, KeyUp:
+		s.SetValue(s.value + step)
+	case KeyHome:
+		s.SetValue(s.Min)
+	case KeyEnd:
+		s.SetValue(s.Max)
+	default:
+		return false
+	}
+	return true
+}
+
+// Layout places the slider of the width with the top left corner at
+// x, y and returns its box: the label and the track below it.
+func (s *Slider) Layout(x, y, width float32) Rect {
+	lh := float32(s.Font.lineHeight())
+	th := lh / 2
+	s.track = Rect{X: x, Y: linePos(below(y, lh), th), Width: width, Height: th}
+	return Rect{X: x, Y: linePos(y, lh+th), Width: width, Height: lh + th}
+}
+
+// Drag sets the value by the X coordinate of the pointer on the track
+// placed by the last Layout or Draw. It reports whether the point is
+// at the track, the value is set for points out of it while dragging.
+func (s *Slider) Drag(x, y float32, dragging bool) bool {
+	if !dragging && !s.track.Contains(x, y) {
+		return false
+	}
+	if s.track.Width <= 0 {
+		return true
+	}
+	t := float64((x - s.track.X) / s.track.Width)
+	s.SetValue(s.Min + t*(s.Max-s.Min))
+	return true
+}
+
+// handle returns the position of the handle on the track.
+func (s *Slider) handle() float32 {
+	if s.Max <= s.Min {
+		return s.track.X
+	}
+	return s.track.X + s.track.Width*float32((s.value-s.Min)/(s.Max-s.Min))
+}
+
+// Draw lays out the slider as Layout and draws it with colors of the
+// theme. It returns the box.
+func (s *Slider) Draw(x, y, width float32) (Rect, error) {
+	box := s.Layout(x, y, width)
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	if c := theme.color(ColorText); c != nil {
+		setColor(c)
+	}
+	text := s.Text()
+	if err := s.Font.Printf(s.Font.startX(x, text), s.Font.lineY(y), text); err != nil {
+		return box, err
+	}
+	t := s.track
+	fillRect(Rect{X: t.X, Y: t.Y + t.Height/3, Width: t.Width, Height: t.Height / 3}, theme.color(ColorBorder))
+	hw := t.Height / 2
+	fillRect(Rect{X: s.handle() - hw/2, Y: t.Y, Width: hw, Height: t.Height}, theme.color(ColorHighlight))
+	return box, checkGLError("Slider.Draw")
+}
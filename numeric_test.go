@@ -0,0 +1,79 @@
+package glsymbol
+
+import "testing"
+
+func TestSpinBox(t *testing.T) {
+	var changes []float64
+	s := NewSpinBox(testFont(), 5, 0, 10, 0.5)
+	s.OnChange = func(v float64) { changes = append(changes, v) }
+
+	s.HandleKey(KeyUp)
+	if s.Value() != 5.5 || s.Text() != "5.5" {
+		t.Errorf("value %v, text %q", s.Value(), s.Text())
+	}
+	s.HandleKey(KeyBackspace)
+	s.HandleKey(KeyBackspace)
+	s.HandleKey(KeyBackspace)
+	for _, r := range "7.3x" {
+		s.HandleChar(r)
+	}
+	if s.Text() != "7.3" || s.Value() != 5.5 {
+		t.Errorf("typed text %q, value %v", s.Text(), s.Value())
+	}
+	s.HandleKey(KeyEnter)
+	if s.Value() != 7.5 || s.Text() != "7.5" {
+		t.Errorf("applied value %v, text %q", s.Value(), s.Text())
+	}
+	s.SetValue(100)
+	if s.Value() != 10 {
+		t.Errorf("value out of range %v", s.Value())
+	}
+
+	box := s.Layout(0, 100, 100)
+	if box.Width != 100 || s.field.Width+s.down.Width+s.up.Width != 100 {
+		t.Errorf("layout %v %v %v %v", box, s.field, s.down, s.up)
+	}
+	if !s.Click(s.down.X+1, s.down.Y+1) || s.Value() != 9.5 {
+		t.Errorf("down button, value %v", s.Value())
+	}
+	if s.Click(s.field.X+1, s.field.Y+1) {
+		t.Errorf("click at the field steps the value")
+	}
+	expect := []float64{5.5, 7.5, 10, 9.5}
+	if len(changes) != len(expect) {
+		t.Fatalf("changes %v", changes)
+	}
+	for i := range expect {
+		if changes[i] != expect[i] {
+			t.Errorf("changes %v, expect %v", changes, expect)
+		}
+	}
+}
+
+func TestSlider(t *testing.T) {
+	s := NewSlider(testFont(), "Gain", 0.5, 0, 1, 0.1)
+	if s.Text() != "Gain: 0.5" {
+		t.Errorf("text %q", s.Text())
+	}
+	s.HandleKey(KeyRight)
+	s.HandleKey(KeyRight)
+	if s.Value() != 0.7 {
+		t.Errorf("value %v", s.Value())
+	}
+	s.HandleKey(KeyEnd)
+	if s.Value() != 1 {
+		t.Errorf("value %v", s.Value())
+	}
+
+	s.Layout(10, 100, 100)
+	tr := s.track
+	if !s.Drag(tr.X+25, tr.Y+1, false) || s.Value() != 0.3 {
+		t.Errorf("drag at the track, value %v", s.Value())
+	}
+	if s.Drag(tr.X+25, tr.Y+100, false) {
+		t.Errorf("press out of the track")
+	}
+	if !s.Drag(tr.X+500, tr.Y+100, true) || s.Value() != 1 {
+		t.Errorf("dragging out of the track, value %v", s.Value())
+	}
+}
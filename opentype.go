@@ -0,0 +1,140 @@
+package glsymbol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// isOpentype reports whether the font data holds an OpenType font with
+// CFF outlines, which the truetype package can not parse.
+func isOpentype(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("OTTO"))
+}
+
+// LoadTruetypeCollection loads the font with the index faceIndex from
+// a TrueType or OpenType collection, usually a .ttc file, as LoadTruetype
+// loads a single font. Collections are common for CJK system fonts,
+// which hold a few faces sharing glyphs. Single font files are treated
+// as collections of one font.
+func LoadTruetypeCollection(r io.Reader, faceIndex int, scale int32, low, high rune) (*Font, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	c, err := opentype.ParseCollection(data)
+	if err != nil {
+		return nil, err
+	}
+	if faceIndex < 0 || c.NumFonts() <= faceIndex {
+		return nil, fmt.Errorf("glsymbol: face index %d is out of collection of %d fonts",
+			faceIndex, c.NumFonts())
+	}
+	otf, err := c.Font(faceIndex)
+	if err != nil {
+		return nil, err
+	}
+	return loadOpentype(otf, scale, []RuneRange{{low, high}})
+}
+
+// loadOpentype rasterizes glyphs of the parsed OpenType font.
+func loadOpentype(otf *opentype.Font, scale int32, ranges []RuneRange) (*Font, error) {
+	img, fc, err := rasterizeOpentype(context.Background(), otf, scale, ranges, 0)
+	if err != nil {
+		return nil, err
+	}
+	f, err := loadFont(img, fc)
+	if err != nil {
+		return nil, err
+	}
+	f.size = scale
+	return f, nil
+}
+
+// rasterizeOpentype draws glyphs of the font into a sprite sheet with
+// cells packed by shelfPacker, pad blank pixels around them. It stops
+// with the error of the context once it is done.
+func rasterizeOpentype(ctx context.Context, otf *opentype.Font, scale int32, ranges []RuneRange, pad int) (*image.RGBA, *FontConfig, error) {
+	fc, err := newRangeConfig(ranges)
+	if err != nil {
+		return nil, nil, err
+	}
+	sp := startSpan(StageRasterize)
+	defer sp.end()
+
+	face, err := opentype.NewFace(otf, &opentype.FaceOptions{
+		Size:    float64(scale),
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer face.Close()
+
+	// bounds have Y going down, from the baseline
+	var buf sfnt.Buffer
+	b, err := otf.Bounds(&buf, fixed.I(int(scale)), font.HintingNone)
+	if err != nil {
+		return nil, nil, err
+	}
+	ascent, descent := -b.Min.Y.Floor(), b.Max.Y.Ceil()
+	gh := ascent + descent + 2 // a row of space above and below glyphs
+
+	// glyphs of a cell are in rows Y+1..Y+Height, see loadFont
+	fc.Baseline = int32(gh - 1 - ascent)
+
+	// bitmaps hold the ink of glyphs, see rasterizeTruetype
+	pen := 2 + ascent
+	left := make([]int, len(fc.Glyphs))
+	widths := make([]int, len(fc.Glyphs))
+	heights := make([]int, len(fc.Glyphs))
+	tops := make([]int, len(fc.Glyphs))
+	for i := range fc.Glyphs {
+		heights[i] = gh
+		if gb, _, ok := face.GlyphBounds(fc.RuneAt(i)); ok && gb.Min.X < gb.Max.X {
+			left[i] = gb.Min.X.Floor()
+			widths[i] = gb.Max.X.Ceil() - left[i]
+			tops[i], heights[i] = tightRows(pen+gb.Min.Y.Floor(), pen+gb.Max.Y.Ceil(), gh)
+		}
+	}
+	pos, size, err := packCells(widths, heights, pad)
+	if err != nil {
+		return nil, nil, err
+	}
+	img := image.NewRGBA(image.Rectangle{Max: size})
+
+	d := font.Drawer{Dst: img, Src: image.White, Face: face}
+	for i := range fc.Glyphs {
+		if i%16 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+		}
+		r := fc.RuneAt(i)
+		at := pos[i]
+		adv, _ := face.GlyphAdvance(r)
+		advance := adv.Round()
+		fc.Glyphs[i] = Glyph{
+			X: int32(at.X), Y: int32(at.Y),
+			Width: int32(widths[i]), Height: int32(heights[i]),
+			Advance:       int32(advance),
+			LeftBearing:   int32(left[i]),
+			RightBearing:  int32(advance - left[i] - widths[i]),
+			TopBearing:    int32(tops[i]),
+			BottomBearing: int32(gh - tops[i] - heights[i]),
+		}
+		if widths[i] != 0 {
+			d.Dot = fixed.P(at.X-left[i], at.Y+pen-tops[i])
+			d.DrawString(string(r))
+		}
+	}
+	return img, fc, nil
+}
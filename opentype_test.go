@@ -0,0 +1,70 @@
+package glsymbol
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestRasterizeOpentype(t *testing.T) {
+	data := goregular.TTF
+	if isOpentype(data) {
+		t.Errorf("truetype font is detected as OpenType")
+	}
+	if !isOpentype([]byte("OTTO\x00\x0a")) {
+		t.Errorf("OpenType font is not detected")
+	}
+	otf, err := opentype.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, config, err := rasterizeOpentype(context.Background(), otf, 16, []RuneRange{{32, 127}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(img.Bounds()); err != nil {
+		t.Fatal(err)
+	}
+	if config.Baseline <= 0 || config.Glyphs[0].cell() <= config.Baseline {
+		t.Errorf("baseline %d", config.Baseline)
+	}
+	// glyph 'I' has ink above the baseline only
+	g := config.Glyphs['I'-32]
+	if g.Advance <= 0 || g.step() != g.Advance {
+		t.Errorf("glyph %+v", g)
+	}
+	ink := func(y int32) bool {
+		for x := g.X; x < g.X+g.Width; x++ {
+			if _, _, _, a := img.At(int(x), int(y)).RGBA(); a != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	bottom := g.Y + g.Height + g.BottomBearing // last row of the cell
+	if !ink(bottom - config.Baseline) {
+		t.Errorf("no ink on the row above the baseline")
+	}
+	if ink(bottom - config.Baseline + 1) {
+		t.Errorf("ink below the baseline")
+	}
+	if _, _, err := rasterizeOpentype(context.Background(), otf, 16, []RuneRange{{'b', 'a'}}, 0); err == nil {
+		t.Errorf("no error for reversed range")
+	}
+}
+
+func TestLoadTruetypeCollectionIndex(t *testing.T) {
+	for _, index := range []int{-1, 1} {
+		_, err := LoadTruetypeCollection(bytes.NewReader(goregular.TTF), index, 16, 32, 127)
+		if err == nil || !strings.Contains(err.Error(), "out of collection of 1 fonts") {
+			t.Errorf("index %d: error %v", index, err)
+		}
+	}
+	if _, err := LoadTruetypeCollection(strings.NewReader("ttcf"), 0, 16, 32, 127); err == nil {
+		t.Errorf("no error for invalid collection")
+	}
+}
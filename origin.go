@@ -0,0 +1,59 @@
+package glsymbol
+
+// Origin defines the corner of the window where the coordinate system
+// of drawing functions starts.
+type Origin int
+
+// Supported origins.
+const (
+	// OriginBottomLeft is the OpenGL convention: Y axis goes up and
+	// text is placed by the bottom left corner of its glyph cells.
+	OriginBottomLeft Origin = iota
+
+	// OriginTopLeft is the convention of most UI toolkits: Y axis goes
+	// down and text is placed by the top left corner of its glyph cells.
+	OriginTopLeft
+)
+
+// origin is the coordinate system used by the package.
+var origin = OriginBottomLeft
+
+// SetOrigin selects the coordinate system of drawing functions and of
+// the projection made by SetupOrtho. The default is OriginBottomLeft.
+//
+// Glyphs are always drawn upright, so text is neither mirrored nor
+// upside-down with a flipped projection.
+func SetOrigin(o Origin) {
+	origin = o
+}
+
+// below returns the coordinate distance h below y.
+func below(y, h float32) float32 {
+	if origin == OriginTopLeft {
+		return y + h
+	}
+	return y - h
+}
+
+// above returns the coordinate distance h above y.
+func above(y, h float32) float32 {
+	return below(y, -h)
+}
+
+// linePos returns the coordinate to draw a line of the given height,
+// whose top edge is at y.
+func linePos(top, height float32) float32 {
+	if origin == OriginTopLeft {
+		return top
+	}
+	return top - height
+}
+
+// cellBottom returns the bottom coordinate of a glyph cell of
+// the given height placed at y.
+func cellBottom(y, height float32) float32 {
+	if origin == OriginTopLeft {
+		return y + height
+	}
+	return y
+}
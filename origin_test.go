@@ -0,0 +1,16 @@
+package glsymbol
+
+import "testing"
+
+func TestOrigin(t *testing.T) {
+	defer SetOrigin(OriginBottomLeft)
+
+	SetOrigin(OriginBottomLeft)
+	if below(100, 10) != 90 || linePos(100, 10) != 90 || cellBottom(100, 10) != 100 {
+		t.Errorf("unexpected bottom left coordinates")
+	}
+	SetOrigin(OriginTopLeft)
+	if below(100, 10) != 110 || linePos(100, 10) != 100 || cellBottom(100, 10) != 110 {
+		t.Errorf("unexpected top left coordinates")
+	}
+}
@@ -0,0 +1,260 @@
+package glsymbol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"runtime"
+)
+
+// Parsing of OpenType layout tables, GSUB and GPOS. Only lookups which
+// map glyphs without context are read: single and ligature substitutions
+// and pair positioning. Offsets out of tables panic with runtime errors,
+// which otlParse reports as malformed tables.
+
+// otlParse calls parse and turns panics of reads out of the table into
+// an error.
+func otlParse(tag string, parse func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(runtime.Error); !ok {
+				panic(r)
+			}
+			err = fmt.Errorf("glsymbol: %s table is malformed", tag)
+		}
+	}()
+	parse()
+	return nil
+}
+
+// u16 returns the big endian value at the offset of the data.
+func u16(b []byte, off int) int {
+	return int(binary.BigEndian.Uint16(b[off:]))
+}
+
+// otlFeatures returns subtables of lookups of the type by feature tags
+// of the GSUB or GPOS table, in lookup order. Features of default
+// language systems of all scripts are read. Extension lookups of
+// extType are resolved to the subtables they point to.
+func otlFeatures(table []byte, typ, extType int) map[string][][]byte {
+	scripts := table[u16(table, 4):]
+	features := table[u16(table, 6):]
+	lookups := table[u16(table, 8):]
+
+	// features of default language systems
+	used := make(map[int]bool)
+	for i, n := 0, u16(scripts, 0); i < n; i++ {
+		script := scripts[u16(scripts, 2+6*i+4):]
+		if off := u16(script, 0); off != 0 {
+			lang := script[off:]
+			if req := u16(lang, 2); req != 0xffff {
+				used[req] = true
+			}
+			for j, m := 0, u16(lang, 4); j < m; j++ {
+				used[u16(lang, 6+2*j)] = true
+			}
+		}
+	}
+
+	out := make(map[string][][]byte)
+	for i, n := 0, u16(features, 0); i < n; i++ {
+		if !used[i] {
+			continue
+		}
+		rec := features[2+6*i:]
+		tag := string(rec[:4])
+		feature := features[u16(rec, 4):]
+		for j, m := 0, u16(feature, 2); j < m; j++ {
+			lookup := lookups[u16(lookups, 2+2*u16(feature, 4+2*j)):]
+			t := u16(lookup, 0)
+			for k, l := 0, u16(lookup, 4); k < l; k++ {
+				sub := lookup[u16(lookup, 6+2*k):]
+				st := t
+				if t == extType {
+					st = u16(sub, 2)
+					sub = sub[binary.BigEndian.Uint32(sub[4:]):]
+				}
+				if st == typ {
+					out[tag] = append(out[tag], sub)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// otlCoverage returns glyphs of the coverage table by coverage indexes.
+func otlCoverage(b []byte) []uint16 {
+	var glyphs []uint16
+	switch u16(b, 0) {
+	case 1:
+		for i, n := 0, u16(b, 2); i < n; i++ {
+			glyphs = append(glyphs, uint16(u16(b, 4+2*i)))
+		}
+	case 2:
+		for i, n := 0, u16(b, 2); i < n; i++ {
+			rec := b[4+6*i:]
+			start, end, index := u16(rec, 0), u16(rec, 2), u16(rec, 4)
+			for g := start; g <= end; g++ {
+				for len(glyphs) <= index+g-start {
+					glyphs = append(glyphs, 0)
+				}
+				glyphs[index+g-start] = uint16(g)
+			}
+		}
+	}
+	return glyphs
+}
+
+// otlClasses returns classes of glyphs of the class definition table.
+// Glyphs not listed are of class zero.
+func otlClasses(b []byte) map[uint16]int {
+	classes := make(map[uint16]int)
+	switch u16(b, 0) {
+	case 1:
+		start := u16(b, 2)
+		for i, n := 0, u16(b, 4); i < n; i++ {
+			classes[uint16(start+i)] = u16(b, 6+2*i)
+		}
+	case 2:
+		for i, n := 0, u16(b, 2); i < n; i++ {
+			rec := b[4+6*i:]
+			for g := u16(rec, 0); g <= u16(rec, 2); g++ {
+				classes[uint16(g)] = u16(rec, 4)
+			}
+		}
+	}
+	return classes
+}
+
+// singleSubstitutions returns glyphs substituting glyphs by features of
+// the GSUB table, read from lookups of single substitutions.
+func singleSubstitutions(gsub []byte) (map[string]map[uint16]uint16, error) {
+	out := make(map[string]map[uint16]uint16)
+	err := otlParse("GSUB", func() {
+		for tag, subtables := range otlFeatures(gsub, 1, 7) {
+			m := make(map[uint16]uint16)
+			// the first lookup substituting a glyph wins
+			for _, sub := range subtables {
+				cov := otlCoverage(sub[u16(sub, 2):])
+				for i, g := range cov {
+					if _, ok := m[g]; ok {
+						continue
+					}
+					switch u16(sub, 0) {
+					case 1:
+						m[g] = g + uint16(u16(sub, 4))
+					case 2:
+						m[g] = uint16(u16(sub, 6+2*i))
+					}
+				}
+			}
+			out[tag] = m
+		}
+	})
+	return out, err
+}
+
+// valueSize returns the size of a value record of the format.
+func valueSize(format int) int {
+	n := 0
+	for f := format & 0xff; f != 0; f &= f - 1 {
+		n += 2
+	}
+	return n
+}
+
+// xAdvance returns the horizontal advance adjustment of the value record
+// of the format, zero if the format has none.
+func xAdvance(rec []byte, format int) int {
+	if format&0x4 == 0 {
+		return 0
+	}
+	return int(int16(u16(rec, valueSize(format&0x3))))
+}
+
+// pairAdjustments returns horizontal advance adjustments of the first
+// glyphs of pairs of glyphs of the kern feature of the GPOS table, in
+// font units, the first subtable with a pair wins. Only glyphs for
+// which keep returns true are read.
+func pairAdjustments(gpos []byte, keep func(g uint16) bool) (map[[2]uint16]int, error) {
+	out := make(map[[2]uint16]int)
+	err := otlParse("GPOS", func() {
+		for _, sub := range otlFeatures(gpos, 2, 9)["kern"] {
+			f1, f2 := u16(sub, 4), u16(sub, 6)
+			s1, s2 := valueSize(f1), valueSize(f2)
+			cov := otlCoverage(sub[u16(sub, 2):])
+			switch u16(sub, 0) {
+			case 1:
+				for i, first := range cov {
+					if !keep(first) {
+						continue
+					}
+					set := sub[u16(sub, 10+2*i):]
+					for j, n := 0, u16(set, 0); j < n; j++ {
+						rec := set[2+j*(2+s1+s2):]
+						pair := [2]uint16{first, uint16(u16(rec, 0))}
+						if _, ok := out[pair]; !ok && keep(pair[1]) {
+							out[pair] = xAdvance(rec[2:], f1)
+						}
+					}
+				}
+			case 2:
+				classes1 := otlClasses(sub[u16(sub, 8):])
+				classes2 := otlClasses(sub[u16(sub, 10):])
+				n2 := u16(sub, 14)
+				var seconds []uint16
+				for g := range classes2 {
+					if keep(g) {
+						seconds = append(seconds, g)
+					}
+				}
+				for _, first := range cov {
+					if !keep(first) {
+						continue
+					}
+					row := sub[16+classes1[first]*n2*(s1+s2):]
+					for _, second := range seconds {
+						pair := [2]uint16{first, second}
+						if _, ok := out[pair]; !ok {
+							out[pair] = xAdvance(row[classes2[second]*(s1+s2):], f1)
+						}
+					}
+				}
+			}
+		}
+	})
+	return out, err
+}
+
+// otlLigature is a glyph substituting a sequence of glyphs.
+type otlLigature struct {
+	glyphs []uint16 // substituted glyphs
+	glyph  uint16   // the ligature glyph
+}
+
+// ligatureSubstitutions returns ligatures of the liga feature of the GSUB
+// table, read from lookups of ligature substitutions, in the order of
+// preference of the font: the first ligature matching glyphs wins.
+func ligatureSubstitutions(gsub []byte) ([]otlLigature, error) {
+	var out []otlLigature
+	err := otlParse("GSUB", func() {
+		for _, sub := range otlFeatures(gsub, 4, 7)["liga"] {
+			if u16(sub, 0) != 1 {
+				continue
+			}
+			cov := otlCoverage(sub[u16(sub, 2):])
+			for i, n := 0, u16(sub, 4); i < n && i < len(cov); i++ {
+				set := sub[u16(sub, 6+2*i):]
+				for j, m := 0, u16(set, 0); j < m; j++ {
+					liga := set[u16(set, 2+2*j):]
+					glyphs := []uint16{cov[i]}
+					for k, c := 1, u16(liga, 2); k < c; k++ {
+						glyphs = append(glyphs, uint16(u16(liga, 2+2*k)))
+					}
+					out = append(out, otlLigature{glyphs: glyphs, glyph: uint16(u16(liga, 0))})
+				}
+			}
+		}
+	})
+	return out, err
+}
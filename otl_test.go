@@ -0,0 +1,135 @@
+package glsymbol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// words appends 16-bit values to b.
+func words(b []byte, vs ...int) []byte {
+	for _, v := range vs {
+		b = append(b, byte(v>>8), byte(v))
+	}
+	return b
+}
+
+// otlFeature is a feature of a test layout table with a single lookup
+// of a single subtable.
+type otlFeature struct {
+	tag      string
+	typ      int
+	subtable []byte
+}
+
+// otlTable returns a GSUB or GPOS table of the features of the default
+// script.
+func otlTable(features []otlFeature) []byte {
+	n := len(features)
+	scripts := words(nil, 1)
+	scripts = append(scripts, "DFLT"...)
+	scripts = words(scripts, 8, 4, 0, 0, 0xffff, n)
+	for i := range features {
+		scripts = words(scripts, i)
+	}
+	list := words(nil, n)
+	var tables []byte
+	for i, f := range features {
+		list = append(list, f.tag...)
+		list = words(list, 2+6*n+len(tables))
+		tables = words(tables, 0, 1, i)
+	}
+	list = append(list, tables...)
+	lookups := words(nil, n)
+	tables = nil
+	for _, f := range features {
+		lookups = words(lookups, 2+2*n+len(tables))
+		tables = words(tables, f.typ, 0, 1, 8)
+		tables = append(tables, f.subtable...)
+	}
+	lookups = append(lookups, tables...)
+
+	t := words(nil, 1, 0, 10, 10+len(scripts), 10+len(scripts)+len(list))
+	t = append(t, scripts...)
+	t = append(t, list...)
+	return append(t, lookups...)
+}
+
+func TestSingleSubstitutions(t *testing.T) {
+	gsub := otlTable([]otlFeature{
+		// format 2: 10 -> 20
+		{"smcp", 1, words(nil, 2, 8, 1, 20, 1, 1, 10)},
+		// format 1: 11..12 -> +5
+		{"zero", 1, words(nil, 1, 6, 5, 2, 1, 11, 12, 0)},
+	})
+	m, err := singleSubstitutions(gsub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m["smcp"][10] != 20 || m["zero"][11] != 16 || m["zero"][12] != 17 || len(m["zero"]) != 2 {
+		t.Errorf("substitutions %v", m)
+	}
+	if _, err := singleSubstitutions(gsub[:20]); err == nil {
+		t.Errorf("no error for truncated table")
+	}
+}
+
+func TestPairAdjustments(t *testing.T) {
+	gpos := otlTable([]otlFeature{
+		// format 1: 1, 2 -> -30
+		{"kern", 2, words(nil, 1, 18, 4, 0, 1, 12, 1, 2, -30, 1, 1, 1)},
+		// format 2: class 1 of 3, class 1 of 4..5 -> -40
+		{"kern", 2, words(nil, 2, 24, 4, 0, 30, 38, 2, 2, 0, 0, 0, -40,
+			1, 1, 3, // coverage
+			1, 3, 1, 1, // classes of first glyphs
+			2, 1, 4, 5, 1)}, // classes of second glyphs
+	})
+	m, err := pairAdjustments(gpos, func(g uint16) bool { return g != 5 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m[[2]uint16{1, 2}] != -30 || m[[2]uint16{3, 4}] != -40 {
+		t.Errorf("adjustments %v", m)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := int(ttf.Index('a')), int(ttf.Index('A'))
+	v, w := int(ttf.Index('V')), int(ttf.Index('W'))
+	data := withTable(goregular.TTF, "GSUB", otlTable([]otlFeature{
+		{"smcp", 1, words(nil, 2, 8, 1, b, 1, 1, a)},
+	}))
+	data = withTable(data, "GPOS", otlTable([]otlFeature{
+		{"kern", 2, words(nil, 1, 18, 4, 0, 1, 12, 1, w, -int(ttf.FUnitsPerEm())/8, 1, 1, v)},
+	}))
+
+	img, fc, err := rasterizeData(context.Background(), data, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Kerning) != 1 || fc.Kerning[0] != (KernPair{'V', 'W', -2}) {
+		t.Errorf("pairs %v", fc.Kerning)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.subst, err = newSubstitutions(data, 16, fc); err != nil {
+		t.Fatal(err)
+	}
+	plain := f.advanceSize("ab")
+	f.Features = []string{"smcp"}
+	g := f.glyph('a')
+	if g == &fc.Glyphs[fc.Index('a')] || g.Advance != fc.Glyphs[fc.Index('A')].Advance || len(g.BitmapData) == 0 {
+		t.Errorf("glyph of 'a' is not substituted: %+v", g)
+	}
+	if f.glyph('a') != g {
+		t.Errorf("substitute is rasterized again")
+	}
+	if w := f.advanceSize("ab"); w != plain-fc.Glyphs[fc.Index('a')].step()+g.step() {
+		t.Errorf("width %d, without features %d", w, plain)
+	}
+}
@@ -0,0 +1,119 @@
+package glsymbol
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// An Overlay renders the text layer into a texture instead of the
+// window, for compositing over video streams or by engines which
+// post-process the user interface in a separate pass. The texture keeps
+// alpha: it is cleared to transparent, and text and frames drawn between
+// Begin and End accumulate coverage in the alpha channel, so the result
+// is a premultiplied layer for blending by ONE, ONE_MINUS_SRC_ALPHA.
+type Overlay struct {
+	Width, Height int
+
+	texture uint32
+	fbo     uint32
+	own     bool // the texture is created by the overlay
+
+	// state restored by End
+	prevFBO      int32
+	prevViewport [4]int32
+	prevDisplay  struct {
+		known         bool
+		width, height int
+	}
+}
+
+// NewOverlay creates an overlay of the size rendering into the texture,
+// which must be an RGBA texture of the size. If texture is zero, the
+// overlay creates and owns one.
+func NewOverlay(width, height int, texture uint32) (*Overlay, error) {
+	o := &Overlay{Width: width, Height: height, texture: texture}
+	if texture == 0 {
+		gl.GenTextures(1, &o.texture)
+		gl.BindTexture(gl.TEXTURE_2D, o.texture)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0,
+			gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		o.own = true
+	}
+	var prev int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &prev)
+	gl.GenFramebuffers(1, &o.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, o.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, o.texture, 0)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+	if err := checkGLError("NewOverlay"); err != nil {
+		o.Release()
+		return nil, err
+	}
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		o.Release()
+		return nil, fmt.Errorf("glsymbol: overlay framebuffer is incomplete: 0x%x", status)
+	}
+	return o, nil
+}
+
+// Texture returns the texture of the overlay.
+func (o *Overlay) Texture() uint32 {
+	return o.texture
+}
+
+// Begin binds the overlay, clears it to transparent and sets up the
+// projection of SetupOrtho for its size. Drawing goes to the texture
+// until End.
+func (o *Overlay) Begin() error {
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &o.prevFBO)
+	gl.GetIntegerv(gl.VIEWPORT, &o.prevViewport[0])
+	o.prevDisplay = display
+	gl.PushAttrib(gl.COLOR_BUFFER_BIT | gl.ENABLE_BIT | gl.CURRENT_BIT)
+	gl.MatrixMode(gl.PROJECTION)
+	gl.PushMatrix()
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.PushMatrix()
+	gl.LoadIdentity()
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, o.fbo)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	SetupOrtho(o.Width, o.Height)
+	// colors are blended by alpha, while alpha accumulates coverage
+	gl.Enable(gl.BLEND)
+	gl.BlendFuncSeparate(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	return checkGLError("Overlay.Begin")
+}
+
+// End restores the framebuffer, the viewport and the matrices bound
+// before Begin.
+func (o *Overlay) End() error {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(o.prevFBO))
+	gl.MatrixMode(gl.PROJECTION)
+	gl.PopMatrix()
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.PopMatrix()
+	gl.PopAttrib()
+	vp := o.prevViewport
+	gl.Viewport(vp[0], vp[1], vp[2], vp[3])
+	display = o.prevDisplay
+	return checkGLError("Overlay.End")
+}
+
+// Release deletes the framebuffer and the texture owned by the overlay.
+func (o *Overlay) Release() {
+	if o.fbo != 0 {
+		gl.DeleteFramebuffers(1, &o.fbo)
+		o.fbo = 0
+	}
+	if o.own && o.texture != 0 {
+		gl.DeleteTextures(1, &o.texture)
+	}
+	o.texture = 0
+}
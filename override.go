@@ -0,0 +1,70 @@
+package glsymbol
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetAdvance overrides the distance the pen moves over the glyph of the
+// rune, for example to widen the space or to make a control rune zero
+// width. The glyph is drawn where it is drawn without the override,
+// only glyphs after it move. Overrides apply to drawing and measuring
+// alike and do not change the sprite sheet.
+func (f *Font) SetAdvance(r rune, advance int32) error {
+	if advance < 0 {
+		return fmt.Errorf("glsymbol: negative advance %d of glyph %q", advance, r)
+	}
+	if f.advances == nil {
+		f.advances = make(map[rune]int32)
+	}
+	f.advances[r] = advance
+	return nil
+}
+
+// ResetAdvance removes the override of the advance of the rune, see
+// SetAdvance.
+func (f *Font) ResetAdvance(r rune) {
+	delete(f.advances, r)
+}
+
+// SetRuneData attaches data of the caller to the rune, which is passed
+// to callbacks of EachGlyph by PlacedGlyph.Data, for example a link
+// target or a tooltip of an icon glyph. Nil data removes it.
+func (f *Font) SetRuneData(r rune, data interface{}) {
+	if data == nil {
+		delete(f.data, r)
+		return
+	}
+	if f.data == nil {
+		f.data = make(map[rune]interface{})
+	}
+	f.data[r] = data
+}
+
+// RuneData returns data attached to the rune by SetRuneData, or nil.
+func (f *Font) RuneData(r rune) interface{} {
+	return f.data[r]
+}
+
+// step returns the distance the pen moves over the glyph of the rune,
+// which is the step of the glyph unless it is overridden by SetAdvance.
+func (f *Font) step(r rune, g *Glyph) int32 {
+	if a, ok := f.advances[r]; ok {
+		return a
+	}
+	return g.step()
+}
+
+// hashAdvances writes overrides of advances in the order of runes.
+func (f *Font) hashAdvances(w *hashWriter) {
+	rs := make([]rune, 0, len(f.advances))
+	for r := range f.advances {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i] < rs[j] })
+	w.uint64(uint64(len(rs)))
+	for _, r := range rs {
+		w.int32(r)
+		w.int32(f.advances[r])
+	}
+}
@@ -0,0 +1,56 @@
+package glsymbol
+
+import "testing"
+
+func TestSetAdvance(t *testing.T) {
+	f := testFont()
+	plain := f.advanceSize("a b")
+	h := f.TextHash("a b", nil)
+	if err := f.SetAdvance(' ', 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetAdvance('\u200b', 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetAdvance('x', -1); err == nil {
+		t.Errorf("negative advance is accepted")
+	}
+	if w := f.advanceSize("a b"); w != plain-4+20 {
+		t.Errorf("width %d, expect %d", w, plain-4+20)
+	}
+	if w := f.MetricsBatch([]string{"a b"})[0].X; w != int(plain-4+20) {
+		t.Errorf("batch width %d", w)
+	}
+	if f.TextHash("a b", nil) == h {
+		t.Errorf("hash does not change")
+	}
+	var xs []int32
+	f.EachGlyph("a b", func(g PlacedGlyph) bool {
+		xs = append(xs, g.X)
+		return true
+	})
+	if xs[2] != 28 {
+		t.Errorf("glyphs are placed at %v", xs)
+	}
+	f.ResetAdvance(' ')
+	if w := f.advanceSize("a b"); w != plain {
+		t.Errorf("width %d after reset, expect %d", w, plain)
+	}
+}
+
+func TestRuneData(t *testing.T) {
+	f := testFont()
+	f.SetRuneData('b', "link")
+	var data []interface{}
+	f.EachGlyph("ab", func(g PlacedGlyph) bool {
+		data = append(data, g.Data)
+		return true
+	})
+	if data[0] != nil || data[1] != "link" {
+		t.Errorf("data %v", data)
+	}
+	f.SetRuneData('b', nil)
+	if f.RuneData('b') != nil {
+		t.Errorf("data is not removed")
+	}
+}
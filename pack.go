@@ -0,0 +1,131 @@
+package glsymbol
+
+import (
+	"image"
+	"sort"
+)
+
+// shelfPacker places glyph cells into rows of a sprite sheet, left to
+// right, every row below the previous one and as high as its highest
+// cell. Cells are placed from the highest down, so rows hold cells of
+// close heights and are filled with little waste, close to what skyline
+// or MaxRects packers achieve for rectangles of any size.
+//
+// Every cell has a blank row above it, see loadFont, and a blank column
+// on the right, so linear filtering of the sheet texture does not mix
+// neighbour glyphs. Sheets sampled from smaller mipmap levels need more
+// space, so cells may be padded by blank pixels on every side.
+type shelfPacker struct {
+	width int         // width of the sheet
+	pad   int         // blank pixels around cells
+	at    image.Point // free place in the current row
+	row   int         // height of the current row
+	used  image.Point // size of the used part of the sheet
+}
+
+// maxPageWidth is the largest width of packed sheets. Textures of this
+// size are supported by OpenGL implementations of today, higher sheets
+// are split into texture pages, see Font.sheetPages.
+const maxPageWidth = 4096
+
+// packCells places cells of the widths and the heights padded by pad
+// pixels into the smallest sprite sheet with power-of-two dimensions.
+// Rows of every power-of-two width from the widest cell up to
+// maxPageWidth are tried, of sheets of the same area the squarest one is
+// taken. Higher sheets are split into texture pages by the limit of
+// the OpenGL implementation once the font is uploaded.
+func packCells(widths, heights []int, pad int) ([]image.Point, image.Point, error) {
+	widest := 1
+	for _, w := range widths {
+		if widest < w+1+2*pad {
+			widest = w + 1 + 2*pad
+		}
+	}
+	var (
+		best []image.Point
+		size image.Point
+		err  error
+	)
+	for width := int(Pow2U64(uint64(widest))); ; width *= 2 {
+		p := &shelfPacker{width: width, pad: pad}
+		pos, sz, e := p.layout(widths, heights)
+		if e != nil {
+			err = e
+		} else if best == nil || smallerSheet(sz, size) {
+			best, size = pos, sz
+		}
+		// wider rows do not change a single row
+		if maxPageWidth <= width || p.at.Y == 0 {
+			break
+		}
+	}
+	if best == nil {
+		return nil, image.Point{}, err
+	}
+	return best, size, nil
+}
+
+// smallerSheet reports whether the sheet of the size a takes less memory
+// than b, or is squarer if they take the same.
+func smallerSheet(a, b image.Point) bool {
+	if aa, ab := a.X*a.Y, b.X*b.Y; aa != ab {
+		return aa < ab
+	}
+	return abs(int32(a.X-a.Y)) < abs(int32(b.X-b.Y))
+}
+
+// place returns the position of the cell of the width and the height,
+// which is not higher than cells placed before.
+func (p *shelfPacker) place(w, h int) image.Point {
+	w += 1 + 2*p.pad
+	h += 1 + 2*p.pad
+	if p.width < p.at.X+w && 0 < p.at.X {
+		p.at = image.Pt(0, p.at.Y+p.row)
+		p.row = 0
+	}
+	if p.row < h {
+		p.row = h
+	}
+	at := p.at.Add(image.Pt(p.pad, p.pad))
+	p.at.X += w
+	if p.used.X < p.at.X {
+		p.used.X = p.at.X
+	}
+	p.used.Y = p.at.Y + p.row
+	return at
+}
+
+// layout places cells of the widths and the heights and returns their
+// positions and the power-of-two size of the sheet, or an error if it is
+// too large.
+func (p *shelfPacker) layout(widths, heights []int) ([]image.Point, image.Point, error) {
+	order := make([]int, len(widths))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return heights[order[b]] < heights[order[a]]
+	})
+	pos := make([]image.Point, len(widths))
+	for _, i := range order {
+		pos[i] = p.place(widths[i], heights[i])
+	}
+	w, h, err := sheetSize(int64(p.used.X), int64(p.used.Y))
+	return pos, image.Pt(w, h), err
+}
+
+// tightRows returns the top bearing and the height of the bitmap of ink in rows top..bottom-1 of a glyph cell of the
+// height, with rows counted from the blank row above the cell. Glyphs
+// without ink take the whole cell.
+func tightRows(top, bottom, height int) (int, int) {
+	if top < 1 {
+		top = 1
+	}
+	if height+1 < bottom {
+		bottom = height + 1
+	}
+	if bottom <= top {
+		return 0, height
+	}
+	return top - 1, bottom - top
+}
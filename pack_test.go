@@ -0,0 +1,189 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestShelfPacker(t *testing.T) {
+	widths := []int{5, 0, 7, 3, 9, 9, 2}
+	heights := []int{10, 10, 10, 10, 10, 10, 10}
+	p := &shelfPacker{width: 16}
+	pos, size, err := p.layout(widths, heights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []image.Point{{0, 0}, {6, 0}, {7, 0}, {0, 11}, {4, 11}, {0, 22}, {10, 22}}
+	for i := range pos {
+		if pos[i] != expect[i] {
+			t.Errorf("cell %d at %v, expect %v", i, pos[i], expect[i])
+		}
+	}
+	if size != image.Pt(16, 64) {
+		t.Errorf("sheet size %v", size)
+	}
+}
+
+func TestShelfPackerHeights(t *testing.T) {
+	widths := []int{4, 4, 4, 4}
+	heights := []int{3, 9, 5, 9}
+	p := &shelfPacker{width: 10}
+	pos, _, err := p.layout(widths, heights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the highest cells share the first row
+	expect := []image.Point{{5, 10}, {0, 0}, {0, 10}, {5, 0}}
+	for i := range pos {
+		if pos[i] != expect[i] {
+			t.Errorf("cell %d at %v, expect %v", i, pos[i], expect[i])
+		}
+	}
+	if p.used != image.Pt(10, 16) {
+		t.Errorf("used %v", p.used)
+	}
+}
+
+func TestPackCells(t *testing.T) {
+	for _, tc := range []struct {
+		n, w, h int
+		expect  image.Point
+	}{
+		{64, 7, 7, image.Pt(64, 64)},
+		{1, 200, 10, image.Pt(256, 16)},
+		{8, 7, 63, image.Pt(64, 64)},
+		{128, 7, 7, image.Pt(64, 128)},
+	} {
+		widths, heights := make([]int, tc.n), make([]int, tc.n)
+		for i := range widths {
+			widths[i], heights[i] = tc.w, tc.h
+		}
+		_, size, err := packCells(widths, heights, 0)
+		if err != nil || size != tc.expect {
+			t.Errorf("%d cells %dx%d: sheet %v, expect %v, %v", tc.n, tc.w, tc.h, size, tc.expect, err)
+		}
+	}
+}
+
+func TestRasterizePadding(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pad = 2
+	img, fc, err := rasterizeTruetype(context.Background(), ttf, 16, ASCII, pad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPacked(t, img, fc)
+	// padded cells, with the blank row above, do not overlap
+	var cells []image.Rectangle
+	for _, g := range fc.Glyphs {
+		if g.Width == 0 {
+			continue
+		}
+		r := image.Rect(int(g.X), int(g.Y), int(g.X+g.Width), int(g.Y+g.Height)+1)
+		cells = append(cells, r.Inset(-pad))
+		if !r.Inset(-pad).In(img.Bounds()) {
+			t.Errorf("padded cell %v is out of sheet %v", r.Inset(-pad), img.Bounds())
+		}
+	}
+	for i := range cells {
+		for j := range cells[:i] {
+			if cells[i].Overlaps(cells[j]) {
+				t.Fatalf("padded cells %v and %v overlap", cells[i], cells[j])
+			}
+		}
+	}
+}
+
+// checkPacked checks that glyph cells do not overlap and hold all ink
+// of the sheet.
+func checkPacked(t *testing.T, img *image.RGBA, fc *FontConfig) {
+	t.Helper()
+	owner := make(map[image.Point]int)
+	for i, g := range fc.Glyphs {
+		for y := g.Y; y <= g.Y+g.Height; y++ {
+			for x := g.X; x < g.X+g.Width; x++ {
+				p := image.Pt(int(x), int(y))
+				if j, ok := owner[p]; ok {
+					t.Fatalf("glyphs %d and %d overlap at %v", j, i, p)
+				}
+				owner[p] = i
+			}
+		}
+		if g.step() != g.Advance {
+			t.Errorf("glyph %q: step %d, advance %d", fc.RuneAt(i), g.step(), g.Advance)
+		}
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y).A == 0 {
+				continue
+			}
+			if _, ok := owner[image.Pt(x, y)]; !ok {
+				t.Fatalf("ink at %v is out of glyph cells", image.Pt(x, y))
+			}
+		}
+	}
+}
+
+func TestRasterizePacked(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, fc, err := rasterizeTruetype(context.Background(), ttf, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPacked(t, img, fc)
+	// the uniform grid of cells as wide as all glyphs took 512x256
+	if 256*256 < img.Bounds().Dx()*img.Bounds().Dy() {
+		t.Errorf("sheet %v", img.Bounds())
+	}
+	if g := fc.Glyphs[fc.Index('i')]; fc.Glyphs[fc.Index('W')].Width <= g.Width {
+		t.Errorf("glyph widths are not tight")
+	}
+	checkTight(t, fc)
+
+	otf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, fc, err = rasterizeOpentype(context.Background(), otf, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPacked(t, img, fc)
+	checkTight(t, fc)
+}
+
+// checkTight checks that bitmaps are as high as ink of glyphs and glyphs
+// without descenders sit on the baseline.
+func checkTight(t *testing.T, fc *FontConfig) {
+	t.Helper()
+	x, dot := fc.Glyphs[fc.Index('x')], fc.Glyphs[fc.Index('.')]
+	if x.Height <= dot.Height || x.Height == x.cell() {
+		t.Errorf("bitmaps are not tight: x %+v, dot %+v", x, dot)
+	}
+	for _, r := range "xH." {
+		if g := fc.Glyphs[fc.Index(r)]; g.BottomBearing != fc.Baseline && g.BottomBearing != fc.Baseline-1 {
+			t.Errorf("glyph %q is %d above the cell bottom, baseline %d", r, g.BottomBearing, fc.Baseline)
+		}
+	}
+	if g := fc.Glyphs[fc.Index('p')]; fc.Baseline <= g.BottomBearing {
+		t.Errorf("glyph 'p' has no descender: %+v", g)
+	}
+	for i := range fc.Glyphs {
+		if g := fc.Glyphs[i]; g.cell() != fc.Glyphs[0].cell() {
+			t.Errorf("glyph %q cell is %d high, expect %d", fc.RuneAt(i), g.cell(), fc.Glyphs[0].cell())
+		}
+	}
+}
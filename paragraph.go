@@ -0,0 +1,304 @@
+package glsymbol
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Align is a horizontal alignment of lines.
+type Align int
+
+// Alignments of lines.
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+	AlignJustify // both edges, except the last line of a paragraph
+)
+
+// ParagraphStyle describes the typographic structure of paragraphs.
+// Paragraphs are separated by line breaks of the newline policy, see
+// SetNewlinePolicy. All values are in pixels.
+type ParagraphStyle struct {
+	Align Align
+
+	Indent          int32 // left indent of all lines
+	RightIndent     int32 // right indent of all lines
+	FirstLineIndent int32 // added to Indent for the first line, may be negative
+
+	SpaceBefore int32 // space above a paragraph, except the first one
+	SpaceAfter  int32 // space below a paragraph, except the last one
+
+	// List, if not nil, lays out every non-empty paragraph as an item
+	// of a list. FirstLineIndent is not used for items.
+	List *ListStyle
+}
+
+// ListStyle describes bullet and numbered lists.
+//
+// Markers are placed in a gutter on the left of items, aligned to its
+// right edge. Lines of an item are indented by the gutter, which gives
+// a hanging indent.
+type ListStyle struct {
+	// Marker returns the marker of the item with the index, starting
+	// from zero. See Bullet and Numbered.
+	Marker func(item int) string
+
+	// Gutter is the width of the marker column. Zero means the width
+	// of the widest marker and a space.
+	Gutter int32
+}
+
+// Bullet returns a list marker function with the same marker
+// for every item, for example "•" or "-".
+func Bullet(marker string) func(item int) string {
+	return func(int) string { return marker }
+}
+
+// Numbered returns a list marker function with item numbers starting
+// from start and followed by the suffix, for example "1." or "1)".
+func Numbered(start int, suffix string) func(item int) string {
+	return func(item int) string {
+		return strconv.Itoa(start+item) + suffix
+	}
+}
+
+// A LineBox is a laid out line of text.
+type LineBox struct {
+	Text string
+
+	// X and Y are the position of the top left corner of the line
+	// relative to the top left corner of the text block.
+	// Lines are placed with the line height of the font.
+	// Y goes down independently of the origin used for drawing.
+	X, Y int32
+
+	Width int32 // advance size of Text without Gap
+
+	// Gap is the extra space after every space of a justified line.
+	Gap float32
+
+	// Marker reports a list marker.
+	Marker bool
+
+	wrap lineWrap // how the line continues the previous one
+}
+
+// lineWrap tells how a laid out line continues the previous line.
+type lineWrap int
+
+const (
+	wrapNone  lineWrap = iota // first line of a paragraph
+	wrapSpace                 // line was broken at a space
+	wrapWord                  // word was broken between runes
+)
+
+// LayoutParagraphs breaks text into lines which fit the width and
+// places them according to the style. Lines are broken at spaces,
+// a word wider than a line is broken between runes.
+// It returns the lines and the height of the text block.
+func (f *Font) LayoutParagraphs(text string, width int32, style ParagraphStyle) (lines []LineBox, height int32) {
+	sp := startSpan(StageLayout)
+	defer sp.end()
+
+	lineHeight := f.lineHeight()
+	space := f.advanceSize(" ")
+	paras := splitLines(text)
+
+	var gutter int32
+	if style.List != nil {
+		gutter = style.List.Gutter
+		if gutter == 0 {
+			item := 0
+			for _, para := range paras {
+				if strings.TrimSpace(para) == "" {
+					continue
+				}
+				if w := f.advanceSize(style.List.Marker(item)) + space; gutter < w {
+					gutter = w
+				}
+				item++
+			}
+		}
+	}
+
+	var (
+		y    int32
+		item int
+		line []string // words of the current line, from Arena
+	)
+	for pi, para := range paras {
+		if 0 < pi {
+			y += style.SpaceBefore
+		}
+		first := true
+		wrap := wrapNone
+		words := strings.Fields(para)
+		if cap(line) < len(words) {
+			line = f.Arena.Strings(len(words))
+		}
+		isItem := style.List != nil && len(words) != 0
+		if isItem {
+			marker := style.List.Marker(item)
+			mw := f.advanceSize(marker)
+			lines = append(lines, LineBox{
+				Text:   marker,
+				X:      style.Indent + gutter - space - mw,
+				Y:      y,
+				Width:  mw,
+				Marker: true,
+			})
+			item++
+		}
+		for {
+			indent := style.Indent
+			if isItem {
+				indent += gutter
+			} else if first {
+				indent += style.FirstLineIndent
+			}
+			avail := width - indent - style.RightIndent
+
+			var (
+				lw     int32
+				spaces int
+				split  bool
+			)
+			line = line[:0]
+			for len(words) != 0 {
+				ww := f.advanceSize(words[0])
+				add := ww
+				if len(line) != 0 {
+					add += space
+				}
+				if avail < lw+add {
+					if len(line) == 0 {
+						// word is wider than the line, a rune wider
+						// than the line is placed alone
+						head, tail := f.splitWord(words[0], avail)
+						line = append(line, head)
+						lw += f.advanceSize(head)
+						if tail == "" {
+							words = words[1:]
+						} else {
+							words[0] = tail
+							split = true
+						}
+					}
+					break
+				}
+				if len(line) != 0 {
+					spaces++
+				}
+				line = append(line, words[0])
+				lw += add
+				words = words[1:]
+			}
+
+			box := LineBox{
+				Text:  strings.Join(line, " "),
+				X:     indent,
+				Y:     y,
+				Width: lw,
+				wrap:  wrap,
+			}
+			free := avail - lw
+			switch style.Align {
+			case AlignCenter:
+				box.X += f.rounding().Round(float32(free) / 2)
+			case AlignRight:
+				box.X += free
+			case AlignJustify:
+				if len(words) != 0 && 0 < spaces && 0 < free {
+					box.Gap = float32(free) / float32(spaces)
+				}
+			}
+			lines = append(lines, box)
+			y += lineHeight
+			first = false
+			wrap = wrapSpace
+			if split {
+				wrap = wrapWord
+			}
+			if len(words) == 0 {
+				break
+			}
+		}
+		y += style.SpaceAfter
+	}
+	// no space after the last paragraph
+	return lines, y - style.SpaceAfter
+}
+
+// splitWord splits the word, so the head fits the width.
+// The head holds at least one rune, whatever the width is.
+func (f *Font) splitWord(word string, width int32) (head, tail string) {
+	var w int32
+	for i, r := range word {
+		rw := f.advanceSize(string(r))
+		if 0 < i && width < w+rw {
+			return word[:i], word[i:]
+		}
+		w += rw
+	}
+	_, size := utf8.DecodeRuneInString(word)
+	return word[:size], word[size:]
+}
+
+// DrawParagraphs lays out the text by LayoutParagraphs and draws it.
+// The top left corner of the text block is at x, y.
+func (f *Font) DrawParagraphs(x, y float32, width int32, text string, style ParagraphStyle) error {
+	lines, _ := f.LayoutParagraphs(text, width, style)
+	return f.drawLines(x, y, lines)
+}
+
+// drawLines draws laid out lines of the text block with the top left
+// corner at x, y.
+func (f *Font) drawLines(x, y float32, lines []LineBox) error {
+	for _, l := range lines {
+		ly := f.lineY(below(y, float32(l.Y)))
+		if l.Gap == 0 {
+			if err := f.Printf(x+float32(l.X+f.anchor(l.Width)), ly, l.Text); err != nil {
+				return err
+			}
+			continue
+		}
+		// justified line is drawn word by word
+		err := f.eachWord(l, func(wx float32, _ int, word string) error {
+			return f.Printf(x+wx, ly, word)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachWord calls fn for words of the justified line with their byte
+// offsets and the places Printf draws them from, relative to the left
+// edge of the text block. Words of RightToLeft lines go from the right
+// edge of the line to the left, as the pen of unjustified lines.
+func (f *Font) eachWord(l LineBox, fn func(x float32, offset int, word string) error) error {
+	space := float32(f.advanceSize(" ")) + l.Gap
+	x := float32(l.X)
+	rtl := f.Direction == RightToLeft
+	if rtl {
+		x += float32(l.Width) + l.Gap*float32(strings.Count(l.Text, " "))
+	}
+	offset := 0
+	for rest, more := l.Text, true; more; {
+		var word string
+		word, rest, more = strings.Cut(rest, " ")
+		if err := fn(x, offset, word); err != nil {
+			return err
+		}
+		step := float32(f.advanceSize(word)) + space
+		if rtl {
+			step = -step
+		}
+		x += step
+		offset += len(word) + 1
+	}
+	return nil
+}
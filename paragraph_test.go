@@ -0,0 +1,150 @@
+package glsymbol
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLayoutParagraphs(t *testing.T) {
+	f := testFont() // 8 pixels per rune, 4 pixels space
+	tcs := []struct {
+		text   string
+		width  int32
+		style  ParagraphStyle
+		expect string
+		height int32
+	}{
+		{
+			text:   "aaa bbb ccc",
+			width:  70,
+			expect: `"aaa bbb" 0,0 52 0|"ccc" 0,16 24 0|`,
+			height: 32,
+		},
+		{
+			text:   "aaa bbb ccc",
+			width:  70,
+			style:  ParagraphStyle{Align: AlignRight},
+			expect: `"aaa bbb" 18,0 52 0|"ccc" 46,16 24 0|`,
+			height: 32,
+		},
+		{
+			text:   "aaa bbb ccc",
+			width:  70,
+			style:  ParagraphStyle{Align: AlignCenter},
+			expect: `"aaa bbb" 9,0 52 0|"ccc" 23,16 24 0|`,
+			height: 32,
+		},
+		{
+			text:   "aa bb cc dd",
+			width:  70,
+			style:  ParagraphStyle{Align: AlignJustify},
+			expect: `"aa bb cc" 0,0 56 7|"dd" 0,16 16 0|`,
+			height: 32,
+		},
+		{
+			text:   "aaa bbb\nccc",
+			width:  100,
+			style:  ParagraphStyle{Indent: 10, FirstLineIndent: 20, SpaceBefore: 3, SpaceAfter: 2},
+			expect: `"aaa bbb" 30,0 52 0|"ccc" 30,21 24 0|`,
+			height: 37,
+		},
+		{
+			text:   "aaa bbb ccc",
+			width:  60,
+			style:  ParagraphStyle{Indent: 10, FirstLineIndent: -10, RightIndent: 6},
+			expect: `"aaa bbb" 0,0 52 0|"ccc" 10,16 24 0|`,
+			height: 32,
+		},
+		{
+			text:   "aaaaaaaaaa b",
+			width:  30,
+			expect: `"aaa" 0,0 24 0|"aaa" 0,16 24 0|"aaa" 0,32 24 0|"a b" 0,48 20 0|`,
+			height: 64,
+		},
+		{
+			text:   "\n",
+			width:  30,
+			expect: `"" 0,0 0 0|"" 0,16 0 0|`,
+			height: 32,
+		},
+	}
+	for i, tc := range tcs {
+		lines, height := f.LayoutParagraphs(tc.text, tc.width, tc.style)
+		var s string
+		for _, l := range lines {
+			s += fmt.Sprintf("%q %d,%d %d %v|", l.Text, l.X, l.Y, l.Width, l.Gap)
+		}
+		if s != tc.expect || height != tc.height {
+			t.Errorf("case %d:\n%s %d\n%s %d", i, s, height, tc.expect, tc.height)
+		}
+	}
+}
+
+func TestLayoutNarrow(t *testing.T) {
+	f := testFont()
+	for _, tc := range []struct {
+		text   string
+		width  int32
+		style  ParagraphStyle
+		expect []string
+	}{
+		// no room between the indents, a rune per line
+		{"ab", 20, ParagraphStyle{Indent: 30}, []string{"a", "b"}},
+		// runes wider than the line leave no empty lines
+		{"a bb", 5, ParagraphStyle{}, []string{"a", "b", "b"}},
+	} {
+		lines, _ := f.LayoutParagraphs(tc.text, tc.width, tc.style)
+		var got []string
+		for _, l := range lines {
+			got = append(got, l.Text)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.expect) {
+			t.Errorf("%q at %d: lines %q", tc.text, tc.width, got)
+		}
+	}
+}
+
+func TestLayoutList(t *testing.T) {
+	f := testFont() // 8 pixels per rune, 4 pixels space
+	text := "aaa bbb\n\nccc"
+	tcs := []struct {
+		list   ListStyle
+		expect string
+	}{
+		{
+			list:   ListStyle{Marker: Bullet("-")},
+			expect: `"-" 10,0 8 true|"aaa bbb" 22,0 52 false|"" 10,16 0 false|"-" 10,32 8 true|"ccc" 22,32 24 false|`,
+		},
+		{
+			list:   ListStyle{Marker: Numbered(9, ".")},
+			expect: `"9." 18,0 16 true|"aaa" 38,0 24 false|"bbb" 38,16 24 false|"" 10,32 0 false|"10." 10,48 24 true|"ccc" 38,48 24 false|`,
+		},
+		{
+			list:   ListStyle{Marker: Bullet("*"), Gutter: 20},
+			expect: `"*" 18,0 8 true|"aaa" 30,0 24 false|"bbb" 30,16 24 false|"" 10,32 0 false|"*" 18,48 8 true|"ccc" 30,48 24 false|`,
+		},
+	}
+	for i, tc := range tcs {
+		style := ParagraphStyle{Indent: 10, List: &tc.list}
+		lines, _ := f.LayoutParagraphs(text, 80, style)
+		var s string
+		for _, l := range lines {
+			s += fmt.Sprintf("%q %d,%d %d %v|", l.Text, l.X, l.Y, l.Width, l.Marker)
+		}
+		if s != tc.expect {
+			t.Errorf("case %d:\n%s\n%s", i, s, tc.expect)
+		}
+	}
+}
+
+func TestParagraphRounding(t *testing.T) {
+	f := testFont()
+	for r, x := range map[Rounding]int32{RoundFloor: 5, RoundNearest: 6, RoundCeil: 6} {
+		f.Rounding = r
+		// free space of 11 pixels
+		lines, _ := f.LayoutParagraphs("abc", 35, ParagraphStyle{Align: AlignCenter})
+		if lines[0].X != x {
+			t.Errorf("rounding %d: x %d, expect %d", r, lines[0].X, x)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package glsymbol
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"math/bits"
+)
+
+// PCF is the compiled form of BDF fonts of the X Window System, which
+// Linux distributions ship for console and terminal fonts, usually
+// compressed by gzip. Encodings of PCF fonts are used as runes, which
+// is right for ISO10646 and ISO8859-1 fonts.
+
+// pcf table types
+const (
+	pcfMetrics      = 1 << 2
+	pcfBitmaps      = 1 << 3
+	pcfBDFEncodings = 1 << 5
+)
+
+// pcf table formats
+const (
+	pcfCompressedMetrics = 0x100
+	pcfByteMSB           = 1 << 2
+	pcfBitMSB            = 1 << 3
+)
+
+// pcfMetric is the metric of a glyph of a PCF font.
+type pcfMetric struct {
+	lsb, rsb, width, ascent, descent int16
+}
+
+// size returns the size of the glyph bitmap.
+func (m pcfMetric) size() (width, height int) {
+	width = int(m.rsb) - int(m.lsb)
+	height = int(m.ascent) + int(m.descent)
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	return
+}
+
+// pcfFont is a parsed PCF font.
+type pcfFont struct {
+	metrics  []pcfMetric
+	bitmaps  [][]byte // rows of glyphs, MSB first, padded to rowBytes
+	rowBytes []int
+	encoding map[rune]int // glyph indexes of encoded runes
+	defChar  int          // glyph index of the default character, or -1
+	low      rune         // lowest encoded rune
+	high     rune         // highest encoded rune
+}
+
+// pcfTable is a section of the table of contents of a PCF file.
+type pcfTable struct {
+	format uint32
+	data   []byte
+}
+
+// pcfReader reads values of a table in its byte order.
+type pcfReader struct {
+	order binary.ByteOrder
+	data  []byte
+	err   error
+}
+
+func newPCFReader(t pcfTable, name string) *pcfReader {
+	r := &pcfReader{order: binary.LittleEndian, data: t.data}
+	// every table repeats its format in the first 4 bytes, little endian
+	if len(r.data) < 4 {
+		r.err = fmt.Errorf("glsymbol: pcf %s table is truncated", name)
+		return r
+	}
+	r.data = r.data[4:]
+	if t.format&pcfByteMSB != 0 {
+		r.order = binary.BigEndian
+	}
+	return r
+}
+
+func (r *pcfReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = fmt.Errorf("glsymbol: pcf table is truncated")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *pcfReader) u8() uint8 {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *pcfReader) i16() int16 {
+	if b := r.take(2); b != nil {
+		return int16(r.order.Uint16(b))
+	}
+	return 0
+}
+
+func (r *pcfReader) i32() int32 {
+	if b := r.take(4); b != nil {
+		return int32(r.order.Uint32(b))
+	}
+	return 0
+}
+
+// parsePCF parses a PCF font, which may be compressed by gzip.
+func parsePCF(rd io.Reader) (*pcfFont, error) {
+	br := bufio.NewReader(rd)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("glsymbol: pcf: %v", err)
+		}
+		rd = zr
+	} else {
+		rd = br
+	}
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, fmt.Errorf("glsymbol: pcf: %v", err)
+	}
+	if len(data) < 8 || !bytes.Equal(data[:4], []byte("\x01fcp")) {
+		return nil, fmt.Errorf("glsymbol: not a pcf font")
+	}
+	count := binary.LittleEndian.Uint32(data[4:])
+	if uint64(len(data)) < 8+16*uint64(count) {
+		return nil, fmt.Errorf("glsymbol: pcf table of contents is truncated")
+	}
+	tables := make(map[uint32]pcfTable)
+	for i := uint32(0); i < count; i++ {
+		e := data[8+16*i:]
+		typ := binary.LittleEndian.Uint32(e)
+		format := binary.LittleEndian.Uint32(e[4:])
+		size := binary.LittleEndian.Uint32(e[8:])
+		offset := binary.LittleEndian.Uint32(e[12:])
+		if uint64(len(data)) < uint64(offset)+uint64(size) {
+			return nil, fmt.Errorf("glsymbol: pcf table %d is out of file", typ)
+		}
+		tables[typ] = pcfTable{format: format, data: data[offset : offset+size]}
+	}
+	for _, typ := range []uint32{pcfMetrics, pcfBitmaps, pcfBDFEncodings} {
+		if _, ok := tables[typ]; !ok {
+			return nil, fmt.Errorf("glsymbol: pcf font has no table %d", typ)
+		}
+	}
+
+	f := new(pcfFont)
+	if err := f.readMetrics(tables[pcfMetrics]); err != nil {
+		return nil, err
+	}
+	if err := f.readBitmaps(tables[pcfBitmaps]); err != nil {
+		return nil, err
+	}
+	if err := f.readEncodings(tables[pcfBDFEncodings]); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *pcfFont) readMetrics(t pcfTable) error {
+	r := newPCFReader(t, "metrics")
+	if t.format&pcfCompressedMetrics != 0 {
+		n := int(uint16(r.i16()))
+		f.metrics = make([]pcfMetric, 0, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			var m pcfMetric
+			for _, v := range []*int16{&m.lsb, &m.rsb, &m.width, &m.ascent, &m.descent} {
+				*v = int16(r.u8()) - 0x80
+			}
+			f.metrics = append(f.metrics, m)
+		}
+		return r.err
+	}
+	n := int(r.i32())
+	if n < 0 || len(r.data)/12 < n {
+		return fmt.Errorf("glsymbol: pcf metrics table is truncated")
+	}
+	f.metrics = make([]pcfMetric, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		m := pcfMetric{lsb: r.i16(), rsb: r.i16(), width: r.i16(), ascent: r.i16(), descent: r.i16()}
+		r.i16() // attributes
+		f.metrics = append(f.metrics, m)
+	}
+	return r.err
+}
+
+func (f *pcfFont) readBitmaps(t pcfTable) error {
+	r := newPCFReader(t, "bitmaps")
+	n := int(r.i32())
+	if n != len(f.metrics) {
+		return fmt.Errorf("glsymbol: pcf font has %d bitmaps for %d metrics", n, len(f.metrics))
+	}
+	offsets := make([]int32, n)
+	for i := range offsets {
+		offsets[i] = r.i32()
+	}
+	var sizes [4]int32
+	for i := range sizes {
+		sizes[i] = r.i32()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	pad := 1 << (t.format & 3)
+	unit := 1 << ((t.format >> 4) & 3)
+	data := r.data
+	if int(sizes[t.format&3]) < len(data) {
+		data = data[:sizes[t.format&3]]
+	}
+	f.bitmaps = make([][]byte, n)
+	f.rowBytes = make([]int, n)
+	for i, m := range f.metrics {
+		w, h := m.size()
+		row := ((w+7)/8 + pad - 1) / pad * pad
+		start := int(offsets[i])
+		if start < 0 || len(data) < start+row*h {
+			return fmt.Errorf("glsymbol: pcf bitmap of glyph %d is out of table", i)
+		}
+		b := append([]byte(nil), data[start:start+row*h]...)
+		if t.format&pcfBitMSB == 0 {
+			for k := range b {
+				b[k] = bits.Reverse8(b[k])
+			}
+		}
+		if (t.format&pcfByteMSB == 0) != (t.format&pcfBitMSB == 0) && 1 < unit {
+			// bytes of scan units are in the opposite order of bits
+			for k := 0; k+unit <= len(b); k += unit {
+				for a, c := k, k+unit-1; a < c; a, c = a+1, c-1 {
+					b[a], b[c] = b[c], b[a]
+				}
+			}
+		}
+		f.bitmaps[i] = b
+		f.rowBytes[i] = row
+	}
+	return nil
+}
+
+func (f *pcfFont) readEncodings(t pcfTable) error {
+	r := newPCFReader(t, "encodings")
+	min2, max2 := int(r.i16()), int(r.i16())
+	min1, max1 := int(r.i16()), int(r.i16())
+	def := int(r.i16())
+	if r.err != nil {
+		return r.err
+	}
+	f.encoding = make(map[rune]int)
+	f.defChar = -1
+	f.low, f.high = -1, -1
+	for b1 := min1; b1 <= max1; b1++ {
+		for b2 := min2; b2 <= max2; b2++ {
+			idx := int(uint16(r.i16()))
+			if r.err != nil {
+				return r.err
+			}
+			if idx == 0xffff || len(f.metrics) <= idx {
+				continue
+			}
+			code := rune(b1<<8 | b2)
+			f.encoding[code] = idx
+			if code == rune(def) {
+				f.defChar = idx
+			}
+			if f.low < 0 || code < f.low {
+				f.low = code
+			}
+			if f.high < code {
+				f.high = code
+			}
+		}
+	}
+	if len(f.encoding) == 0 {
+		return fmt.Errorf("glsymbol: pcf font has no encoded glyphs")
+	}
+	return nil
+}
+
+// sheet renders glyphs of the range into a sprite sheet and its config.
+// Runes without glyphs are drawn as the default character, if any.
+func (f *pcfFont) sheet(low, high rune) (*image.RGBA, *FontConfig) {
+	var ascent, descent int
+	for _, m := range f.metrics {
+		if ascent < int(m.ascent) {
+			ascent = int(m.ascent)
+		}
+		if descent < int(m.descent) {
+			descent = int(m.descent)
+		}
+	}
+	glyphs := make([]rasterGlyph, len(f.metrics))
+	for i, m := range f.metrics {
+		w, h := m.size()
+		glyphs[i] = rasterGlyph{
+			width:   w,
+			height:  h,
+			top:     ascent - int(m.ascent),
+			left:    int(m.lsb),
+			advance: int(m.width),
+			stride:  f.rowBytes[i],
+			bits:    f.bitmaps[i],
+		}
+	}
+	index := func(r rune) int {
+		if idx, ok := f.encoding[r]; ok {
+			return idx
+		}
+		return f.defChar
+	}
+	return rasterSheet(low, high, glyphs, index, ascent+descent, descent)
+}
+
+// DecodePCF reads a PCF font, which may be compressed by gzip, into
+// a sprite sheet and its config for the runes from low to high. If high
+// is zero, the range of all encoded runes is scanned from the font.
+func DecodePCF(r io.Reader, low, high rune) (*image.RGBA, *FontConfig, error) {
+	f, err := parsePCF(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if high == 0 {
+		low, high = f.low, f.high
+	}
+	if high < low {
+		return nil, nil, fmt.Errorf("glsymbol: high rune %q is less than low rune %q", high, low)
+	}
+	img, config := f.sheet(low, high)
+	return img, config, nil
+}
+
+// LoadPCF loads a PCF bitmap font, which may be compressed by gzip,
+// with the runes from low to high. If high is zero, all encoded runes
+// of the font are loaded. See DecodePCF.
+func LoadPCF(r io.Reader, low, high rune) (*Font, error) {
+	img, config, err := DecodePCF(r, low, high)
+	if err != nil {
+		return nil, err
+	}
+	return loadFont(img, config)
+}
@@ -0,0 +1,168 @@
+package glsymbol
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"image/color"
+	"math/bits"
+	"testing"
+)
+
+// testPCF builds a PCF font with glyphs 'A' and 'C', where 'B' is not
+// encoded and 'A' is the default character. Bitmaps are padded to pad
+// bytes and written in the byte and bit order of msb.
+func testPCF(msb bool, pad int) []byte {
+	var order binary.ByteOrder = binary.LittleEndian
+	format := uint32(0)
+	switch pad {
+	case 2:
+		format |= 1
+	case 4:
+		format |= 2
+	}
+	if msb {
+		order = binary.BigEndian
+		format |= pcfByteMSB | pcfBitMSB
+	}
+	table := func(values ...interface{}) []byte {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, format)
+		for _, v := range values {
+			binary.Write(&buf, order, v)
+		}
+		return buf.Bytes()
+	}
+
+	metrics := table(int32(2),
+		[]int16{0, 3, 4, 2, 1, 0},
+		[]int16{1, 2, 3, 1, 0, 0})
+
+	rows := [][]byte{{0xa0}, {0x40}, {0xe0}, {0x80}}
+	var data []byte
+	for _, row := range rows {
+		r := make([]byte, pad)
+		copy(r, row)
+		if !msb {
+			for i := range r {
+				r[i] = bits.Reverse8(r[i])
+			}
+		}
+		data = append(data, r...)
+	}
+	size := int32(len(data))
+	bitmaps := table(int32(2), []int32{0, int32(3 * pad)},
+		[]int32{size, size, size, size}, data)
+
+	encodings := table([]int16{0x41, 0x43, 0, 0, 0x41}, []uint16{0, 0xffff, 1})
+
+	var buf bytes.Buffer
+	buf.WriteString("\x01fcp")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	offset := uint32(8 + 3*16)
+	for _, t := range []struct {
+		typ  uint32
+		data []byte
+	}{{pcfMetrics, metrics}, {pcfBitmaps, bitmaps}, {pcfBDFEncodings, encodings}} {
+		binary.Write(&buf, binary.LittleEndian, []uint32{t.typ, format, uint32(len(t.data)), offset})
+		offset += uint32(len(t.data))
+	}
+	buf.Write(metrics)
+	buf.Write(bitmaps)
+	buf.Write(encodings)
+	return buf.Bytes()
+}
+
+func TestDecodePCF(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(testPCF(false, 1))
+	zw.Close()
+
+	for name, data := range map[string][]byte{
+		"lsb":  testPCF(false, 1),
+		"msb":  testPCF(true, 4),
+		"pad2": testPCF(false, 2),
+		"gzip": gz.Bytes(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			img, config, err := DecodePCF(bytes.NewReader(data), 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Low != 'A' || config.High != 'C' || config.Baseline != 1 {
+				t.Fatalf("config %q..%q baseline %d", config.Low, config.High, config.Baseline)
+			}
+			if err := config.Validate(img.Bounds()); err != nil {
+				t.Fatal(err)
+			}
+			a, b, c := config.Glyphs[0], config.Glyphs[1], config.Glyphs[2]
+			if a.Width != 3 || a.Height != 3 || a.Advance != 4 || a.RightBearing != 1 {
+				t.Errorf("glyph A %+v", a)
+			}
+			if b.Advance != a.Advance || b.Width != a.Width {
+				t.Errorf("missing glyph B %+v is not the default glyph", b)
+			}
+			if c.Width != 1 || c.Height != 3 || c.LeftBearing != 1 || c.RightBearing != 1 {
+				t.Errorf("glyph C %+v", c)
+			}
+
+			ink := func(x, y int) bool {
+				_, _, _, a := img.At(x, y).RGBA()
+				return a != 0
+			}
+			// rows of glyph A, top row of a cell is below its Y
+			want := []string{"101", "010", "111"}
+			for gx, g := range []Glyph{a, b} {
+				for y, row := range want {
+					for x := range row {
+						px, py := int(g.X)+x, int(g.Y)+1+y
+						if ink(px, py) != (row[x] == '1') {
+							t.Errorf("glyph %d: pixel %d,%d", gx, px, py)
+						}
+					}
+				}
+			}
+			// glyph C has ascent 1 and sits on the baseline
+			for y := 0; y < 3; y++ {
+				if ink(int(c.X), int(c.Y)+1+y) != (y == 1) {
+					t.Errorf("glyph C: row %d", y)
+				}
+			}
+			if img.RGBAAt(int(c.X), int(c.Y)+2) != (color.RGBA{255, 255, 255, 255}) {
+				t.Errorf("glyph C is not white")
+			}
+		})
+	}
+}
+
+func TestDecodePCFRange(t *testing.T) {
+	_, config, err := DecodePCF(bytes.NewReader(testPCF(false, 1)), 'C', 'E')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Glyphs) != 3 || config.Glyphs[0].Width != 1 {
+		t.Fatalf("glyphs %+v", config.Glyphs)
+	}
+	// runes out of the font are the default glyph
+	if config.Glyphs[2].Advance != 4 {
+		t.Errorf("glyph E %+v", config.Glyphs[2])
+	}
+}
+
+func TestDecodePCFInvalid(t *testing.T) {
+	data := testPCF(false, 1)
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     []byte("\x01fcq\x00\x00\x00\x00"),
+		"toc":       data[:20],
+		"truncated": data[:len(data)-4],
+	} {
+		if _, _, err := DecodePCF(bytes.NewReader(data), 0, 0); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	if _, _, err := DecodePCF(bytes.NewReader(data), 'C', 'A'); err == nil {
+		t.Errorf("no error for reversed range")
+	}
+}
@@ -0,0 +1,97 @@
+package glsymbol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// proggyPixels is the size in pixels the embedded ProggyClean font is
+// designed for, at which its glyphs are crisp.
+const proggyPixels = 13
+
+// A PixelPolicy provides fonts of a face by sizes, with a pixel font
+// below a size: truetype glyphs rasterized at a few pixels lose their
+// shapes, a font drawn at the size it is designed for stays readable.
+//
+// Glyphs of the pixel font are placed by advances, kerning and line
+// heights of the face font of the size, so measures and layouts of text
+// are the same as with the face font and do not jump when the size
+// crosses Below. Glyphs of the pixel font wider than advances of small
+// sizes overlap.
+type PixelPolicy struct {
+	Face  *Face // fonts of sizes from Below up
+	Below int32 // size in points below which the pixel font is drawn
+
+	// Pixel loads the pixel font, it is called once for every size
+	// below Below. Nil loads the embedded ProggyClean font at its
+	// design size.
+	Pixel func() (*Font, error)
+
+	fonts map[int32]pixelFont // pixel fonts by sizes
+}
+
+// pixelFont is a pixel font placing glyphs by metrics of a face font.
+type pixelFont struct {
+	font, metrics *Font
+}
+
+// Font returns the font for the size in points. As Face.Font it should
+// be requested every frame instead of being kept.
+func (p *PixelPolicy) Font(size int32) (*Font, error) {
+	if p.Face == nil {
+		return nil, fmt.Errorf("glsymbol: pixel policy has no face")
+	}
+	metrics, err := p.Face.Font(size)
+	if err != nil || p.Below <= size {
+		return metrics, err
+	}
+	if pf, ok := p.fonts[size]; ok {
+		if pf.metrics != metrics {
+			// the face font is released and loaded again
+			pf.font.matchMetrics(metrics)
+			p.fonts[size] = pixelFont{pf.font, metrics}
+		}
+		return pf.font, nil
+	}
+	load := p.Pixel
+	if load == nil {
+		load = func() (*Font, error) {
+			return LoadTruetype(strings.NewReader(DefaultEmbeddedFont), proggyPixels, 32, 127)
+		}
+	}
+	f, err := load()
+	if err != nil {
+		return nil, err
+	}
+	f.matchMetrics(metrics)
+	if p.fonts == nil {
+		p.fonts = make(map[int32]pixelFont)
+	}
+	p.fonts[size] = pixelFont{f, metrics}
+	return f, nil
+}
+
+// Release releases pixel fonts of the policy, fonts of the face are
+// released with the face.
+func (p *PixelPolicy) Release() {
+	for _, pf := range p.fonts {
+		pf.font.Release()
+	}
+	p.fonts = nil
+}
+
+// matchMetrics sets advances, kerning and the line height of the font to
+// those of the font m, so text is measured and placed as by m.
+func (f *Font) matchMetrics(m *Font) {
+	f.advances = make(map[rune]int32, len(m.Config.Glyphs)+len(m.advances))
+	for i := range m.Config.Glyphs {
+		r := m.Config.RuneAt(i)
+		f.advances[r] = m.step(r, &m.Config.Glyphs[i])
+	}
+	for r, a := range m.advances {
+		f.advances[r] = a
+	}
+	f.kern, f.NoKerning = m.kern, m.NoKerning
+	f.LineHeight, f.Grid = LinePixels(m.lineHeight()), m.Grid
+	f.Rounding = m.Rounding
+}
@@ -0,0 +1,39 @@
+package glsymbol
+
+import "testing"
+
+func TestPixelPolicy(t *testing.T) {
+	small, large := testSheetFont(), testSheetFont()
+	// the face font of the small size is narrower than the pixel font
+	for i := range small.Config.Glyphs {
+		small.Config.Glyphs[i].RightBearing -= 3
+	}
+	small.LineHeight = LinePixels(7)
+	small.kern = kernMap([]KernPair{{'A', 'V', -1}})
+	p := &PixelPolicy{
+		Face:  &Face{fonts: map[int32]*Font{6: small, 20: large}},
+		Below: 10,
+		Pixel: func() (*Font, error) { return testFont(), nil },
+	}
+	if f, err := p.Font(20); err != nil || f != large {
+		t.Errorf("font of a large size %p, %v", f, err)
+	}
+	f, err := p.Font(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f == small || f.Config == small.Config {
+		t.Fatalf("pixel font is not used")
+	}
+	for _, s := range []string{"AVA", "wide text"} {
+		if w, expect := f.advanceSize(s), small.advanceSize(s); w != expect || w == testFont().advanceSize(s) {
+			t.Errorf("width of %q %d, expect %d", s, w, expect)
+		}
+	}
+	if h := f.lineHeight(); h != 7 {
+		t.Errorf("line height %d", h)
+	}
+	if again, _ := p.Font(6); again != f {
+		t.Errorf("pixel font is loaded again")
+	}
+}
@@ -0,0 +1,107 @@
+package glsymbol
+
+import (
+	"image"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// PositionedGlyph is a glyph of laid out text placed as DrawParagraphs
+// draws it, for drawing by other pipelines from the sprite sheet of the
+// font. Positions are relative to the top left corner of the text block
+// with Y going down, as LineBox.
+type PositionedGlyph struct {
+	Rune rune   // the rune, the first one of a ligature
+	Text string // runes drawn by the glyph
+
+	// ID is the index of the glyph in the truetype or OpenType font data,
+	// zero for bitmap fonts and glyphs not found in the data.
+	ID sfnt.GlyphIndex
+
+	Line    int // index of the line of LayoutParagraphs
+	Cluster int // byte offset of Text in the text of the line
+
+	Pen   image.Point     // pen position on the baseline before the glyph
+	Box   image.Rectangle // bitmap of the glyph
+	Atlas image.Rectangle // bitmap of the glyph in the sprite sheet, see Sheet
+}
+
+// ShapeParagraphs lays out the text as LayoutParagraphs and returns its
+// glyphs in drawing order, with the places DrawParagraphs draws them
+// at. Glyphs without bitmaps, such as spaces, have empty boxes, spaces
+// of justified lines are gaps between words without glyphs. Snapping of
+// baselines by Grid depends on the place of the text block and is not
+// applied.
+func (f *Font) ShapeParagraphs(text string, width int32, style ParagraphStyle) []PositionedGlyph {
+	lines, _ := f.LayoutParagraphs(text, width, style)
+	var out []PositionedGlyph
+	for li, l := range lines {
+		top := l.Y + f.halfLeading()
+		baseline := top + f.MaxGlyphHeight - f.Config.Baseline
+		add := func(x int32, offset int, g PlacedGlyph) {
+			glyph := g.Glyph
+			p := PositionedGlyph{
+				Rune: g.Rune, Text: g.Text, ID: f.glyphIndex(g.Rune, glyph),
+				Line: li, Cluster: offset + g.Offset,
+				Pen: image.Pt(int(x+g.X-glyph.LeftBearing), int(baseline-g.Y)),
+			}
+			if 0 < glyph.Width && 0 < glyph.Height {
+				p.Box = image.Rect(0, 0, int(glyph.Width), int(glyph.Height)).
+					Add(image.Pt(int(x+g.X), int(top+glyph.TopBearing-g.Y)))
+				p.Atlas = image.Rect(int(glyph.X), int(glyph.Y)+1, int(glyph.X+glyph.Width), int(glyph.Y+glyph.Height)+1)
+			}
+			out = append(out, p)
+		}
+		if l.Gap == 0 {
+			f.place(l.Text, func(g PlacedGlyph) bool {
+				add(l.X+f.anchor(l.Width), 0, g)
+				return true
+			})
+			continue
+		}
+		// justified line is placed word by word as drawLines draws it
+		f.eachWord(l, func(wx float32, offset int, word string) error {
+			lx := f.rounding().Round(wx)
+			f.place(word, func(g PlacedGlyph) bool {
+				add(lx, offset, g)
+				return true
+			})
+			return nil
+		})
+	}
+	return out
+}
+
+// glyphIndex returns the index in the font data of the glyph placed for
+// the rune, zero if the font has no outlines or the glyph is not found.
+func (f *Font) glyphIndex(r rune, g *Glyph) sfnt.GlyphIndex {
+	s := f.subst
+	if s == nil {
+		return 0
+	}
+	if f.glyph(r) == g {
+		if id, err := s.font.GlyphIndex(&s.buf, r); err == nil {
+			return id
+		}
+		return 0
+	}
+	// substitutes, ligatures and glyphs of other runes placed by shapers
+	for id, sg := range s.glyphs {
+		if sg == g {
+			return id
+		}
+	}
+	for id, br := range s.byGlyph {
+		if f.glyph(br) == g {
+			return id
+		}
+	}
+	return 0
+}
+
+// Sheet returns the sprite sheet of the font, which Atlas rectangles of
+// positioned glyphs refer to. Glyphs added on demand may grow the sheet,
+// so it is taken again after laying out text. It must not be modified.
+func (f *Font) Sheet() *image.RGBA {
+	return f.img
+}
@@ -0,0 +1,137 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestShapeParagraphs(t *testing.T) {
+	f := testSheetFont()
+	glyphs := f.ShapeParagraphs("aa bb cc dd", 70, ParagraphStyle{Align: AlignJustify})
+	// words of the justified line are 7 pixels apart
+	var got []string
+	for _, g := range glyphs {
+		got = append(got, g.Text)
+	}
+	if len(glyphs) != 8 {
+		t.Fatalf("glyphs %q", got)
+	}
+	a := f.Config.Glyphs['a'-f.Config.Low]
+	tcs := []struct {
+		i       int
+		text    string
+		line    int
+		cluster int
+		x, y    int
+	}{
+		{0, "a", 0, 0, 0, 0},
+		{1, "a", 0, 1, 8, 0},
+		{2, "b", 0, 3, 27, 0},
+		{5, "c", 0, 7, 62, 0},
+		{7, "d", 1, 1, 8, 16},
+	}
+	for _, tc := range tcs {
+		g := glyphs[tc.i]
+		if g.Text != tc.text || g.Rune != rune(tc.text[0]) || g.Line != tc.line || g.Cluster != tc.cluster {
+			t.Errorf("glyph %d: %+v", tc.i, g)
+		}
+		if g.Box != image.Rect(tc.x, tc.y, tc.x+8, tc.y+16) || g.Pen != image.Pt(tc.x, tc.y+16) {
+			t.Errorf("glyph %d: box %v, pen %v", tc.i, g.Box, g.Pen)
+		}
+		if g.ID != 0 {
+			t.Errorf("glyph %d of a bitmap font has index %d", tc.i, g.ID)
+		}
+	}
+	if at := glyphs[0].Atlas; at != image.Rect(int(a.X), int(a.Y)+1, int(a.X)+8, int(a.Y)+17) {
+		t.Errorf("atlas %v", at)
+	}
+	if f.Sheet() != f.img {
+		t.Errorf("sheet is not the sprite sheet of the font")
+	}
+}
+
+func TestShapeParagraphsRTL(t *testing.T) {
+	f := testSheetFont()
+	f.Direction = RightToLeft
+	glyphs := f.ShapeParagraphs("aa bb cc dd", 70, ParagraphStyle{Align: AlignJustify})
+	// words of the justified line go from its right edge
+	for _, tc := range []struct {
+		i    int
+		text string
+		x    int
+	}{
+		{0, "a", 62},
+		{1, "a", 54},
+		{2, "b", 35},
+		{4, "c", 8},
+		{5, "c", 0},
+	} {
+		if g := glyphs[tc.i]; g.Text != tc.text || g.Box.Min.X != tc.x {
+			t.Errorf("glyph %d: %q at %v", tc.i, g.Text, g.Box)
+		}
+	}
+}
+
+func TestShapeParagraphsRounding(t *testing.T) {
+	f := testSheetFont()
+	f.Rounding = RoundCeil
+	// words of the justified line are 7.5 pixels apart
+	if g := f.ShapeParagraphs("aa bb cc dd", 71, ParagraphStyle{Align: AlignJustify})[2]; g.Box.Min.X != 28 {
+		t.Errorf("glyph at %v", g.Box)
+	}
+	SetDeterministic(true)
+	defer SetDeterministic(false)
+	if g := f.ShapeParagraphs("aa bb cc dd", 71, ParagraphStyle{Align: AlignJustify})[2]; g.Box.Min.X != 27 {
+		t.Errorf("deterministic glyph at %v", g.Box)
+	}
+}
+
+func TestShapeParagraphsIndex(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, fc, err := rasterizeData(context.Background(), goregular.TTF, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.subst, err = newSubstitutions(goregular.TTF, 16, fc); err != nil {
+		t.Fatal(err)
+	}
+	glyphs := f.ShapeParagraphs("Wa", 100, ParagraphStyle{})
+	if len(glyphs) != 2 {
+		t.Fatalf("glyphs %+v", glyphs)
+	}
+	for i, r := range "Wa" {
+		if id := glyphs[i].ID; int(id) != int(ttf.Index(r)) {
+			t.Errorf("index of %q is %d, expect %d", r, id, ttf.Index(r))
+		}
+	}
+	// glyphs placed by shapers are found by their glyphs
+	w := glyphs[0].ID
+	f.Shaper = idShaper(w)
+	if g := f.ShapeParagraphs("é", 100, ParagraphStyle{}); len(g) != 1 || g[0].ID != w || g[0].Rune != 'é' {
+		t.Errorf("shaped glyphs %+v", g)
+	}
+	b := glyphs[0].Box
+	if b.Empty() || glyphs[0].Pen.X != b.Min.X-int(fc.Glyphs[fc.Index('W')].LeftBearing) {
+		t.Errorf("box %v, pen %v", b, glyphs[0].Pen)
+	}
+}
+
+// idShaper places the glyph of the index for every rune.
+type idShaper sfnt.GlyphIndex
+
+func (s idShaper) Shape(f *Font, runes []rune) []ShapedGlyph {
+	out := make([]ShapedGlyph, len(runes))
+	for i := range runes {
+		out[i] = ShapedGlyph{ID: sfnt.GlyphIndex(s), Cluster: i, Advance: 10}
+	}
+	return out
+}
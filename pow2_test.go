@@ -0,0 +1,89 @@
+package glsymbol
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPow2(t *testing.T) {
+	if p := Pow2(0); p != 1 {
+		t.Errorf("Pow2(0) = %d", p)
+	}
+	if p := Pow2U64(0); p != 1 {
+		t.Errorf("Pow2U64(0) = %d", p)
+	}
+	if Pow2(1) != 1 || Pow2U64(1) != 1 {
+		t.Errorf("power of two of 1 is not 1")
+	}
+	for k := 0; k < 64; k++ {
+		p := uint64(1) << k
+		for _, tc := range []struct{ x, expect uint64 }{
+			{p - 1, p},
+			{p, p},
+			{p + 1, p << 1},
+		} {
+			if k <= 1 && tc.x == p-1 {
+				continue // 0 and 1 are checked separately
+			}
+			expect := tc.expect
+			if k == 63 && tc.x == p+1 {
+				expect = 0 // out of uint64
+			}
+			if got := Pow2U64(tc.x); got != expect {
+				t.Errorf("Pow2U64(%d) = %d, expect %d", tc.x, got, expect)
+			}
+			if 32 < k || math.MaxUint32 < tc.x {
+				continue
+			}
+			if 1<<31 < tc.x {
+				expect = 0 // out of uint32
+			}
+			if got := Pow2(uint32(tc.x)); uint64(got) != expect {
+				t.Errorf("Pow2(%d) = %d, expect %d", tc.x, got, expect)
+			}
+		}
+	}
+	if p := Pow2(math.MaxUint32); p != 0 {
+		t.Errorf("Pow2(MaxUint32) = %d", p)
+	}
+	if p := Pow2U64(math.MaxUint64); p != 0 {
+		t.Errorf("Pow2U64(MaxUint64) = %d", p)
+	}
+}
+
+func TestIsPow2(t *testing.T) {
+	// every value below 1<<20 and powers of two with neighbours above
+	next := uint32(1)
+	for x := uint32(0); x < 1<<20; x++ {
+		expect := x == next
+		if expect {
+			next <<= 1
+		}
+		if IsPow2(x) != expect {
+			t.Fatalf("IsPow2(%d) = %v", x, !expect)
+		}
+	}
+	for k := 20; k < 32; k++ {
+		p := uint32(1) << k
+		if !IsPow2(p) || IsPow2(p-1) || IsPow2(p+1) {
+			t.Errorf("IsPow2 is wrong near 1<<%d", k)
+		}
+	}
+	if IsPow2(math.MaxUint32) {
+		t.Errorf("IsPow2(MaxUint32)")
+	}
+}
+
+func TestSheetSize(t *testing.T) {
+	if w, h, err := sheetSize(300, 17); err != nil || w != 512 || h != 32 {
+		t.Errorf("size %dx%d, %v", w, h, err)
+	}
+	if w, h, err := sheetSize(0, 0); err != nil || w != 1 || h != 1 {
+		t.Errorf("size of empty sheet %dx%d, %v", w, h, err)
+	}
+	for _, s := range [][2]int64{{maxSheetSize + 1, 1}, {1, 1 << 40}, {-1, 1}} {
+		if _, _, err := sheetSize(s[0], s[1]); err == nil {
+			t.Errorf("no error for %v", s)
+		}
+	}
+}
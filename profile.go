@@ -0,0 +1,69 @@
+package glsymbol
+
+import (
+	"context"
+	"runtime/trace"
+	"time"
+)
+
+// Stage identifies a part of text processing for profiling.
+type Stage int
+
+// Stages of text processing.
+const (
+	StageRasterize Stage = iota // rasterization of glyphs into a sprite sheet
+	StageUpload                 // conversion of a sprite sheet into glyph bitmaps
+	StageLayout                 // measurement and placement of text
+	StageDraw                   // drawing of glyphs
+)
+
+var stageNames = [...]string{
+	StageRasterize: "glsymbol.rasterize",
+	StageUpload:    "glsymbol.upload",
+	StageLayout:    "glsymbol.layout",
+	StageDraw:      "glsymbol.draw",
+}
+
+func (s Stage) String() string {
+	if 0 <= s && int(s) < len(stageNames) {
+		return stageNames[s]
+	}
+	return "glsymbol.unknown"
+}
+
+// profileHook is called at the end of every stage.
+var profileHook func(stage Stage, d time.Duration)
+
+// SetProfileHook sets a function which is called at the end of every
+// stage of text processing with the stage duration, or removes the hook
+// if h is nil. The hook is called on the goroutine doing the work.
+//
+// Independently of the hook every stage is marked as a runtime/trace
+// region named by the stage, so it is visible in execution traces.
+func SetProfileHook(h func(stage Stage, d time.Duration)) {
+	profileHook = h
+}
+
+// span measures a single stage.
+type span struct {
+	stage  Stage
+	region *trace.Region
+	start  time.Time
+}
+
+// startSpan begins a stage, the result must be ended by span.end.
+func startSpan(stage Stage) (s span) {
+	s.stage = stage
+	s.region = trace.StartRegion(context.Background(), stage.String())
+	if profileHook != nil {
+		s.start = time.Now()
+	}
+	return
+}
+
+func (s span) end() {
+	s.region.End()
+	if profileHook != nil && !s.start.IsZero() {
+		profileHook(s.stage, time.Since(s.start))
+	}
+}
@@ -0,0 +1,39 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProfileHook(t *testing.T) {
+	var stages []Stage
+	SetProfileHook(func(stage Stage, d time.Duration) {
+		if d < 0 {
+			t.Errorf("negative duration")
+		}
+		stages = append(stages, stage)
+	})
+	defer SetProfileHook(nil)
+
+	sp := startSpan(StageLayout)
+	sp.end()
+	if len(stages) != 1 || stages[0] != StageLayout {
+		t.Errorf("unexpected stages: %v", stages)
+	}
+
+	SetProfileHook(nil)
+	sp = startSpan(StageDraw)
+	sp.end()
+	if len(stages) != 1 {
+		t.Errorf("hook is called after removing")
+	}
+}
+
+func TestStageString(t *testing.T) {
+	if s := StageDraw.String(); s != "glsymbol.draw" {
+		t.Errorf("unexpected name: %s", s)
+	}
+	if s := Stage(-1).String(); s != "glsymbol.unknown" {
+		t.Errorf("unexpected name: %s", s)
+	}
+}
@@ -0,0 +1,101 @@
+package glsymbol
+
+import (
+	"context"
+	"image/color"
+	"sync"
+)
+
+// A Queue holds text drawn by fonts from any goroutine until Flush
+// draws it on the goroutine holding the OpenGL context.
+//
+// OpenGL calls are only valid on the thread of the context, calls of
+// Printf from other goroutines crash or corrupt the state of drivers.
+// A font with a queue, see Font.SetQueue, never calls OpenGL in Printf
+// and PrintfColors, so they are safe on any goroutine. Other methods of
+// the font, which measure text, are not, they must not run concurrently
+// with Flush.
+//
+// A queued call keeps the string, the place, the colors of PrintfColors
+// and the Bidi and Direction of the font at the time of the call. The
+// current color and other settings of the font, such as Rounding and
+// MaxGlyphs, are read by Flush, so changes made between the call and
+// Flush apply to the queued text.
+type Queue struct {
+	mu   sync.Mutex
+	cmds []queueCmd
+}
+
+// queueCmd is a queued call of Printf or PrintfColors.
+type queueCmd struct {
+	font   *Font
+	x, y   float32
+	str    string
+	colors []color.RGBA // nil for Printf
+	opt    drawOptions  // options of the font at the call
+}
+
+// SetQueue sets the queue of Printf and PrintfColors calls of the font,
+// or draws them immediately again if q is nil. The queue must be set
+// before the font is used by other goroutines.
+func (f *Font) SetQueue(q *Queue) {
+	f.queue = q
+}
+
+// push adds the command to the queue.
+func (q *Queue) push(c queueCmd) {
+	q.mu.Lock()
+	q.cmds = append(q.cmds, c)
+	q.mu.Unlock()
+}
+
+// Len returns the amount of queued calls.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.cmds)
+}
+
+// Flush draws queued text in the order of calls and empties the queue.
+// It must be called on the goroutine holding the OpenGL context. Text is
+// drawn by the current color, or the colors of PrintfColors.
+//
+// If the context is done, Flush returns its error and keeps the text not
+// drawn yet queued for the next call. On a draw error the rest of the
+// queue is kept as well.
+func (q *Queue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	cmds := q.cmds
+	q.cmds = nil
+	q.mu.Unlock()
+
+	for i, c := range cmds {
+		if err := ctx.Err(); err != nil {
+			q.requeue(cmds[i:])
+			return err
+		}
+		if err := c.draw(); err != nil {
+			q.requeue(cmds[i+1:])
+			return err
+		}
+	}
+	return nil
+}
+
+// draw draws the text of the command.
+func (c *queueCmd) draw() error {
+	if c.colors == nil {
+		return c.font.printf(c.x, c.y, c.str, c.opt)
+	}
+	return c.font.printfColors(c.x, c.y, c.str, c.colors, c.opt)
+}
+
+// requeue puts not drawn commands before the ones queued during Flush.
+func (q *Queue) requeue(cmds []queueCmd) {
+	if len(cmds) == 0 {
+		return
+	}
+	q.mu.Lock()
+	q.cmds = append(append([]queueCmd(nil), cmds...), q.cmds...)
+	q.mu.Unlock()
+}
@@ -0,0 +1,69 @@
+package glsymbol
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"sync"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	var q Queue
+	f := testFont()
+	f.SetQueue(&q)
+
+	// no OpenGL calls are made, so goroutines need no context
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := f.Printf(float32(i), float32(j), "text"); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := f.PrintfColors(0, 0, "colors", nil); err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 801 {
+		t.Fatalf("%d calls are queued", q.Len())
+	}
+	if c := q.cmds[800]; c.str != "colors" || c.colors == nil {
+		t.Errorf("PrintfColors is queued as %+v", c)
+	}
+
+	// nothing is drawn after the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := q.Flush(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if q.Len() != 801 {
+		t.Errorf("%d calls are kept", q.Len())
+	}
+
+	q.requeue([]queueCmd{{str: "first", colors: []color.RGBA{}}})
+	if q.cmds[0].str != "first" || q.cmds[1].str != "text" {
+		t.Errorf("requeued calls are not first")
+	}
+}
+
+func TestQueueSettings(t *testing.T) {
+	var q Queue
+	f := testFont()
+	f.SetQueue(&q)
+	f.Bidi, f.Direction = BidiRTL, RightToLeft
+	if err := f.Printf(0, 0, "text"); err != nil {
+		t.Fatal(err)
+	}
+	// settings changed after the call do not change the queued text
+	f.Bidi, f.Direction = BidiOff, TopToBottom
+	if o := q.cmds[0].opt; o.bidi != BidiRTL || o.dir != RightToLeft {
+		t.Errorf("queued with options %+v", o)
+	}
+}
@@ -0,0 +1,111 @@
+package glsymbol
+
+import (
+	"context"
+	"fmt"
+)
+
+// A RuneRange is a segment of runes from Low up to High inclusive.
+type RuneRange struct {
+	Low, High rune
+}
+
+// Len returns the amount of runes in the range.
+func (rg RuneRange) Len() int {
+	return int(rg.High-rg.Low) + 1
+}
+
+// validateRanges checks that ranges are not empty, ascending and
+// do not overlap.
+func validateRanges(ranges []RuneRange) error {
+	if len(ranges) == 0 {
+		return fmt.Errorf("glsymbol: no rune ranges")
+	}
+	for i, rg := range ranges {
+		if rg.High < rg.Low {
+			return fmt.Errorf("glsymbol: high rune %q is less than low rune %q", rg.High, rg.Low)
+		}
+		if 0 < i && rg.Low <= ranges[i-1].High {
+			return fmt.Errorf("glsymbol: range %q..%q is not above range %q..%q",
+				rg.Low, rg.High, ranges[i-1].Low, ranges[i-1].High)
+		}
+	}
+	return nil
+}
+
+// newRangeConfig returns a font configuration for the ranges with
+// empty glyphs. A single range is stored in Low and High only.
+func newRangeConfig(ranges []RuneRange) (*FontConfig, error) {
+	if err := validateRanges(ranges); err != nil {
+		return nil, err
+	}
+	fc := &FontConfig{
+		Low:  ranges[0].Low,
+		High: ranges[len(ranges)-1].High,
+	}
+	n := 0
+	for _, rg := range ranges {
+		n += rg.Len()
+	}
+	if 1 < len(ranges) {
+		fc.Ranges = append([]RuneRange(nil), ranges...)
+	}
+	fc.Glyphs = make(Charset, n)
+	return fc, nil
+}
+
+// ranges returns the segments of runes of the configuration.
+func (fc *FontConfig) ranges() []RuneRange {
+	if len(fc.Ranges) == 0 {
+		return []RuneRange{{fc.Low, fc.High}}
+	}
+	return fc.Ranges
+}
+
+// Index returns the index of the glyph of the rune in Glyphs,
+// or -1 if the rune is out of the ranges of the configuration.
+func (fc *FontConfig) Index(r rune) int {
+	if len(fc.Ranges) == 0 {
+		if fc.Low <= r && r <= fc.High {
+			return int(r - fc.Low)
+		}
+		return -1
+	}
+	// ranges are few, for example ASCII, Cyrillic and arrows
+	i := 0
+	for _, rg := range fc.Ranges {
+		if r < rg.Low {
+			break
+		}
+		if r <= rg.High {
+			return i + int(r-rg.Low)
+		}
+		i += rg.Len()
+	}
+	return -1
+}
+
+// RuneAt returns the rune of the glyph with the index in Glyphs.
+// It is the inverse of Index.
+func (fc *FontConfig) RuneAt(i int) rune {
+	for _, rg := range fc.Ranges {
+		if i < rg.Len() {
+			return rg.Low + rune(i)
+		}
+		i -= rg.Len()
+	}
+	return fc.Low + rune(i)
+}
+
+// Has reports whether the rune is in the ranges of the configuration.
+func (fc *FontConfig) Has(r rune) bool {
+	return 0 <= fc.Index(r)
+}
+
+// LoadTruetypeRanges loads a truetype or OpenType font from the data as
+// LoadTruetypeBytes with glyphs of runes of the ranges only, so a font
+// for ASCII, Cyrillic and arrows does not hold every rune between them.
+// Ranges must be ascending and must not overlap.
+func LoadTruetypeRanges(data []byte, scale int32, ranges ...RuneRange) (*Font, error) {
+	return LoadTruetypeContext(context.Background(), data, scale, ranges...)
+}
@@ -0,0 +1,146 @@
+package glsymbol
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// testRangesFont returns a font of ASCII letters, Cyrillic capitals and
+// arrows. Glyphs are 8 pixels wide, arrows are 12.
+func testRangesFont(t *testing.T) *Font {
+	t.Helper()
+	config, err := newRangeConfig([]RuneRange{{'A', 'Z'}, {'А', 'Я'}, {'←', '↓'}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range config.Glyphs {
+		w := int32(8)
+		if '←' <= config.RuneAt(i) {
+			w = 12
+		}
+		config.Glyphs[i] = Glyph{Width: w, Height: 16, Advance: w}
+	}
+	return &Font{Config: config, MaxGlyphWidth: 12, MaxGlyphHeight: 16}
+}
+
+func TestRuneRanges(t *testing.T) {
+	f := testRangesFont(t)
+	c := f.Config
+	if n := 26 + 32 + 4; len(c.Glyphs) != n {
+		t.Fatalf("%d glyphs, expect %d", len(c.Glyphs), n)
+	}
+	if c.Low != 'A' || c.High != '↓' {
+		t.Errorf("range %q..%q", c.Low, c.High)
+	}
+	for i := range c.Glyphs {
+		if j := c.Index(c.RuneAt(i)); j != i {
+			t.Fatalf("index of rune %q of glyph %d is %d", c.RuneAt(i), i, j)
+		}
+	}
+	for _, r := range []rune{'@', '[', 'a', 'Ё', 'я', '↔', 0} {
+		if c.Has(r) {
+			t.Errorf("rune %q is in ranges", r)
+		}
+	}
+	if err := c.Validate(image.Rectangle{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := f.advanceSize("AЖ↑?"); w != 8+8+12 {
+		t.Errorf("advance size %d", w)
+	}
+	if p := f.MetricsBatch([]string{"AЖ↑?"})[0]; p.X != 8+8+12 {
+		t.Errorf("batch width %d", p.X)
+	}
+
+	// a single range is stored as before
+	single, err := newRangeConfig([]RuneRange{{32, 126}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.Ranges != nil || single.Low != 32 || single.High != 126 || len(single.Glyphs) != 95 {
+		t.Errorf("single range %+v", single)
+	}
+}
+
+func TestRuneRangesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		ranges []RuneRange
+		err    string
+	}{
+		{nil, "no rune ranges"},
+		{[]RuneRange{{'b', 'a'}}, "less than low"},
+		{[]RuneRange{{'a', 'z'}, {'x', 'я'}}, "is not above"},
+		{[]RuneRange{{'x', 'z'}, {'a', 'c'}}, "is not above"},
+	} {
+		if _, err := newRangeConfig(tc.ranges); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: unexpected error: %v", tc.ranges, err)
+		}
+	}
+
+	c := testRangesFont(t).Config
+	c.Low = ' '
+	if err := c.Validate(image.Rectangle{}); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	c.Low = 'A'
+	c.Glyphs = c.Glyphs[1:]
+	if err := c.Validate(image.Rectangle{}); err == nil || !strings.Contains(err.Error(), "needs 62 glyphs") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRuneRangesJSON(t *testing.T) {
+	c := testRangesFont(t).Config
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc FontConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Ranges) != 3 || fc.Ranges[1] != (RuneRange{'А', 'Я'}) || len(fc.Glyphs) != len(c.Glyphs) {
+		t.Errorf("not same: %s", data)
+	}
+
+	in := `{"ranges":[{"low":"a","high":"b"},{"low":"x","high":"x"}],"glyphs":[{},{},{"advance":3}]}`
+	if err := json.Unmarshal([]byte(in), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Low != 'a' || fc.High != 'x' || fc.Glyphs[fc.Index('x')].Advance != 3 {
+		t.Errorf("unexpected config %+v", fc)
+	}
+}
+
+func TestRasterizeOpentypeRanges(t *testing.T) {
+	otf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, all, err := rasterizeOpentype(context.Background(), otf, 16, []RuneRange{{32, 127}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, parts, err := rasterizeOpentype(context.Background(), otf, 16, []RuneRange{{'0', '9'}, {'A', 'Z'}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parts.Validate(img.Bounds()); err != nil {
+		t.Fatal(err)
+	}
+	if len(parts.Glyphs) != 36 {
+		t.Fatalf("%d glyphs", len(parts.Glyphs))
+	}
+	for _, r := range "09AZ" {
+		if a, b := all.Glyphs[all.Index(r)].Advance, parts.Glyphs[parts.Index(r)].Advance; a != b {
+			t.Errorf("advance of %q is %d, expect %d", r, b, a)
+		}
+	}
+}
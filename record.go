@@ -0,0 +1,208 @@
+package glsymbol
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A Recorder records text drawn by fonts, for example during a frame,
+// see SetRecorder. Records can be exported, for example by WriteSVG,
+// dumped by WriteJSON or drawn by DrawWireframe to find out where
+// a label went.
+//
+// A Recorder must not be used from several goroutines at once.
+type Recorder struct {
+	Records []TextRecord
+
+	groups []recordGroup
+	group  int // index of the current group plus one, zero is the root
+}
+
+// A TextRecord describes a drawn string.
+type TextRecord struct {
+	Text string
+	Font *Font
+
+	// Caller is the file and line of the call outside of the package,
+	// which drew the string.
+	Caller string
+
+	X, Y float32 // position given to the drawing function
+
+	// Box is the rectangle of glyph cells of the string in pixels of
+	// the viewport, with the top left corner at Box.X, Box.Y and Y going
+	// down independently of the origin. Baseline is the Y of the
+	// baseline in the same coordinates.
+	Box      Rect
+	Baseline float32
+
+	Color  color.RGBA   // current color
+	Colors []color.RGBA // colors of runes given to PrintfColors
+
+	viewport float32 // height of the viewport
+	group    int     // group of the record, see Recorder.group
+}
+
+// Reset removes all records, usually at the beginning of a frame.
+func (r *Recorder) Reset() {
+	r.Records = r.Records[:0]
+	r.groups = r.groups[:0]
+	r.group = 0
+}
+
+// recorder receives records of all drawn text.
+var recorder *Recorder
+
+// SetRecorder starts recording of all text drawn by fonts into r,
+// or stops recording if r is nil. Recording queries the current color
+// from OpenGL for every string, so it is meant for exports and debugging.
+func SetRecorder(r *Recorder) {
+	recorder = r
+}
+
+// record adds the string drawn at x, y to the recorder, if any.
+func (f *Font) record(x, y float32, str string, colors []color.RGBA) {
+	if recorder == nil {
+		return
+	}
+	_, vh := viewportSize()
+	rec := f.newRecord(x, y, str, float32(vh))
+	rec.Caller = caller()
+	rec.Color = currentColor()
+	rec.Colors = append([]color.RGBA(nil), colors...)
+	rec.group = recorder.group
+	recorder.Records = append(recorder.Records, rec)
+}
+
+// newRecord describes the string drawn at x, y in a viewport of
+// the given height.
+func (f *Font) newRecord(x, y float32, str string, height float32) TextRecord {
+	h := float32(f.MaxGlyphHeight)
+	top := y
+	if origin != OriginTopLeft {
+		top = height - y - h
+	}
+	return TextRecord{
+		Text:     str,
+		Font:     f,
+		X:        x,
+		Y:        y,
+		Box:      Rect{X: x, Y: top, Width: float32(f.advanceSize(str)), Height: h},
+		Baseline: top + h - float32(f.Config.Baseline),
+		viewport: height,
+	}
+}
+
+// caller returns the position of the first call from outside of
+// the package, tests of the package are outside.
+func caller() string {
+	pc := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	for {
+		fr, more := frames.Next()
+		inside := strings.HasPrefix(fr.Function, "github.com/Konstantin8105/glsymbol.") &&
+			!strings.HasSuffix(fr.File, "_test.go")
+		if !inside {
+			return fmt.Sprintf("%s:%d", fr.File, fr.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// currentColor returns the current OpenGL color.
+func currentColor() color.RGBA {
+	var c [4]float32
+	gl.GetFloatv(gl.CURRENT_COLOR, &c[0])
+	return color.RGBA{
+		R: uint8(c[0]*0xff + 0.5),
+		G: uint8(c[1]*0xff + 0.5),
+		B: uint8(c[2]*0xff + 0.5),
+		A: uint8(c[3]*0xff + 0.5),
+	}
+}
+
+// recordJSON is the JSON representation of a TextRecord.
+type recordJSON struct {
+	Text   string     `json:"text"`
+	Caller string     `json:"caller,omitempty"`
+	Font   int        `json:"font"` // index of the font in order of appearance
+	Size   float32    `json:"size"`
+	X      float32    `json:"x"`
+	Y      float32    `json:"y"`
+	Box    [4]float32 `json:"box"` // x, y, width, height
+	Color  string     `json:"color"`
+	Colors []string   `json:"colors,omitempty"`
+}
+
+// WriteJSON writes the records as a JSON array. Fonts are identified by
+// their index in order of appearance and their size.
+func (r *Recorder) WriteJSON(w io.Writer) error {
+	fonts := map[*Font]int{}
+	out := make([]recordJSON, 0, len(r.Records))
+	for _, rec := range r.Records {
+		id, ok := fonts[rec.Font]
+		if !ok {
+			id = len(fonts)
+			fonts[rec.Font] = id
+		}
+		v := recordJSON{
+			Text:   rec.Text,
+			Caller: rec.Caller,
+			Font:   id,
+			Size:   rec.Font.fontSize(),
+			X:      rec.X,
+			Y:      rec.Y,
+			Box:    [4]float32{rec.Box.X, rec.Box.Y, rec.Box.Width, rec.Box.Height},
+			Color:  hexColor(rec.Color),
+		}
+		for _, c := range rec.Colors {
+			v.Colors = append(v.Colors, hexColor(c))
+		}
+		out = append(out, v)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(out)
+}
+
+// hexColor returns the color as "#rrggbbaa".
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// DrawWireframe draws outlines of glyph cells of the records and their
+// baselines with the current color, in the coordinates set by SetupOrtho.
+func (r *Recorder) DrawWireframe() error {
+	gl.PushAttrib(gl.ENABLE_BIT)
+	gl.Disable(gl.TEXTURE_2D)
+	for _, rec := range r.Records {
+		// records are in pixels of the viewport from its top
+		y := func(v float32) float32 {
+			if origin == OriginTopLeft {
+				return v
+			}
+			return rec.viewport - v
+		}
+		b := rec.Box
+		gl.Begin(gl.LINE_LOOP)
+		gl.Vertex2f(b.X, y(b.Y))
+		gl.Vertex2f(b.X+b.Width, y(b.Y))
+		gl.Vertex2f(b.X+b.Width, y(b.Y+b.Height))
+		gl.Vertex2f(b.X, y(b.Y+b.Height))
+		gl.End()
+		gl.Begin(gl.LINES)
+		gl.Vertex2f(b.X, y(rec.Baseline))
+		gl.Vertex2f(b.X+b.Width, y(rec.Baseline))
+		gl.End()
+	}
+	gl.PopAttrib()
+	return checkGLError("DrawWireframe")
+}
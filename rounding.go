@@ -0,0 +1,31 @@
+package glsymbol
+
+import "math"
+
+// Rounding is a policy of rounding fractional pixel values, for example
+// positions of strings or line heights given by a multiplier.
+// The same policy is used by measurement, layout and drawing of a font,
+// so measured and drawn sizes do not differ by a pixel.
+type Rounding int
+
+// Rounding policies.
+const (
+	// RoundDefault, the zero value, rounds positions towards negative
+	// infinity and line heights to the nearest integer.
+	RoundDefault Rounding = iota
+	RoundFloor            // towards negative infinity
+	RoundNearest          // to the nearest integer, halves away from zero
+	RoundCeil             // towards positive infinity
+)
+
+// Round returns the value rounded to a whole pixel by the policy.
+func (r Rounding) Round(v float32) int32 {
+	switch r {
+	case RoundNearest:
+		return int32(math.Round(float64(v)))
+	case RoundCeil:
+		return int32(math.Ceil(float64(v)))
+	default:
+		return int32(math.Floor(float64(v)))
+	}
+}
@@ -0,0 +1,179 @@
+package glsymbol
+
+import (
+	"image/color"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SearchOptions controls matching of a query in text.
+type SearchOptions struct {
+	IgnoreCase       bool // "Straße" matches "STRAßE"
+	IgnoreDiacritics bool // "cafe" matches "café"
+}
+
+// fold returns the runes the rune is compared by.
+func (o SearchOptions) fold(r rune, dst []rune) []rune {
+	if !o.IgnoreDiacritics {
+		if o.IgnoreCase {
+			r = unicode.ToLower(r)
+		}
+		return append(dst, r)
+	}
+	for _, d := range norm.NFD.String(string(r)) {
+		if unicode.Is(unicode.Mn, d) {
+			continue
+		}
+		if o.IgnoreCase {
+			d = unicode.ToLower(d)
+		}
+		dst = append(dst, d)
+	}
+	return dst
+}
+
+// searchRune is a rune of laid out lines prepared for matching.
+type searchRune struct {
+	line int // index of the line, -1 for a break between lines
+	pos  int // byte offset in the line
+	end  int // byte offset after the rune in the line
+}
+
+// FindParagraphs returns the rectangles of all occurrences of the query
+// in the text laid out by LayoutParagraphs. Rectangles are relative to
+// the top left corner of the text block with Y going down, as LineBox.
+// An occurrence broken by wrapping gives a rectangle on every line.
+//
+// Matches do not overlap and do not cross paragraphs. Runs of white
+// space in the query match a single space, as words are laid out.
+func (f *Font) FindParagraphs(text string, width int32, style ParagraphStyle, query string, opt SearchOptions) []Rect {
+	lines, _ := f.LayoutParagraphs(text, width, style)
+	return f.findLines(lines, query, opt)
+}
+
+// findLines returns the rectangles of the query occurrences in lines.
+func (f *Font) findLines(lines []LineBox, query string, opt SearchOptions) []Rect {
+	var q []rune
+	for _, r := range strings.Join(strings.Fields(query), " ") {
+		q = opt.fold(r, q)
+	}
+	if len(q) == 0 {
+		return nil
+	}
+
+	// runes of every paragraph are matched as a stream, where origin
+	// maps folded runes back to the runes of lines
+	var (
+		rects  []Rect
+		folded []rune
+		origin []searchRune
+	)
+	flush := func() {
+		for i := 0; i+len(q) <= len(folded); {
+			if !equalRunes(folded[i:i+len(q)], q) {
+				i++
+				continue
+			}
+			rects = f.matchRects(rects, lines, origin[i:i+len(q)])
+			i += len(q)
+		}
+		folded, origin = folded[:0], origin[:0]
+	}
+	for li, l := range lines {
+		if l.Marker {
+			continue
+		}
+		switch l.wrap {
+		case wrapNone:
+			flush()
+		case wrapSpace:
+			folded = append(folded, ' ')
+			origin = append(origin, searchRune{line: -1})
+		}
+		for pos, r := range l.Text {
+			n := len(folded)
+			folded = opt.fold(r, folded)
+			for ; n < len(folded); n++ {
+				origin = append(origin, searchRune{line: li, pos: pos, end: pos + len(string(r))})
+			}
+		}
+	}
+	flush()
+	return rects
+}
+
+// matchRects appends the rectangles covering the runes of a match,
+// one rectangle per line.
+func (f *Font) matchRects(rects []Rect, lines []LineBox, match []searchRune) []Rect {
+	lh := float32(f.lineHeight())
+	for i := 0; i < len(match); {
+		m := match[i]
+		if m.line < 0 {
+			i++
+			continue
+		}
+		start, end := m.pos, m.end
+		for i++; i < len(match) && match[i].line == m.line; i++ {
+			end = match[i].end
+		}
+		l := lines[m.line]
+		x0, x1 := f.offsetX(l, start), f.offsetX(l, end)
+		if x1 < x0 {
+			// right-to-left line
+			x0, x1 = x1, x0
+		}
+		rects = append(rects, Rect{
+			X:      float32(l.X) + x0,
+			Y:      float32(l.Y),
+			Width:  x1 - x0,
+			Height: lh,
+		})
+	}
+	return rects
+}
+
+// offsetX returns the distance from the left edge of the laid out line
+// to the byte offset, justification included. RightToLeft lines start
+// at their right edge, as drawLines draws them.
+func (f *Font) offsetX(l LineBox, offset int) float32 {
+	head := l.Text[:offset]
+	x := float32(f.advanceSize(head)) + l.Gap*float32(strings.Count(head, " "))
+	if f.Direction == RightToLeft {
+		return float32(l.Width) + l.Gap*float32(strings.Count(l.Text, " ")) - x
+	}
+	return x
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// HighlightParagraphs draws the text as DrawParagraphs with backgrounds
+// of the color behind all occurrences of the query. A nil color means
+// the highlight color of the theme. It returns the rectangles of the
+// occurrences in drawing coordinates.
+func (f *Font) HighlightParagraphs(x, y float32, width int32, text string, style ParagraphStyle,
+	query string, opt SearchOptions, c color.Color) ([]Rect, error) {
+	if c == nil {
+		c = theme.color(ColorHighlight)
+	}
+	lines, _ := f.LayoutParagraphs(text, width, style)
+	rects := f.findLines(lines, query, opt)
+	for i, r := range rects {
+		r.X += x
+		r.Y = linePos(below(y, r.Y), r.Height)
+		fillRect(r, c)
+		rects[i] = r
+	}
+	if err := f.drawLines(x, y, lines); err != nil {
+		return rects, err
+	}
+	return rects, nil
+}
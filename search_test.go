@@ -0,0 +1,122 @@
+package glsymbol
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindParagraphs(t *testing.T) {
+	f := testFont() // 8 pixels per rune, 4 pixels space
+	for f.Config.High < 0xff {
+		// Latin-1 Supplement
+		f.Config.High++
+		f.Config.Glyphs = append(f.Config.Glyphs, Glyph{Width: 8, Height: 16, Advance: 8})
+	}
+	tcs := []struct {
+		text   string
+		width  int32
+		style  ParagraphStyle
+		query  string
+		opt    SearchOptions
+		expect string
+	}{
+		{
+			text:   "aaa bbb aaa",
+			width:  200,
+			query:  "aaa",
+			expect: "0,0 24|56,0 24|",
+		},
+		{
+			text:   "aaaa",
+			width:  200,
+			query:  "aa",
+			expect: "0,0 16|16,0 16|",
+		},
+		{
+			// broken at the space
+			text:   "aaa bbb ccc",
+			width:  70,
+			query:  "bbb  ccc",
+			expect: "28,0 24|0,16 24|",
+		},
+		{
+			// word broken between runes
+			text:   "aaaaaaaaaa b",
+			width:  30,
+			query:  "aaaa",
+			expect: "0,0 24|0,16 8|8,16 16|0,32 16|",
+		},
+		{
+			// no matches across paragraphs
+			text:   "aaa\nbbb",
+			width:  200,
+			query:  "a b",
+			expect: "",
+		},
+		{
+			text:   "AbC abc",
+			width:  200,
+			query:  "abc",
+			expect: "28,0 24|",
+		},
+		{
+			text:   "AbC abc",
+			width:  200,
+			query:  "abc",
+			opt:    SearchOptions{IgnoreCase: true},
+			expect: "0,0 24|28,0 24|",
+		},
+		{
+			text:   "cafe café",
+			width:  200,
+			query:  "CAFE",
+			opt:    SearchOptions{IgnoreCase: true, IgnoreDiacritics: true},
+			expect: "0,0 32|36,0 32|",
+		},
+		{
+			text:   "aa bb cc dd",
+			width:  70,
+			style:  ParagraphStyle{Align: AlignJustify},
+			query:  "cc",
+			expect: "54,0 16|",
+		},
+		{
+			text:   "aaa",
+			width:  200,
+			query:  " ",
+			expect: "",
+		},
+	}
+	for i, tc := range tcs {
+		var s string
+		for _, r := range f.FindParagraphs(tc.text, tc.width, tc.style, tc.query, tc.opt) {
+			if r.Height != 16 {
+				t.Errorf("case %d: height %v", i, r.Height)
+			}
+			s += fmt.Sprintf("%v,%v %v|", r.X, r.Y, r.Width)
+		}
+		if s != tc.expect {
+			t.Errorf("case %d:\n%s\n%s", i, s, tc.expect)
+		}
+	}
+}
+
+func TestFindParagraphsList(t *testing.T) {
+	f := testFont()
+	style := ParagraphStyle{List: &ListStyle{Marker: Bullet("a")}}
+	rects := f.FindParagraphs("bab", 200, style, "a", SearchOptions{})
+	// marker is not searched, gutter is 8+4 pixels
+	if len(rects) != 1 || rects[0].X != 20 {
+		t.Errorf("rects %v", rects)
+	}
+}
+
+func TestFindParagraphsRTL(t *testing.T) {
+	f := testFont()
+	f.Direction = RightToLeft
+	rects := f.FindParagraphs("aa bb", 200, ParagraphStyle{Align: AlignRight}, "bb", SearchOptions{})
+	// the line of 36 pixels starts at the right edge of the block
+	if len(rects) != 1 || rects[0].X != 164 || rects[0].Width != 16 {
+		t.Errorf("rects %v", rects)
+	}
+}
@@ -0,0 +1,181 @@
+package glsymbol
+
+import (
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// substitutions are glyphs of a truetype or OpenType font which are
+// not mapped to runes: glyphs substituting glyphs of runes by OpenType
+// features of the GSUB table, see Font.Features and Font.Ligatures, and
+// glyphs placed by a Shaper. They are rasterized from outlines by glyph
+// indexes on first use and added to the sprite sheet.
+type substitutions struct {
+	font    *sfnt.Font
+	scale   int32
+	runes   map[string]map[rune]sfnt.GlyphIndex // substitutes of glyphs of runes by features
+	ligas   map[rune][]runeLigature             // ligatures by first runes, in order of preference
+	byGlyph map[sfnt.GlyphIndex]rune            // runes of Config by their glyphs
+	glyphs  map[sfnt.GlyphIndex]*Glyph          // added glyphs, nil if not drawable
+	buf     sfnt.Buffer
+}
+
+// runeLigature is a ligature glyph substituting glyphs of runes.
+type runeLigature struct {
+	runes string
+	glyph sfnt.GlyphIndex
+}
+
+// newSubstitutions parses outlines of the truetype or OpenType font data
+// and reads single and ligature substitutions of glyphs of runes of the
+// config from its GSUB table.
+func newSubstitutions(data []byte, scale int32, fc *FontConfig) (*substitutions, error) {
+	gsub, err := sfntTable(data, "GSUB")
+	if err != nil {
+		return nil, err
+	}
+	var (
+		features map[string]map[uint16]uint16
+		ligas    []otlLigature
+	)
+	if gsub != nil {
+		if features, err = singleSubstitutions(gsub); err != nil {
+			return nil, err
+		}
+		if ligas, err = ligatureSubstitutions(gsub); err != nil {
+			return nil, err
+		}
+	}
+	otf, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	s := &substitutions{
+		font:    otf,
+		scale:   scale,
+		runes:   make(map[string]map[rune]sfnt.GlyphIndex),
+		ligas:   make(map[rune][]runeLigature),
+		byGlyph: make(map[sfnt.GlyphIndex]rune),
+		glyphs:  make(map[sfnt.GlyphIndex]*Glyph),
+	}
+	for i := range fc.Glyphs {
+		r := fc.RuneAt(i)
+		g, err := otf.GlyphIndex(&s.buf, r)
+		if err != nil || g == 0 {
+			continue
+		}
+		if _, ok := s.byGlyph[g]; !ok {
+			s.byGlyph[g] = r
+		}
+		for tag, m := range features {
+			if alt, ok := m[uint16(g)]; ok && alt != uint16(g) {
+				if s.runes[tag] == nil {
+					s.runes[tag] = make(map[rune]sfnt.GlyphIndex)
+				}
+				s.runes[tag][r] = sfnt.GlyphIndex(alt)
+			}
+		}
+	}
+ligatures:
+	for _, l := range ligas {
+		rs := make([]rune, len(l.glyphs))
+		for i, g := range l.glyphs {
+			r, ok := s.byGlyph[sfnt.GlyphIndex(g)]
+			if !ok {
+				continue ligatures
+			}
+			rs[i] = r
+		}
+		s.ligas[rs[0]] = append(s.ligas[rs[0]], runeLigature{runes: string(rs), glyph: sfnt.GlyphIndex(l.glyph)})
+	}
+	return s, nil
+}
+
+// substitutes reports whether glyphs of runes may be substituted by
+// Features.
+func (f *Font) substitutes() bool {
+	return f.subst != nil && len(f.subst.runes) != 0 && len(f.Features) != 0
+}
+
+// substitute returns the glyph substituting the glyph of the rune by
+// the first of Features which substitutes it, or nil.
+func (f *Font) substitute(r rune) *Glyph {
+	s := f.subst
+	for _, tag := range f.Features {
+		idx, ok := s.runes[tag][r]
+		if !ok {
+			continue
+		}
+		return f.substGlyph(idx)
+	}
+	return nil
+}
+
+// substGlyph returns the glyph of the index, which is added to the sprite
+// sheet on first use, or nil if it can not be drawn.
+func (f *Font) substGlyph(idx sfnt.GlyphIndex) *Glyph {
+	s := f.subst
+	if g, ok := s.glyphs[idx]; ok {
+		return g
+	}
+	img, m, err := s.rasterize(idx)
+	if err == nil {
+		top := f.cellHeight(img) - int(f.Config.Baseline) - int(m.BearingY)
+		s.glyphs[idx], err = f.addImage(img, top, int(m.BearingX), int(m.Advance))
+	}
+	if err != nil {
+		warn("glsymbol: glyph is not added", "glyph", idx, "error", err)
+		s.glyphs[idx] = nil
+	}
+	return s.glyphs[idx]
+}
+
+// rasterize returns the image and the metrics of the glyph of the index.
+func (s *substitutions) rasterize(idx sfnt.GlyphIndex) (*image.Alpha, GlyphMetrics, error) {
+	if err := faultError("rasterize"); err != nil {
+		return nil, GlyphMetrics{}, err
+	}
+	ppem := fixed.I(int(s.scale))
+	adv, err := s.font.GlyphAdvance(&s.buf, idx, ppem, font.HintingNone)
+	if err != nil {
+		return nil, GlyphMetrics{}, err
+	}
+	// segments are valid until the buffer is used again, Y goes down
+	segs, err := s.font.LoadGlyph(&s.buf, idx, ppem, nil)
+	if err != nil {
+		return nil, GlyphMetrics{}, err
+	}
+	b := segs.Bounds()
+	x0, y0 := b.Min.X.Floor(), b.Min.Y.Floor()
+	w, h := b.Max.X.Ceil()-x0, b.Max.Y.Ceil()-y0
+	img := image.NewAlpha(image.Rect(0, 0, w, h))
+	m := GlyphMetrics{BearingX: int32(x0), BearingY: int32(-y0), Advance: int32(adv.Round())}
+	if w <= 0 || h <= 0 {
+		return img, m, nil
+	}
+	ras := vector.NewRasterizer(w, h)
+	px := func(p fixed.Point26_6) (float32, float32) {
+		return float32(p.X)/64 - float32(x0), float32(p.Y)/64 - float32(y0)
+	}
+	for _, seg := range segs {
+		ax, ay := px(seg.Args[0])
+		bx, by := px(seg.Args[1])
+		cx, cy := px(seg.Args[2])
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			ras.MoveTo(ax, ay)
+		case sfnt.SegmentOpLineTo:
+			ras.LineTo(ax, ay)
+		case sfnt.SegmentOpQuadTo:
+			ras.QuadTo(ax, ay, bx, by)
+		case sfnt.SegmentOpCubeTo:
+			ras.CubeTo(ax, ay, bx, by, cx, cy)
+		}
+	}
+	ras.Draw(img, img.Bounds(), image.Opaque, image.Point{})
+	return img, m, nil
+}
@@ -0,0 +1,150 @@
+package glsymbol
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// A ShapedGlyph is a glyph placed by a Shaper. Values are in pixels.
+type ShapedGlyph struct {
+	// ID is the index of the glyph in the truetype or OpenType font data
+	// the font is loaded from. Zero, the missing glyph of fonts, stands
+	// for the glyph of Rune, which is taken as without a shaper and may
+	// be supplied by Fallback.
+	ID   sfnt.GlyphIndex
+	Rune rune
+
+	// Cluster is the index of the first rune of the glyph in runes
+	// passed to Shape. Glyphs are in the order of clusters.
+	Cluster int
+
+	// Advance is the distance the pen moves over the glyph, kerning
+	// included. The glyph is drawn at the pen position moved by XOffset
+	// right and YOffset up.
+	Advance, XOffset, YOffset int32
+}
+
+// A Shaper places glyphs of runes of a single line of text. Complex
+// scripts need shaping engines, for example a binding of HarfBuzz,
+// which glsymbol does not depend on: they are plugged in by a Shaper
+// set on the font, see Font.Shaper.
+//
+// Fonts not loaded from truetype or OpenType data have no glyph
+// indexes, their shapers place glyphs of runes. The runes may be taken
+// from Font.Arena, so shapers must not keep them after Shape returns.
+type Shaper interface {
+	Shape(f *Font, runes []rune) []ShapedGlyph
+}
+
+// SimpleShaper is the built-in Shaper, which places glyphs as fonts
+// without a shaper do: glyphs of runes substituted by Features, standard
+// Ligatures, kerning and advances set by SetAdvance. It is a reference
+// for shapers which handle a part of text themselves.
+type SimpleShaper struct{}
+
+// Shape places glyphs of the runes drawn by the font.
+func (SimpleShaper) Shape(f *Font, runes []rune) []ShapedGlyph {
+	s := string(runes)
+	out := make([]ShapedGlyph, 0, len(runes))
+	prev := rune(-1)
+	n := 0
+	for i := 0; i < len(s); {
+		c := f.cluster(s[i:])
+		// kerning moves the glyph, so it widens the glyph before it
+		if k := f.kerning(prev, c.first); k != 0 && len(out) != 0 {
+			out[len(out)-1].Advance += k
+		}
+		prev = c.last
+		out = append(out, ShapedGlyph{ID: c.id, Rune: c.form, Cluster: n, Advance: c.step})
+		n += c.runes
+		i += c.bytes
+	}
+	return out
+}
+
+// shapedGlyph returns the glyph placed by the shaper of the font.
+func (f *Font) shapedGlyph(sg ShapedGlyph) *Glyph {
+	s := f.subst
+	if sg.ID == 0 || s == nil {
+		return f.glyph(sg.Rune)
+	}
+	if r, ok := s.byGlyph[sg.ID]; ok {
+		// the shaper has substituted glyphs already
+		return &f.Config.Glyphs[f.Config.Index(r)]
+	}
+	if g := f.substGlyph(sg.ID); g != nil {
+		return g
+	}
+	return f.unknownGlyph()
+}
+
+// shape returns glyphs of the single line string placed by the shaper of
+// the font and byte offsets of runes of the string, followed by the
+// length of the string.
+func (f *Font) shape(str string) ([]ShapedGlyph, []int) {
+	n := utf8.RuneCountInString(str)
+	runes := f.Arena.Runes(n)[:0]
+	offsets := f.Arena.Ints(n + 1)[:0]
+	for i, r := range str {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(str))
+	glyphs := f.Shaper.Shape(f, runes)
+	// clusters out of the runes are dropped, glyphs of the shaper are
+	// copied only then
+	for _, g := range glyphs {
+		if 0 <= g.Cluster && g.Cluster < len(runes) {
+			continue
+		}
+		valid := make([]ShapedGlyph, 0, len(glyphs))
+		for _, g := range glyphs {
+			if 0 <= g.Cluster && g.Cluster < len(runes) {
+				valid = append(valid, g)
+			}
+		}
+		warn("glsymbol: shaper places glyphs out of the text", "dropped", len(glyphs)-len(valid))
+		return valid, offsets
+	}
+	return glyphs, offsets
+}
+
+// eachShaped calls fn for glyphs of the string placed by the shaper of
+// the font as place.
+func (f *Font) eachShaped(str string, fn func(g PlacedGlyph) bool) (int32, bool) {
+	glyphs, offsets := f.shape(str)
+	var x int32
+	for i, sg := range glyphs {
+		// runes of the cluster end where the next cluster starts
+		end := len(offsets) - 1
+		for _, next := range glyphs[i+1:] {
+			if sg.Cluster < next.Cluster {
+				end = next.Cluster
+				break
+			}
+		}
+		glyph := f.shapedGlyph(sg)
+		r, _ := utf8.DecodeRuneInString(str[offsets[sg.Cluster]:])
+		if !fn(PlacedGlyph{
+			Index: sg.Cluster, Offset: offsets[sg.Cluster], Rune: r,
+			Text: str[offsets[sg.Cluster]:offsets[end]],
+			X:    x + sg.XOffset + glyph.LeftBearing, Y: sg.YOffset,
+			Glyph: glyph, Step: sg.Advance, Data: f.data[r],
+		}) {
+			return x, false
+		}
+		x += sg.Advance
+	}
+	return x, true
+}
+
+// shapedSize returns the distance the pen moves over glyphs of the string
+// placed by the shaper of the font.
+func (f *Font) shapedSize(str string) (size int32) {
+	glyphs, _ := f.shape(str)
+	for _, g := range glyphs {
+		size += g.Advance
+	}
+	return
+}
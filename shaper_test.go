@@ -0,0 +1,96 @@
+package glsymbol
+
+import (
+	"context"
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+// placed returns glyphs placed by EachGlyph.
+func placed(f *Font, s string) []PlacedGlyph {
+	var out []PlacedGlyph
+	f.EachGlyph(s, func(g PlacedGlyph) bool {
+		out = append(out, g)
+		return true
+	})
+	return out
+}
+
+func TestSimpleShaper(t *testing.T) {
+	f := testFont()
+	f.kern = kernMap([]KernPair{{'A', 'V', -2}})
+	f.Ligatures = true
+	if err := f.AddGlyph('ﬁ', image.NewAlpha(image.Rect(0, 0, 10, 10)), GlyphMetrics{Advance: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetAdvance(' ', 12); err != nil {
+		t.Fatal(err)
+	}
+	const s = "AVfi x"
+	expect, width := placed(f, s), f.advanceSize(s)
+	f.Shaper = SimpleShaper{}
+	got := placed(f, s)
+	// kerning widens the glyph before the pair
+	expect[0].Step -= 2
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("glyphs\n%+v\nexpect\n%+v", got, expect)
+	}
+	if w := f.advanceSize(s); w != width {
+		t.Errorf("width %d, expect %d", w, width)
+	}
+}
+
+// testShaper places the glyph of the index for the first rune and two
+// glyphs of runes for the second rune.
+type testShaper struct{ id sfnt.GlyphIndex }
+
+func (s testShaper) Shape(f *Font, runes []rune) []ShapedGlyph {
+	return []ShapedGlyph{
+		{ID: s.id, Cluster: 0, Advance: 10},
+		{Rune: runes[1], Cluster: 1, Advance: 6},
+		{Rune: '.', Cluster: 1, Advance: 3, XOffset: -2, YOffset: 4},
+		{Cluster: 7}, // out of the text
+	}
+}
+
+func TestShaper(t *testing.T) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, fc, err := rasterizeData(context.Background(), goregular.TTF, 16, ASCII, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{Config: fc, img: img, MaxGlyphHeight: fc.Glyphs[0].Height}
+	if f.subst, err = newSubstitutions(goregular.TTF, 16, fc); err != nil {
+		t.Fatal(err)
+	}
+	// the glyph of 'W' is taken from the sheet
+	f.Shaper = testShaper{id: sfnt.GlyphIndex(ttf.Index('W'))}
+	got := placed(f, "éa")
+	if len(got) != 3 {
+		t.Fatalf("glyphs %+v", got)
+	}
+	if got[0].Glyph != &fc.Glyphs[fc.Index('W')] || got[0].Text != "é" || got[0].Rune != 'é' {
+		t.Errorf("first glyph %+v", got[0])
+	}
+	dot := fc.Glyphs[fc.Index('.')]
+	if g := got[2]; g.Index != 1 || g.Offset != 2 || g.Text != "a" || g.Y != 4 || g.X != 16-2+dot.LeftBearing {
+		t.Errorf("third glyph %+v", g)
+	}
+	if w := f.advanceSize("éa"); w != 19 {
+		t.Errorf("width %d", w)
+	}
+
+	// glyphs not mapped to runes are rasterized
+	f.Shaper = testShaper{id: sfnt.GlyphIndex(ttf.Index('é'))}
+	if g := placed(f, "ab")[0].Glyph; g == f.unknownGlyph() {
+		t.Errorf("glyph of the index is not drawn")
+	}
+}
@@ -0,0 +1,237 @@
+package glsymbol
+
+import (
+	"image/color"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"golang.org/x/text/language"
+)
+
+// A Span is a part of a line of rich text: a string or an inline icon.
+type Span struct {
+	Text string
+	Icon *Icon // if not nil, the icon is drawn instead of Text
+
+	// Link, if not empty, identifies the target of a link, for example
+	// a URL. Neighbour spans with the same link form a single link.
+	Link string
+
+	// Lang is the language of Text, which selects language specific
+	// variants of glyphs, see Font.Localize. The zero value is the
+	// undetermined language.
+	Lang language.Tag
+}
+
+// IconAlign is a vertical alignment of an icon relative to text.
+type IconAlign int
+
+// Vertical alignments of icons.
+const (
+	IconBaseline   IconAlign = iota // bottom of icon on the baseline
+	IconCenter                      // centered in glyph cells
+	IconCellBottom                  // bottom of icon at the bottom of glyph cells
+)
+
+// An Icon is a region of an OpenGL texture drawn inline with text,
+// for example a gamepad button in "press [A] to continue".
+type Icon struct {
+	Texture uint32 // texture name
+
+	// U0, V0 are texture coordinates of the top left corner of
+	// the region, U1, V1 of the bottom right corner.
+	U0, V0, U1, V1 float32
+
+	Width, Height int32 // size on screen in pixels, Width is the advance
+	Align         IconAlign
+}
+
+// A Rect is a rectangle in the coordinates of drawing functions.
+// X, Y is the bottom left corner, or the top left corner with
+// OriginTopLeft, so the rectangle grows along the Y axis in both cases.
+type Rect struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// Contains reports whether the point is inside of the rectangle.
+func (r Rect) Contains(x, y float32) bool {
+	return r.X <= x && x < r.X+r.Width && r.Y <= y && y < r.Y+r.Height
+}
+
+// union returns the smallest rectangle holding both rectangles.
+func (r Rect) union(o Rect) Rect {
+	x0, y0 := min32(r.X, o.X), min32(r.Y, o.Y)
+	x1, y1 := max32(r.X+r.Width, o.X+o.Width), max32(r.Y+r.Height, o.Y+o.Height)
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+// A LinkRegion is the place of a link span on the screen.
+type LinkRegion struct {
+	Link string
+	Span int // index of the span
+	Rect Rect
+}
+
+// LayoutSpans returns the regions of link spans of a line drawn
+// at x, y by DrawSpans, for hover and click handling.
+// Text spans are as high as glyph cells, icons as high as the icon.
+func (f *Font) LayoutSpans(x, y float32, spans []Span) (links []LinkRegion) {
+	for i, r := range f.layoutSpans(x, y, spans) {
+		if spans[i].Link != "" {
+			links = append(links, LinkRegion{Link: spans[i].Link, Span: i, Rect: r})
+		}
+	}
+	return
+}
+
+// LinkAt returns the link of regions at the point, or an empty string.
+func LinkAt(links []LinkRegion, x, y float32) string {
+	for _, l := range links {
+		if l.Rect.Contains(x, y) {
+			return l.Link
+		}
+	}
+	return ""
+}
+
+// layoutSpans places spans of a line drawn at x, y.
+// The coordinates are the same as for Printf.
+func (f *Font) layoutSpans(x, y float32, spans []Span) []Rect {
+	boxes := make([]Rect, len(spans))
+	for i, s := range spans {
+		b := Rect{X: x, Y: y, Height: float32(f.MaxGlyphHeight)}
+		if s.Icon == nil {
+			b.Width = float32(f.advanceSize(f.localize(s.Text, s.Lang)))
+		} else {
+			b.Width = float32(s.Icon.Width)
+			b.Height = float32(s.Icon.Height)
+			// distance from the bottom of cells to the bottom of icon
+			var lift float32
+			switch s.Icon.Align {
+			case IconBaseline:
+				lift = float32(f.Config.Baseline)
+			case IconCenter:
+				lift = float32(f.MaxGlyphHeight-s.Icon.Height) / 2
+			}
+			bottom := above(cellBottom(y, float32(f.MaxGlyphHeight)), lift)
+			b.Y = linePos(above(bottom, b.Height), b.Height)
+		}
+		boxes[i] = b
+		x += b.Width
+	}
+	return boxes
+}
+
+// SpansWidth returns the advance size of spans.
+func (f *Font) SpansWidth(spans []Span) (width int32) {
+	for _, s := range spans {
+		if s.Icon != nil {
+			width += s.Icon.Width
+		} else {
+			width += f.advanceSize(f.localize(s.Text, s.Lang))
+		}
+	}
+	return
+}
+
+// LinkStyle describes drawing of link spans.
+type LinkStyle struct {
+	Underline bool // underline links with the current color
+
+	// Hover is the link under the pointer, see LinkAt.
+	// Its spans are drawn over a background of the Highlight color.
+	Hover     string
+	Highlight color.Color
+}
+
+// DrawSpans draws a line of spans, the coordinates are the same as for
+// Printf. Icons are drawn with their texture colors, text with the
+// current color. Links are decorated by the theme, see SetTheme.
+func (f *Font) DrawSpans(x, y float32, spans []Span) error {
+	return f.DrawLinks(x, y, spans, theme.LinkStyle(""))
+}
+
+// DrawLinks draws a line of spans as DrawSpans and decorates
+// link spans according to the style.
+func (f *Font) DrawLinks(x, y float32, spans []Span, style LinkStyle) error {
+	boxes := f.layoutSpans(x, y, spans)
+	for i, s := range spans {
+		b := boxes[i]
+		if s.Link != "" && s.Link == style.Hover && style.Highlight != nil {
+			fillRect(b, style.Highlight)
+		}
+		if s.Icon == nil {
+			text := f.localize(s.Text, s.Lang)
+			if err := f.Printf(b.X+float32(f.anchor(int32(b.Width))), b.Y, text); err != nil {
+				return err
+			}
+		} else {
+			drawIcon(s.Icon, b.X, b.Y)
+		}
+		if s.Link != "" && style.Underline {
+			// one pixel line just below the baseline
+			bottom := above(cellBottom(y, float32(f.MaxGlyphHeight)), float32(f.Config.Baseline-1))
+			underline := Rect{X: b.X, Y: linePos(bottom, 1), Width: b.Width, Height: 1}
+			fillRect(underline, nil)
+		}
+	}
+	return checkGLError("DrawLinks")
+}
+
+// fillRect fills the rectangle with the color, or with the current
+// color if c is nil.
+func fillRect(r Rect, c color.Color) {
+	gl.PushAttrib(gl.CURRENT_BIT | gl.ENABLE_BIT)
+	defer gl.PopAttrib()
+
+	if c != nil {
+		setColor(c)
+	}
+	gl.Disable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Rectf(r.X, r.Y, r.X+r.Width, r.Y+r.Height)
+}
+
+// drawIcon draws the icon as a textured quad with the bottom left corner,
+// or the top left corner with OriginTopLeft, at x, y.
+func drawIcon(icon *Icon, x, y float32) {
+	gl.PushAttrib(gl.CURRENT_BIT | gl.ENABLE_BIT | gl.TEXTURE_BIT)
+	defer gl.PopAttrib()
+
+	gl.Color4f(1, 1, 1, 1)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindTexture(gl.TEXTURE_2D, icon.Texture)
+
+	w, h := float32(icon.Width), float32(icon.Height)
+	bottom := cellBottom(y, h)
+	top := above(bottom, h)
+	gl.Begin(gl.QUADS)
+	gl.TexCoord2f(icon.U0, icon.V1)
+	gl.Vertex2f(x, bottom)
+	gl.TexCoord2f(icon.U1, icon.V1)
+	gl.Vertex2f(x+w, bottom)
+	gl.TexCoord2f(icon.U1, icon.V0)
+	gl.Vertex2f(x+w, top)
+	gl.TexCoord2f(icon.U0, icon.V0)
+	gl.Vertex2f(x, top)
+	gl.End()
+}
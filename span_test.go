@@ -0,0 +1,78 @@
+package glsymbol
+
+import "testing"
+
+func TestLayoutSpans(t *testing.T) {
+	f := testFont()
+	f.Config.Baseline = 4
+	icon := &Icon{Width: 10, Height: 8}
+	spans := []Span{{Text: "ab"}, {Icon: icon}, {Text: "i"}}
+	if w := f.SpansWidth(spans); w != 8+8+10+4 {
+		t.Fatalf("width %d", w)
+	}
+
+	for _, tc := range []struct {
+		origin Origin
+		align  IconAlign
+		y      float32
+	}{
+		{OriginBottomLeft, IconBaseline, 104},
+		{OriginBottomLeft, IconCenter, 104},
+		{OriginBottomLeft, IconCellBottom, 100},
+		{OriginTopLeft, IconBaseline, 104},
+		{OriginTopLeft, IconCenter, 104},
+		{OriginTopLeft, IconCellBottom, 108},
+	} {
+		SetOrigin(tc.origin)
+		icon.Align = tc.align
+		boxes := f.layoutSpans(20, 100, spans)
+		SetOrigin(OriginBottomLeft)
+
+		expect := []Rect{{20, 100, 16, 16}, {36, tc.y, 10, 8}, {46, 100, 4, 16}}
+		for i := range expect {
+			if boxes[i] != expect[i] {
+				t.Errorf("origin %d, align %d: span %d: %v != %v",
+					tc.origin, tc.align, i, boxes[i], expect[i])
+			}
+		}
+	}
+}
+
+func TestLayoutLinks(t *testing.T) {
+	f := testFont()
+	spans := []Span{
+		{Text: "see "},
+		{Text: "docs", Link: "https://example.com"},
+		{Text: " or "},
+		{Icon: &Icon{Width: 10, Height: 16}, Link: "help"},
+	}
+	links := f.LayoutSpans(0, 0, spans)
+	expect := []LinkRegion{
+		{Link: "https://example.com", Span: 1, Rect: Rect{28, 0, 32, 16}},
+		{Link: "help", Span: 3, Rect: Rect{84, 0, 10, 16}},
+	}
+	if len(links) != len(expect) {
+		t.Fatalf("links %v", links)
+	}
+	for i := range expect {
+		if links[i] != expect[i] {
+			t.Errorf("link %d: %v != %v", i, links[i], expect[i])
+		}
+	}
+
+	for _, tc := range []struct {
+		x, y float32
+		link string
+	}{
+		{0, 0, ""},
+		{28, 0, "https://example.com"},
+		{59, 15, "https://example.com"},
+		{60, 0, ""},
+		{90, 8, "help"},
+		{90, 16, ""},
+	} {
+		if l := LinkAt(links, tc.x, tc.y); l != tc.link {
+			t.Errorf("link at %v,%v is %q, expect %q", tc.x, tc.y, l, tc.link)
+		}
+	}
+}
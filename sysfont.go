@@ -0,0 +1,162 @@
+package glsymbol
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Style is a style of a font face for FindSystemFont.
+type Style int
+
+// Styles of font faces, StyleBold|StyleItalic is a bold italic face.
+const (
+	StyleRegular Style = 0
+	StyleBold    Style = 1 << iota
+	StyleItalic
+)
+
+// styleSuffixes are endings of font file names of the styles, as in
+// "DejaVuSans-BoldOblique.ttf" or "arialbi.ttf", after removal of
+// separators.
+var styleSuffixes = map[Style][]string{
+	StyleRegular:            {"", "regular", "book", "roman", "r"},
+	StyleBold:               {"bold", "bd", "b"},
+	StyleItalic:             {"italic", "oblique", "it", "i"},
+	StyleBold | StyleItalic: {"bolditalic", "boldoblique", "bi", "z"},
+}
+
+// fontExts are extensions of font files FindSystemFont returns.
+var fontExts = []string{".ttf", ".otf", ".ttc"}
+
+// FindSystemFont returns the path of an installed font file of the
+// family name and style, for LoadTruetype or LoadTruetypeCollection.
+//
+// On Linux fontconfig is asked by fc-match, if it is installed. Then
+// font directories of the system and of the user are searched by file
+// names: /usr/share/fonts and ~/.local/share/fonts on Linux, the Fonts
+// folders of Windows and the Library/Fonts folders of macOS. Names are
+// compared without case, spaces, '-' and '_'.
+func FindSystemFont(name string, style Style) (string, error) {
+	if runtime.GOOS == "linux" {
+		if path, ok := fcMatch(name, style); ok {
+			return path, nil
+		}
+	}
+	return findFont(systemFontDirs(), name, style)
+}
+
+// systemFontDirs returns font directories of the operating system.
+func systemFontDirs() (dirs []string) {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		windir := os.Getenv("WINDIR")
+		if windir == "" {
+			windir = `C:\Windows`
+		}
+		dirs = append(dirs, filepath.Join(windir, "Fonts"))
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+	case "darwin", "ios":
+		dirs = append(dirs, "/System/Library/Fonts", "/Library/Fonts")
+		if home != "" {
+			dirs = append(dirs, filepath.Join(home, "Library", "Fonts"))
+		}
+	default:
+		if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+			dirs = append(dirs, filepath.Join(data, "fonts"))
+		}
+		if home != "" {
+			dirs = append(dirs, filepath.Join(home, ".local", "share", "fonts"), filepath.Join(home, ".fonts"))
+		}
+		dirs = append(dirs, "/usr/local/share/fonts", "/usr/share/fonts")
+	}
+	return
+}
+
+// fcMatch asks fontconfig for the font. Fontconfig substitutes unknown
+// families, so the result is used only for the same family.
+func fcMatch(name string, style Style) (string, bool) {
+	pattern := name
+	switch style {
+	case StyleBold:
+		pattern += ":bold"
+	case StyleItalic:
+		pattern += ":italic"
+	case StyleBold | StyleItalic:
+		pattern += ":bold:italic"
+	}
+	out, err := exec.Command("fc-match", "--format=%{family}\n%{file}", pattern).Output()
+	if err != nil {
+		return "", false
+	}
+	lines := strings.SplitN(string(bytes.TrimSpace(out)), "\n", 2)
+	if len(lines) != 2 || !hasFontExt(lines[1]) {
+		return "", false
+	}
+	// family may list a few names, as "DejaVu Sans,DejaVu Sans Condensed"
+	for _, family := range strings.Split(lines[0], ",") {
+		if fontKey(family) == fontKey(name) {
+			return lines[1], true
+		}
+	}
+	return "", false
+}
+
+// findFont searches the directories for a font file of the family name
+// and style. Earlier directories take precedence.
+func findFont(dirs []string, name string, style Style) (string, error) {
+	key := fontKey(name)
+	suffixes := styleSuffixes[style&(StyleBold|StyleItalic)]
+	for _, dir := range dirs {
+		best, rank := "", len(suffixes)
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !hasFontExt(path) {
+				return nil
+			}
+			base := fontKey(strings.TrimSuffix(d.Name(), filepath.Ext(path)))
+			if !strings.HasPrefix(base, key) {
+				return nil
+			}
+			for i, s := range suffixes[:rank] {
+				if base == key+s {
+					best, rank = path, i
+					break
+				}
+			}
+			return nil
+		})
+		if best != "" {
+			return best, nil
+		}
+	}
+	return "", fmt.Errorf("glsymbol: font %q is not found", name)
+}
+
+// fontKey returns the name in lower case without separators.
+func fontKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+func hasFontExt(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range fontExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
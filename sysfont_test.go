@@ -0,0 +1,60 @@
+package glsymbol
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindFont(t *testing.T) {
+	dir, user := t.TempDir(), t.TempDir()
+	for _, name := range []string{
+		"arial.ttf", "arialbd.ttf", "ariali.ttf", "arialbi.ttf",
+		"truetype/dejavu/DejaVuSans.ttf",
+		"truetype/dejavu/DejaVuSans-Bold.ttf",
+		"truetype/dejavu/DejaVuSans-BoldOblique.ttf",
+		"truetype/dejavu/DejaVuSansMono.ttf",
+		"noto/NotoSansCJK-Regular.ttc",
+		"misc/fixed.pcf.gz",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(user, "Arial.otf"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		style  Style
+		dirs   []string
+		expect string // relative to the first directory, empty for error
+	}{
+		{"Arial", StyleRegular, []string{dir}, "arial.ttf"},
+		{"arial", StyleBold, []string{dir}, "arialbd.ttf"},
+		{"Arial", StyleBold | StyleItalic, []string{dir}, "arialbi.ttf"},
+		{"DejaVu Sans", StyleRegular, []string{dir}, "truetype/dejavu/DejaVuSans.ttf"},
+		{"DejaVu Sans", StyleBold | StyleItalic, []string{dir}, "truetype/dejavu/DejaVuSans-BoldOblique.ttf"},
+		{"DejaVu Sans Mono", StyleRegular, []string{dir}, "truetype/dejavu/DejaVuSansMono.ttf"},
+		{"Noto Sans CJK", StyleRegular, []string{dir}, "noto/NotoSansCJK-Regular.ttc"},
+		{"DejaVu Sans", StyleItalic, []string{dir}, ""},
+		{"fixed", StyleRegular, []string{dir}, ""},
+		{"Arial", StyleRegular, []string{user, dir}, "Arial.otf"},
+	} {
+		path, err := findFont(tc.dirs, tc.name, tc.style)
+		if tc.expect == "" {
+			if err == nil {
+				t.Errorf("%q %d: found %s", tc.name, tc.style, path)
+			}
+			continue
+		}
+		if expect := filepath.Join(tc.dirs[0], tc.expect); err != nil || path != expect {
+			t.Errorf("%q %d: %s, %v, expect %s", tc.name, tc.style, path, err, expect)
+		}
+	}
+}
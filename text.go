@@ -0,0 +1,378 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Text is a multi-line string with cached layout for the font.
+// Lines are broken by the policy of SetNewlinePolicy and separated by
+// '\n' in the text returned by String.
+//
+// Edits by InsertAt and DeleteRange measure only the lines they change,
+// so editors do not lay out the whole buffer on every keystroke.
+// Offsets are byte offsets into the string returned by String.
+//
+// Edits are undone by Undo word by word, as edits of Input. Undo and
+// Redo change only the lines of the edits, as InsertAt and DeleteRange.
+type Text struct {
+	// Clock is the time of grouping of edits for Undo, SystemClock if nil.
+	Clock Clock
+
+	font  *Font
+	lines []textLine
+
+	width      int32 // cached width of the widest line
+	widthDirty bool
+
+	identity uint64 // identity of the font lines are measured with
+
+	history history
+}
+
+// textLine is a single line of Text.
+type textLine struct {
+	start int    // byte offset of the line in the text
+	text  string // line without the line break
+	width int32  // advance size of the line
+}
+
+// NewText creates a text laid out with the font.
+func NewText(f *Font, s string) *Text {
+	t := &Text{font: f, identity: f.Identity()}
+	t.lines = t.split(0, s)
+	t.widthDirty = true
+	return t
+}
+
+// remeasure measures all lines again if glyphs of the font changed.
+func (t *Text) remeasure() {
+	if id := t.font.Identity(); id != t.identity {
+		for i := range t.lines {
+			t.lines[i].width = t.font.advanceSize(t.lines[i].text)
+		}
+		t.identity = id
+		t.widthDirty = true
+	}
+}
+
+// split lays out the string placed at the offset into lines. Line
+// breaks are normalized to '\n', see SetNewlinePolicy.
+func (t *Text) split(offset int, s string) (lines []textLine) {
+	for _, line := range splitLines(s) {
+		lines = append(lines, textLine{
+			start: offset,
+			text:  line,
+			width: t.font.advanceSize(line),
+		})
+		offset += len(line) + 1
+	}
+	return
+}
+
+// String returns the whole text.
+func (t *Text) String() string {
+	var b strings.Builder
+	for i, l := range t.lines {
+		if 0 < i {
+			b.WriteByte('\n')
+		}
+		b.WriteString(l.text)
+	}
+	return b.String()
+}
+
+// Len returns the length of the text in bytes.
+func (t *Text) Len() int {
+	last := t.lines[len(t.lines)-1]
+	return last.start + len(last.text)
+}
+
+// Lines returns the amount of lines.
+func (t *Text) Lines() int {
+	return len(t.lines)
+}
+
+// Line returns the line with the index without the line break.
+func (t *Text) Line(i int) string {
+	return t.lines[i].text
+}
+
+// LineStart returns the offset of the line with the index.
+func (t *Text) LineStart(i int) int {
+	return t.lines[i].start
+}
+
+// LineAt returns the index of the line holding the offset.
+func (t *Text) LineAt(offset int) int {
+	i := sort.Search(len(t.lines), func(i int) bool {
+		return offset < t.lines[i].start
+	})
+	if i == 0 {
+		return 0
+	}
+	return i - 1
+}
+
+// Size returns the width of the widest line and the height of all lines.
+func (t *Text) Size() (width, height int32) {
+	t.remeasure()
+	if t.widthDirty {
+		t.width = 0
+		for _, l := range t.lines {
+			if t.width < l.width {
+				t.width = l.width
+			}
+		}
+		t.widthDirty = false
+	}
+	return t.width, int32(len(t.lines)) * t.font.lineHeight()
+}
+
+// checkOffset returns an error if the offset is not a rune boundary
+// inside of the text.
+func (t *Text) checkOffset(offset int) error {
+	if offset < 0 || t.Len() < offset {
+		return fmt.Errorf("glsymbol: offset %d is out of text of length %d", offset, t.Len())
+	}
+	l := t.lines[t.LineAt(offset)]
+	if pos := offset - l.start; pos < len(l.text) && !utf8.RuneStart(l.text[pos]) {
+		return fmt.Errorf("glsymbol: offset %d is inside of a rune", offset)
+	}
+	return nil
+}
+
+// InsertAt inserts the string at the offset. Line breaks of the string
+// are normalized to '\n', see SetNewlinePolicy.
+func (t *Text) InsertAt(offset int, s string) error {
+	if err := t.checkOffset(offset); err != nil {
+		return err
+	}
+	// the edit is recorded as it is laid out, so undo finds its bytes
+	s = strings.Join(splitLines(s), "\n")
+	if s == "" {
+		return nil
+	}
+	t.edit(textEdit{offset: offset, inserted: s})
+	return nil
+}
+
+// DeleteRange removes the bytes from start up to end.
+func (t *Text) DeleteRange(start, end int) error {
+	if err := t.checkOffset(start); err != nil {
+		return err
+	}
+	if err := t.checkOffset(end); err != nil {
+		return err
+	}
+	if end < start {
+		return fmt.Errorf("glsymbol: invalid range %d..%d", start, end)
+	}
+	if start == end {
+		return nil
+	}
+	t.edit(textEdit{offset: start, removed: t.slice(start, end)})
+	return nil
+}
+
+// slice returns the bytes from start up to end, taken from their lines
+// only.
+func (t *Text) slice(start, end int) string {
+	first, last := t.LineAt(start), t.LineAt(end)
+	texts := make([]string, 0, last-first+1)
+	for _, l := range t.lines[first : last+1] {
+		texts = append(texts, l.text)
+	}
+	base := t.lines[first].start
+	return strings.Join(texts, "\n")[start-base : end-base]
+}
+
+// edit records the edit for Undo and applies it.
+func (t *Text) edit(e textEdit) {
+	t.history.record(e, now(t.Clock))
+	t.apply(e)
+}
+
+// apply replaces lines changed by the edit.
+func (t *Text) apply(e textEdit) {
+	start, end := e.offset, e.offset+len(e.removed)
+	first, last := t.LineAt(start), t.LineAt(end)
+	fl, ll := t.lines[first], t.lines[last]
+	t.replace(first, last+1, fl.text[:start-fl.start]+e.inserted+ll.text[end-ll.start:])
+}
+
+// Undo reverts the last group of edits and returns the offset after
+// the reverted text, the place of the caret of editors, or false if
+// there is nothing to undo.
+func (t *Text) Undo() (int, bool) {
+	edits, ok := t.history.undo()
+	return t.applyEdits(edits), ok
+}
+
+// Redo makes the last undone group of edits again and returns the
+// offset after them, or false if there is nothing to redo.
+func (t *Text) Redo() (int, bool) {
+	edits, ok := t.history.redo()
+	return t.applyEdits(edits), ok
+}
+
+// applyEdits applies the edits without recording them and returns the
+// offset after the text inserted by the last one.
+func (t *Text) applyEdits(edits []textEdit) (offset int) {
+	for _, e := range edits {
+		t.apply(e)
+		offset = e.offset + len(e.inserted)
+	}
+	return offset
+}
+
+// replace lays out the string in place of lines from first up to end.
+func (t *Text) replace(first, end int, s string) {
+	t.remeasure()
+	lines := t.split(t.lines[first].start, s)
+	for _, l := range t.lines[first:end] {
+		if l.width == t.width {
+			// the widest line may be removed
+			t.widthDirty = true
+		}
+	}
+	for _, l := range lines {
+		if t.width < l.width {
+			t.width = l.width
+		}
+	}
+
+	// shift offsets of lines after the change
+	shift := 0
+	if end < len(t.lines) {
+		last := lines[len(lines)-1]
+		shift = last.start + len(last.text) + 1 - t.lines[end].start
+	}
+	tail := t.lines[end:]
+	for k := range tail {
+		tail[k].start += shift
+	}
+
+	t.lines = append(t.lines[:first], append(lines, tail...)...)
+}
+
+// OffsetAt returns the offset of the rune boundary nearest to the point
+// given relative to the top left corner of the text, with Y going down.
+// Points outside of the text select the nearest line.
+func (t *Text) OffsetAt(dx, dy float32) int {
+	l := t.lines[t.LineAtY(dy)]
+	offset := len(l.text)
+	// glyphs are placed once with kerning and ligatures, a glyph spans
+	// from its pen to the pen of the next one and runes of a ligature
+	// share it evenly
+	var prev PlacedGlyph
+	var from float32
+	found := func(to float32) bool {
+		n := utf8.RuneCountInString(prev.Text)
+		pos := prev.Offset
+		for k := 0; k < n; k++ {
+			if dx < from+(to-from)*float32(2*k+1)/float32(2*n) {
+				offset = pos
+				return true
+			}
+			_, size := utf8.DecodeRuneInString(l.text[pos:])
+			pos += size
+		}
+		return false
+	}
+	end, all := t.font.placeLTR(l.text, t.font.options(), func(g PlacedGlyph) bool {
+		pen := float32(g.X - g.Glyph.LeftBearing)
+		if found(pen) {
+			return false
+		}
+		prev, from = g, pen
+		return true
+	})
+	if all {
+		found(float32(end))
+	}
+	return l.start + offset
+}
+
+// LineAtY returns the index of the line at the distance dy below
+// the top edge of the text. Points outside of the text select
+// the nearest line.
+func (t *Text) LineAtY(dy float32) int {
+	i := 0
+	if lh := t.font.lineHeight(); 0 < lh && 0 < dy {
+		i = int(dy) / int(lh)
+	}
+	if len(t.lines) <= i {
+		i = len(t.lines) - 1
+	}
+	return i
+}
+
+// LineRect returns the rectangle of the line with the index for the text
+// drawn with the top edge at y. The rectangle is width wide, zero width
+// means the width of the widest line. Points of the rectangle select
+// the line by OffsetAt and LineAtY.
+func (t *Text) LineRect(i int, x, y, width float32) Rect {
+	lh := float32(t.font.lineHeight())
+	if width == 0 {
+		w, _ := t.Size()
+		width = float32(w)
+	}
+	return Rect{X: x, Y: linePos(below(y, float32(i)*lh), lh), Width: width, Height: lh}
+}
+
+// CaretPos returns the position of the caret at the offset relative to
+// the top left corner of the text, with Y going down. It is the inverse
+// of OffsetAt.
+func (t *Text) CaretPos(offset int) (dx, dy int32, err error) {
+	if err = t.checkOffset(offset); err != nil {
+		return
+	}
+	i := t.LineAt(offset)
+	l := t.lines[i]
+	return t.font.advanceSize(l.text[:offset-l.start]), int32(i) * t.font.lineHeight(), nil
+}
+
+// LineStyle describes backgrounds of lines drawn by Text.DrawStyled.
+type LineStyle struct {
+	// Width of backgrounds, zero means the width of the widest line.
+	Width float32
+
+	// Stripe, if not nil, fills every odd line for alternating rows.
+	Stripe color.Color
+
+	// Highlight, if not nil, fills the line with the index Current,
+	// over the stripe.
+	Highlight color.Color
+	Current   int
+}
+
+// DrawStyled draws the text as Draw over backgrounds of lines,
+// see LineStyle. Backgrounds are rectangles of LineRect.
+func (t *Text) DrawStyled(x, y float32, style LineStyle) error {
+	if style.Stripe != nil {
+		for i := 1; i < len(t.lines); i += 2 {
+			fillRect(t.LineRect(i, x, y, style.Width), style.Stripe)
+		}
+	}
+	if style.Highlight != nil && 0 <= style.Current && style.Current < len(t.lines) {
+		fillRect(t.LineRect(style.Current, x, y, style.Width), style.Highlight)
+	}
+	return t.Draw(x, y)
+}
+
+// Draw draws the text. The top edge of the first line is at y,
+// next lines are placed below with the line height of the font.
+func (t *Text) Draw(x, y float32) error {
+	lh := float32(t.font.lineHeight())
+	for _, l := range t.lines {
+		if err := t.font.Printf(x+float32(t.font.anchor(l.width)), t.font.lineY(y), l.text); err != nil {
+			return err
+		}
+		y = below(y, lh)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package glsymbol
+
+import (
+	"strings"
+	"testing"
+)
+
+// testFont returns a font without bitmaps for tests of layout.
+// Glyphs of runes 32..126 are 8x16 with advance 8, except 'i' and ' '
+// which are 4 pixels wide.
+func testFont() *Font {
+	config := &FontConfig{Low: 32, High: 126}
+	for r := config.Low; r <= config.High; r++ {
+		w := int32(8)
+		if r == 'i' || r == ' ' {
+			w = 4
+		}
+		config.Glyphs = append(config.Glyphs, Glyph{Width: w, Height: 16, Advance: w})
+	}
+	return &Font{Config: config, MaxGlyphWidth: 8, MaxGlyphHeight: 16}
+}
+
+func TestText(t *testing.T) {
+	f := testFont()
+	text := NewText(f, "one\ntwo\nthree")
+	check := func(expect string) {
+		t.Helper()
+		if s := text.String(); s != expect {
+			t.Fatalf("text is %q, expect %q", s, expect)
+		}
+		ref := NewText(f, expect)
+		if text.Lines() != ref.Lines() {
+			t.Fatalf("lines: %d != %d", text.Lines(), ref.Lines())
+		}
+		for i := range ref.lines {
+			if text.lines[i] != ref.lines[i] {
+				t.Fatalf("line %d: %v != %v", i, text.lines[i], ref.lines[i])
+			}
+		}
+		w, h := text.Size()
+		rw, rh := ref.Size()
+		if w != rw || h != rh {
+			t.Fatalf("size: %d,%d != %d,%d", w, h, rw, rh)
+		}
+	}
+	check("one\ntwo\nthree")
+
+	if err := text.InsertAt(4, "X\nY"); err != nil {
+		t.Fatal(err)
+	}
+	check("one\nX\nYtwo\nthree")
+
+	if err := text.DeleteRange(2, 7); err != nil {
+		t.Fatal(err)
+	}
+	check("ontwo\nthree")
+
+	if err := text.InsertAt(text.Len(), "\n"+strings.Repeat("w", 20)); err != nil {
+		t.Fatal(err)
+	}
+	check("ontwo\nthree\n" + strings.Repeat("w", 20))
+
+	// removing of the widest line
+	if err := text.DeleteRange(11, text.Len()); err != nil {
+		t.Fatal(err)
+	}
+	check("ontwo\nthree")
+
+	if err := text.DeleteRange(0, text.Len()); err != nil {
+		t.Fatal(err)
+	}
+	check("")
+}
+
+func TestTextErrors(t *testing.T) {
+	text := NewText(testFont(), "ab\ncd")
+	if err := text.InsertAt(6, "x"); err == nil {
+		t.Errorf("no error for offset out of text")
+	}
+	if err := text.DeleteRange(3, 1); err == nil {
+		t.Errorf("no error for invalid range")
+	}
+	text = NewText(testFont(), "a~b")
+	text.lines[0].text = "aéb" // two bytes rune, not in font
+	if err := text.InsertAt(2, "x"); err == nil {
+		t.Errorf("no error for offset inside of rune")
+	}
+}
+
+func TestTextLineAt(t *testing.T) {
+	text := NewText(testFont(), "ab\n\ncd")
+	for offset, line := range []int{0, 0, 0, 1, 2, 2, 2} {
+		if l := text.LineAt(offset); l != line {
+			t.Errorf("offset %d: line %d, expect %d", offset, l, line)
+		}
+	}
+}
+
+func TestTextOffsetAtKerning(t *testing.T) {
+	f := testFont()
+	f.kern = kernMap([]KernPair{{'A', 'V', -2}, {'V', 'A', -1}})
+	// glyphs are placed at 0, 6, 13 and 19, the line ends at 27
+	text := NewText(f, "AVAV")
+	for _, tc := range []struct {
+		dx     float32
+		offset int
+	}{
+		{3, 1},
+		{10, 2},
+		{22, 3},
+		{25, 4},
+	} {
+		if o := text.OffsetAt(tc.dx, 0); o != tc.offset {
+			t.Errorf("offset at %v is %d, expect %d", tc.dx, o, tc.offset)
+		}
+	}
+	for offset := 0; offset <= text.Len(); offset++ {
+		dx, _, err := text.CaretPos(offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o := text.OffsetAt(float32(dx), 0); o != offset {
+			t.Errorf("caret of offset %d at %d gives offset %d", offset, dx, o)
+		}
+	}
+	if x := NewInput(f, "AVAV").CaretX(); x != 27 {
+		t.Errorf("caret of the field at %d", x)
+	}
+}
+
+func TestTextLineRect(t *testing.T) {
+	text := NewText(testFont(), "ab\n\nabc")
+	for _, o := range []Origin{OriginBottomLeft, OriginTopLeft} {
+		SetOrigin(o)
+		for i := 0; i < text.Lines(); i++ {
+			r := text.LineRect(i, 10, 100, 0)
+			if r.X != 10 || r.Width != 24 || r.Height != 16 {
+				t.Errorf("origin %v: line %d: %+v", o, i, r)
+			}
+			// distance of the rectangle from the top of the text
+			top := 100 - rectTop(r)
+			if o == OriginTopLeft {
+				top = -top
+			}
+			for _, dy := range []float32{top, top + r.Height - 1} {
+				if l := text.LineAtY(dy); l != i {
+					t.Errorf("origin %v: line %d: line at %v is %d", o, i, dy, l)
+				}
+			}
+		}
+	}
+	SetOrigin(OriginBottomLeft)
+	if l := text.LineAtY(1000); l != 2 {
+		t.Errorf("line below of text is %d", l)
+	}
+	if r := text.LineRect(0, 0, 0, 5); r.Width != 5 {
+		t.Errorf("width %v", r.Width)
+	}
+}
+
+func TestBearings(t *testing.T) {
+	f := testFont()
+	// script glyph reaching 2 pixels into both neighbours
+	g := &f.Config.Glyphs['f'-f.Config.Low]
+	g.Width, g.LeftBearing, g.RightBearing = 10, -2, -2
+
+	if w := f.advanceSize("afa"); w != 8+6+8 {
+		t.Errorf("advance %d", w)
+	}
+	var xs []int32
+	f.EachGlyph("afa", func(g PlacedGlyph) bool {
+		xs = append(xs, g.X)
+		return true
+	})
+	if len(xs) != 3 || xs[0] != 0 || xs[1] != 6 || xs[2] != 14 {
+		t.Errorf("glyphs placed at %v", xs)
+	}
+}
@@ -0,0 +1,190 @@
+package glsymbol
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A texturePage is a band of rows of the sprite sheet uploaded as
+// a texture. Sheets higher than the largest texture are split into
+// pages between rows of glyph cells.
+type texturePage struct {
+	tex    uint32
+	y0, y1 int // rows of the sheet
+}
+
+// sheetPages returns the texture pages of the sprite sheet, which are
+// created on the first call and updated after glyphs are added to the
+// sheet. Glyphs are drawn as bitmaps by Printf, textures are only
+// needed by effects drawing glyphs as textured quads.
+func (f *Font) sheetPages() ([]texturePage, error) {
+	if len(f.pages) != 0 && !f.stale {
+		return f.pages, nil
+	}
+	if f.img == nil {
+		return nil, fmt.Errorf("glsymbol: font has no sprite sheet")
+	}
+	if err := faultError("sheetPages"); err != nil {
+		return nil, err
+	}
+	var max int32
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &max)
+	b := f.img.Bounds()
+	if int(max) < b.Dx() {
+		return nil, fmt.Errorf("glsymbol: sprite sheet of width %d is wider than the largest texture %d",
+			b.Dx(), max)
+	}
+	bands, err := splitPages(f.cells(), b.Dy(), int(max))
+	if err != nil {
+		return nil, err
+	}
+	f.releasePages()
+	for _, band := range bands {
+		sub := f.img.SubImage(image.Rect(b.Min.X, band[0], b.Max.X, band[1])).(*image.RGBA)
+		tex, err := uploadTexture(0, sub, gl.LINEAR)
+		if err != nil {
+			f.releasePages()
+			return nil, err
+		}
+		f.pages = append(f.pages, texturePage{tex: tex, y0: band[0], y1: band[1]})
+	}
+	f.stale = false
+	return f.pages, nil
+}
+
+// releasePages deletes textures of the sprite sheet.
+func (f *Font) releasePages() {
+	for i := range f.pages {
+		gl.DeleteTextures(1, &f.pages[i].tex)
+	}
+	f.pages = nil
+}
+
+// cells returns rectangles of glyph cells in the sprite sheet, from
+// the blank row above a glyph to its bottom row.
+func (f *Font) cells() []image.Rectangle {
+	var cells []image.Rectangle
+	add := func(g *Glyph) {
+		cells = append(cells, image.Rect(int(g.X), int(g.Y), int(g.X+g.Width), int(g.Y+g.Height)+1))
+	}
+	if f.Config != nil {
+		for i := range f.Config.Glyphs {
+			add(&f.Config.Glyphs[i])
+		}
+	}
+	for _, r := range f.addedRunes() {
+		add(f.added[r])
+	}
+	return cells
+}
+
+// splitPages splits rows of a sheet of the height into bands at most max
+// rows high, which do not cut the cells. It returns the first and the
+// end row of every band.
+func splitPages(cells []image.Rectangle, height, max int) ([][2]int, error) {
+	// cut[y] is negative if a cell holds both rows y-1 and y
+	cut := make([]int, height+2)
+	for _, c := range cells {
+		if c.Min.Y < c.Max.Y && 0 <= c.Min.Y && c.Max.Y <= height {
+			cut[c.Min.Y+1]--
+			cut[c.Max.Y]++
+		}
+	}
+	for y := 1; y <= height; y++ {
+		cut[y] += cut[y-1]
+	}
+	var bands [][2]int
+	for y0 := 0; y0 < height; {
+		y1 := y0 + max
+		if height <= y1 {
+			y1 = height
+		} else {
+			for y0 < y1 && cut[y1] < 0 {
+				y1--
+			}
+			if y1 == y0 {
+				return nil, fmt.Errorf("glsymbol: glyph cell at row %d is higher than the largest texture %d", y0, max)
+			}
+		}
+		bands = append(bands, [2]int{y0, y1})
+		y0 = y1
+	}
+	return bands, nil
+}
+
+// pageOf returns the index of the page holding the glyph.
+func pageOf(pages []texturePage, g *Glyph) int {
+	for i := range pages {
+		if int(g.Y) < pages[i].y1 {
+			return i
+		}
+	}
+	return len(pages) - 1
+}
+
+// uploadTexture copies the image into the texture, which is created if
+// tex is zero, and returns the texture. The filter is used for both
+// minification and magnification.
+func uploadTexture(tex uint32, img *image.RGBA, filter int32) (uint32, error) {
+	created := tex == 0
+	if created {
+		gl.GenTextures(1, &tex)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, tex)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	b := img.Bounds()
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, int32(img.Stride/4))
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(b.Dx()), int32(b.Dy()), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
+	if err := checkGLError("uploadTexture"); err != nil {
+		if created {
+			warn("glsymbol: texture deleted after GL error", "error", err)
+			gl.DeleteTextures(1, &tex)
+		}
+		return 0, err
+	}
+	return tex, nil
+}
+
+// drawTexture draws the whole texture as a quad with the top left
+// corner at x, y in the coordinates given by SetOrigin, modulated by
+// the current color.
+func drawTexture(tex uint32, x, y, width, height float32) {
+	gl.PushAttrib(gl.ENABLE_BIT | gl.TEXTURE_BIT | gl.COLOR_BUFFER_BIT)
+	defer gl.PopAttrib()
+
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindTexture(gl.TEXTURE_2D, tex)
+	gl.TexEnvi(gl.TEXTURE_ENV, gl.TEXTURE_ENV_MODE, gl.MODULATE)
+	bottom := below(y, height)
+	gl.Begin(gl.QUADS)
+	gl.TexCoord2f(0, 1)
+	gl.Vertex2f(x, bottom)
+	gl.TexCoord2f(1, 1)
+	gl.Vertex2f(x+width, bottom)
+	gl.TexCoord2f(1, 0)
+	gl.Vertex2f(x+width, y)
+	gl.TexCoord2f(0, 0)
+	gl.Vertex2f(x, y)
+	gl.End()
+}
+
+// glyphTexCoords returns texture coordinates in the page of the glyph
+// region drawn by Printf: u0, v0 of the top left corner and u1, v1 of the bottom
+// right corner.
+func (f *Font) glyphTexCoords(p texturePage, g *Glyph) (u0, v0, u1, v1 float32) {
+	w, h := float32(f.img.Bounds().Dx()), float32(p.y1-p.y0)
+	y := g.Y - int32(p.y0)
+	// bitmaps start one row below the glyph rectangle, see loadFont
+	return float32(g.X) / w, float32(y+1) / h,
+		float32(g.X+g.Width) / w, float32(y+g.Height+1) / h
+}
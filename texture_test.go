@@ -0,0 +1,48 @@
+package glsymbol
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitPages(t *testing.T) {
+	// rows of cells 10 pixels high with a blank row above every cell
+	var cells []image.Rectangle
+	for y := 0; y < 55; y += 11 {
+		cells = append(cells, image.Rect(0, y, 8, y+11), image.Rect(8, y, 12, y+11))
+	}
+	for _, tc := range []struct {
+		max    int
+		expect [][2]int
+	}{
+		{64, [][2]int{{0, 55}}},
+		{55, [][2]int{{0, 55}}},
+		{30, [][2]int{{0, 22}, {22, 44}, {44, 55}}},
+		{11, [][2]int{{0, 11}, {11, 22}, {22, 33}, {33, 44}, {44, 55}}},
+	} {
+		bands, err := splitPages(cells, 55, tc.max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bands, tc.expect) {
+			t.Errorf("max %d: bands %v, expect %v", tc.max, bands, tc.expect)
+		}
+	}
+	if _, err := splitPages(cells, 55, 10); err == nil || !strings.Contains(err.Error(), "higher than") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	f := testSheetFont()
+	pages := []texturePage{{y0: 0, y1: 11}, {y0: 11, y1: 30}}
+	g := &Glyph{X: 4, Y: 11, Width: 8, Height: 10}
+	if p := pageOf(pages, g); p != 1 {
+		t.Errorf("glyph is on page %d", p)
+	}
+	w := float32(f.img.Bounds().Dx())
+	u0, v0, u1, v1 := f.glyphTexCoords(pages[1], g)
+	if u0 != 4/w || v0 != 1/19. || u1 != 12/w || v1 != 11/19. {
+		t.Errorf("texture coordinates %v %v %v %v", u0, v0, u1, v1)
+	}
+}
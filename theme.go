@@ -0,0 +1,258 @@
+package glsymbol
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// A Theme is the style of text and widgets drawn by the package: fonts
+// by role, colors by role, paddings and decorations. Products restyle
+// all of their text from a configuration file by LoadTheme and SetTheme:
+//
+//	{
+//		"fonts": {
+//			"body": {"size": 16},
+//			"title": {"file": "fonts/title.ttf", "size": 24}
+//		},
+//		"colors": {"text": "#e0e0e0", "background": "#202020e0", "border": "#808080"},
+//		"padding": 4,
+//		"lineHeight": 1.2,
+//		"frame": {"borderWidth": 1, "radius": 4},
+//		"link": {"underline": true}
+//	}
+type Theme struct {
+	Fonts  map[string]ThemeFont `json:"fonts,omitempty"`
+	Colors map[string]Color     `json:"colors,omitempty"`
+
+	Padding    float32 `json:"padding,omitempty"`    // between text and frames
+	LineHeight float32 `json:"lineHeight,omitempty"` // multiplier, zero is normal
+
+	Frame ThemeFrame `json:"frame"`
+	Link  ThemeLink  `json:"link"`
+
+	fonts map[string]*Font // loaded fonts
+}
+
+// ThemeFont describes a font of a role. An empty file is the default font.
+type ThemeFont struct {
+	File string `json:"file,omitempty"`
+	Size int32  `json:"size"`
+	Low  rune   `json:"low,omitempty"`  // zero is 32
+	High rune   `json:"high,omitempty"` // zero is 127
+}
+
+// ThemeFrame describes frames of text boxes. The frame is filled by the
+// "background" color and outlined by the "border" color.
+type ThemeFrame struct {
+	BorderWidth float32 `json:"borderWidth,omitempty"`
+	Radius      float32 `json:"radius,omitempty"`
+}
+
+// ThemeLink describes decorations of links. Hovered links are
+// highlighted by the "highlight" color.
+type ThemeLink struct {
+	Underline bool `json:"underline,omitempty"`
+}
+
+// Color roles of themes.
+const (
+	ColorText       = "text"
+	ColorBackground = "background"
+	ColorBorder     = "border"
+	ColorHighlight  = "highlight"
+	ColorCaret      = "caret"
+	ColorWarning    = "warning"
+	ColorError      = "error"
+)
+
+// Color is a color encoded in JSON as "#rrggbb" or "#rrggbbaa".
+type Color color.RGBA
+
+// MarshalJSON implements json.Marshaler.
+func (c Color) MarshalJSON() ([]byte, error) {
+	s := fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf("%02x", c.A)
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var v [4]uint8
+	v[3] = 0xff
+	n := 0
+	if strings.HasPrefix(s, "#") {
+		var err error
+		switch len(s) {
+		case 7:
+			n, err = fmt.Sscanf(s, "#%02x%02x%02x", &v[0], &v[1], &v[2])
+		case 9:
+			n, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &v[0], &v[1], &v[2], &v[3])
+		}
+		if err != nil {
+			n = 0
+		}
+	}
+	if n == 0 {
+		return fmt.Errorf("glsymbol: color %q is not #rrggbb or #rrggbbaa", s)
+	}
+	*c = Color{v[0], v[1], v[2], v[3]}
+	return nil
+}
+
+// DefaultTheme returns the theme used if no other is set: the default
+// font for the "body" role, white text and no decorations.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Fonts:  map[string]ThemeFont{"body": {Size: 16}},
+		Colors: map[string]Color{ColorText: {0xff, 0xff, 0xff, 0xff}},
+	}
+}
+
+// LoadTheme reads a theme in JSON.
+func LoadTheme(r io.Reader) (*Theme, error) {
+	t := new(Theme)
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(t); err != nil {
+		return nil, fmt.Errorf("glsymbol: theme: %v", err)
+	}
+	return t, nil
+}
+
+// Save writes the theme in JSON.
+func (t *Theme) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(t)
+}
+
+// theme is the theme of the package.
+var theme = DefaultTheme()
+
+// SetTheme sets the theme used by the package, nil sets the default theme.
+func SetTheme(t *Theme) {
+	if t == nil {
+		t = DefaultTheme()
+	}
+	theme = t
+}
+
+// CurrentTheme returns the theme set by SetTheme.
+func CurrentTheme() *Theme {
+	return theme
+}
+
+// Color returns the color of the role and whether it is set.
+func (t *Theme) Color(role string) (color.RGBA, bool) {
+	c, ok := t.Colors[role]
+	return color.RGBA(c), ok
+}
+
+// color returns the color of the role or nil.
+func (t *Theme) color(role string) color.Color {
+	if c, ok := t.Color(role); ok {
+		return c
+	}
+	return nil
+}
+
+// Font returns the font of the role, which is loaded on the first call.
+// Fonts are loaded with the line height of the theme and require the
+// OpenGL context.
+func (t *Theme) Font(role string) (*Font, error) {
+	if f, ok := t.fonts[role]; ok {
+		return f, nil
+	}
+	spec, ok := t.Fonts[role]
+	if !ok {
+		return nil, fmt.Errorf("glsymbol: theme has no font %q", role)
+	}
+	low, high := spec.Low, spec.High
+	if low == 0 {
+		low = 32
+	}
+	if high == 0 {
+		high = 127
+	}
+	var r io.Reader = strings.NewReader(DefaultEmbeddedFont)
+	if spec.File != "" {
+		file, err := os.Open(spec.File)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	f, err := LoadTruetype(r, spec.Size, low, high)
+	if err != nil {
+		return nil, fmt.Errorf("glsymbol: theme font %q: %v", role, err)
+	}
+	if t.LineHeight != 0 {
+		f.LineHeight = LineMultiple(t.LineHeight)
+	}
+	if t.fonts == nil {
+		t.fonts = map[string]*Font{}
+	}
+	t.fonts[role] = f
+	return f, nil
+}
+
+// Release releases fonts loaded by the theme.
+func (t *Theme) Release() {
+	for _, f := range t.fonts {
+		f.Release()
+	}
+	t.fonts = nil
+}
+
+// TextFrame returns the frame of text boxes of the theme.
+func (t *Theme) TextFrame() Frame {
+	return t.textFrame()
+}
+
+// textFrame returns the frame of TextFrame, for widgets changing its
+// colors.
+func (t *Theme) textFrame() RoundedFrame {
+	return RoundedFrame{
+		Fill:        t.color(ColorBackground),
+		Border:      t.color(ColorBorder),
+		BorderWidth: t.Frame.BorderWidth,
+		Radius:      t.Frame.Radius,
+	}
+}
+
+// LinkStyle returns the decoration of links of the theme for the hovered link.
+func (t *Theme) LinkStyle(hover string) LinkStyle {
+	return LinkStyle{
+		Underline: t.Link.Underline,
+		Hover:     hover,
+		Highlight: t.color(ColorHighlight),
+	}
+}
+
+// DrawTextBox draws the text in a box as Font.DrawTextBox with the font
+// of the role, the frame, the padding and the text color of the theme.
+func (t *Theme) DrawTextBox(role string, x, y float32, text string) (Rect, error) {
+	f, err := t.Font(role)
+	if err != nil {
+		return Rect{}, err
+	}
+	gl.PushAttrib(gl.CURRENT_BIT)
+	defer gl.PopAttrib()
+	if c := t.color(ColorText); c != nil {
+		setColor(c)
+	}
+	return f.DrawTextBox(x, y, text, t.TextFrame(), t.Padding)
+}
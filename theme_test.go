@@ -0,0 +1,68 @@
+package glsymbol
+
+import (
+	"bytes"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	in := `{
+		"fonts": {"body": {"size": 16}, "title": {"file": "title.ttf", "size": 24}},
+		"colors": {"text": "#e0e0e0", "background": "#20202080"},
+		"padding": 4,
+		"lineHeight": 1.2,
+		"frame": {"borderWidth": 1, "radius": 4},
+		"link": {"underline": true}
+	}`
+	th, err := LoadTheme(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := th.Color(ColorBackground); !ok || c != (color.RGBA{0x20, 0x20, 0x20, 0x80}) {
+		t.Errorf("background %v", c)
+	}
+	if _, ok := th.Color(ColorBorder); ok {
+		t.Errorf("border is not set")
+	}
+	frame := th.TextFrame().(RoundedFrame)
+	if frame.Border != nil || frame.Fill == nil || frame.Radius != 4 {
+		t.Errorf("frame %+v", frame)
+	}
+	if ls := th.LinkStyle("x"); !ls.Underline || ls.Hover != "x" || ls.Highlight != nil {
+		t.Errorf("link style %+v", ls)
+	}
+
+	var b bytes.Buffer
+	if err := th.Save(&b); err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadTheme(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(th, again) {
+		t.Errorf("not same after saving:\n%+v\n%+v", th, again)
+	}
+
+	for _, bad := range []string{
+		`{"colors": {"text": "red"}}`,
+		`{"colors": {"text": "#12345"}}`,
+		`{"padding": 1, "unknown": 2}`,
+	} {
+		if _, err := LoadTheme(strings.NewReader(bad)); err == nil {
+			t.Errorf("no error for %s", bad)
+		}
+	}
+
+	SetTheme(th)
+	if CurrentTheme() != th {
+		t.Errorf("theme is not set")
+	}
+	SetTheme(nil)
+	if c, _ := CurrentTheme().Color(ColorText); c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("default text color %v", c)
+	}
+}
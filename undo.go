@@ -0,0 +1,130 @@
+package glsymbol
+
+import (
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Edits of Input and Text are recorded for Undo and Redo in groups:
+// runes typed or removed one after another form a group, which ends at
+// a pause of undoPause, at the start of a new word and when the caret
+// is moved. Only the last undoLimit groups are kept.
+const (
+	undoPause = time.Second
+	undoLimit = 100
+)
+
+// textEdit is a change of text: bytes removed at the offset and bytes
+// inserted instead of them.
+type textEdit struct {
+	offset   int
+	removed  string
+	inserted string
+}
+
+// revert returns the edit which undoes the edit.
+func (e textEdit) revert() textEdit {
+	return textEdit{offset: e.offset, removed: e.inserted, inserted: e.removed}
+}
+
+// apply returns the string changed by the edit.
+func (e textEdit) apply(s string) string {
+	return s[:e.offset] + e.inserted + s[e.offset+len(e.removed):]
+}
+
+// applyEdits returns the string changed by the edits and the offset
+// after the text inserted by the last edit, the place of the caret.
+func applyEdits(s string, edits []textEdit) (string, int) {
+	caret := 0
+	for _, e := range edits {
+		s = e.apply(s)
+		caret = e.offset + len(e.inserted)
+	}
+	return s, caret
+}
+
+// undoGroup is a group of edits undone together.
+type undoGroup struct {
+	edits []textEdit
+	at    time.Time // time of the last edit
+}
+
+// history holds groups of edits for undo and redo.
+type history struct {
+	done   []undoGroup
+	undone []undoGroup
+	open   bool // the last done group takes next edits
+}
+
+// record adds the edit made at the time, to the last group if the edit
+// continues it. Undone edits can not be redone after a new edit.
+func (h *history) record(e textEdit, at time.Time) {
+	h.undone = nil
+	if n := len(h.done); h.open && 0 < n && continues(h.done[n-1], e, at) {
+		g := &h.done[n-1]
+		g.edits = append(g.edits, e)
+		g.at = at
+		return
+	}
+	h.done = append(h.done, undoGroup{edits: []textEdit{e}, at: at})
+	if undoLimit < len(h.done) {
+		h.done = h.done[len(h.done)-undoLimit:]
+	}
+	h.open = true
+}
+
+// continues reports whether the edit made at the time continues the
+// group: it inserts after the last insertion, not starting a new word,
+// or removes next to the last removal, without a pause.
+func continues(g undoGroup, e textEdit, at time.Time) bool {
+	if undoPause <= at.Sub(g.at) {
+		return false
+	}
+	l := g.edits[len(g.edits)-1]
+	switch {
+	case l.removed == "" && e.removed == "":
+		if e.offset != l.offset+len(l.inserted) {
+			return false
+		}
+		last, _ := utf8.DecodeLastRuneInString(l.inserted)
+		first, _ := utf8.DecodeRuneInString(e.inserted)
+		return !unicode.IsSpace(last) || unicode.IsSpace(first)
+	case l.inserted == "" && e.inserted == "":
+		// removed by backspace or by delete
+		return e.offset+len(e.removed) == l.offset || e.offset == l.offset
+	}
+	return false
+}
+
+// undo returns edits reverting the last group, in order of application,
+// or false if there is nothing to undo.
+func (h *history) undo() ([]textEdit, bool) {
+	n := len(h.done)
+	if n == 0 {
+		return nil, false
+	}
+	g := h.done[n-1]
+	h.done = h.done[:n-1]
+	h.undone = append(h.undone, g)
+	h.open = false
+	edits := make([]textEdit, len(g.edits))
+	for i, e := range g.edits {
+		edits[len(edits)-1-i] = e.revert()
+	}
+	return edits, true
+}
+
+// redo returns edits of the last undone group, in order of application,
+// or false if there is nothing to redo.
+func (h *history) redo() ([]textEdit, bool) {
+	n := len(h.undone)
+	if n == 0 {
+		return nil, false
+	}
+	g := h.undone[n-1]
+	h.undone = h.undone[:n-1]
+	h.done = append(h.done, g)
+	h.open = false
+	return g.edits, true
+}
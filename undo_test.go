@@ -0,0 +1,108 @@
+package glsymbol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInputUndo(t *testing.T) {
+	clock := new(ManualClock)
+	in := NewInput(testFont(), "")
+	in.Clock = clock
+	typed := func(s string) {
+		for _, r := range s {
+			in.HandleChar(r)
+		}
+	}
+	check := func(text string, caret int) {
+		t.Helper()
+		if in.String() != text || in.Caret() != caret {
+			t.Fatalf("text %q caret %d, expect %q %d", in.String(), in.Caret(), text, caret)
+		}
+	}
+	// words are undone one by one
+	typed("one two")
+	clock.Advance(2 * undoPause)
+	typed("x")
+	in.HandleKey(KeyUndo)
+	check("one two", 7)
+	in.HandleKey(KeyUndo)
+	check("one ", 4)
+	in.HandleKey(KeyRedo)
+	check("one two", 7)
+
+	// removals next to each other are undone together
+	in.HandleKey(KeyBackspace)
+	in.HandleKey(KeyBackspace)
+	check("one t", 5)
+	in.HandleKey(KeyLeft)
+	in.HandleKey(KeyBackspace)
+	check("onet", 3)
+	in.Undo()
+	check("one t", 4)
+	in.Undo()
+	check("one two", 7)
+
+	// a new edit drops undone edits
+	typed("!")
+	if in.Redo() {
+		t.Errorf("undone edits are redone after an edit")
+	}
+	in.SetText("new")
+	if in.Undo() {
+		t.Errorf("edits before SetText are undone")
+	}
+}
+
+func TestTextUndo(t *testing.T) {
+	text := NewText(testFont(), "one\ntwo")
+	text.Clock = NewManualClock(time.Time{})
+	if err := text.DeleteRange(2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := text.InsertAt(2, "\nthree\n"); err != nil {
+		t.Fatal(err)
+	}
+	if s := text.String(); s != "on\nthree\nwo" {
+		t.Fatalf("text %q", s)
+	}
+	if w, _ := text.Size(); text.Lines() != 3 || w != 40 {
+		t.Errorf("lines %d, width %d", text.Lines(), w)
+	}
+	if offset, ok := text.Undo(); !ok || offset != 2 || text.String() != "onwo" {
+		t.Errorf("undo to %q, caret %d", text.String(), offset)
+	}
+	if offset, ok := text.Undo(); !ok || offset != 5 || text.String() != "one\ntwo" {
+		t.Errorf("undo to %q, caret %d", text.String(), offset)
+	}
+	if _, ok := text.Undo(); ok {
+		t.Errorf("undo of nothing")
+	}
+	if offset, ok := text.Redo(); !ok || offset != 2 || text.String() != "onwo" {
+		t.Errorf("redo to %q, caret %d", text.String(), offset)
+	}
+	for i := range text.lines {
+		if text.lines[i] != (textLine{text: "onwo", width: 32}) {
+			t.Errorf("line %d: %+v", i, text.lines[i])
+		}
+	}
+}
+
+func TestTextUndoLineBreaks(t *testing.T) {
+	for _, br := range []string{"\r\n", "\r", "\u2028"} {
+		text := NewText(testFont(), "one\ntwo")
+		text.Clock = NewManualClock(time.Time{})
+		if err := text.InsertAt(5, "a"+br+"b"); err != nil {
+			t.Fatal(err)
+		}
+		if s := text.String(); s != "one\nta\nbwo" {
+			t.Fatalf("%q: text %q", br, s)
+		}
+		if offset, ok := text.Undo(); !ok || offset != 5 || text.String() != "one\ntwo" {
+			t.Errorf("%q: undo to %q, caret %d", br, text.String(), offset)
+		}
+		if offset, ok := text.Redo(); !ok || offset != 8 || text.String() != "one\nta\nbwo" {
+			t.Errorf("%q: redo to %q, caret %d", br, text.String(), offset)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package glsymbol
+
+// UnknownRune is a policy for runes a font has no glyphs for, neither
+// in its range nor added or supplied by Fallback. The same policy is
+// used to measure and to draw text, so measured sizes match the drawn
+// text.
+type UnknownRune int
+
+// Policies for unknown runes.
+const (
+	UnknownZero        UnknownRune = iota // nothing is drawn, the pen does not move
+	UnknownMaxWidth                       // an empty cell of MaxGlyphWidth
+	UnknownAverage                        // an empty cell of the average step of glyphs
+	UnknownReplacement                    // glyph of U+FFFD, or of '?' if the font has none
+)
+
+// unknownGlyph returns the glyph of unknown runes by the Unknown policy.
+func (f *Font) unknownGlyph() *Glyph {
+	var w int32
+	switch f.Unknown {
+	case UnknownMaxWidth:
+		w = f.MaxGlyphWidth
+	case UnknownAverage:
+		w = f.rounding().Round(float32(f.averageStep()))
+	case UnknownReplacement:
+		for _, r := range []rune{'\ufffd', '?'} {
+			if g := f.knownGlyph(r); g != nil {
+				return g
+			}
+		}
+	}
+	f.unknown = Glyph{Advance: w, RightBearing: w}
+	return &f.unknown
+}
+
+// knownGlyph returns the glyph of the rune in the range or added to
+// the font, or nil.
+func (f *Font) knownGlyph(r rune) *Glyph {
+	c := f.Config
+	if i := c.Index(r); 0 <= i {
+		return &c.Glyphs[i]
+	}
+	return f.added[r]
+}
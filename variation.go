@@ -0,0 +1,139 @@
+package glsymbol
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A FontAxis is a design axis of an OpenType variable font, for example
+// "wght" for the weight or "wdth" for the width.
+type FontAxis struct {
+	Tag               string
+	Min, Default, Max float64
+}
+
+// FontAxes returns the design axes of a variable font from its fvar
+// table, or nil for a font without variations.
+func FontAxes(data []byte) ([]FontAxis, error) {
+	fvar, err := sfntTable(data, "fvar")
+	if err != nil || fvar == nil {
+		return nil, err
+	}
+	be := binary.BigEndian
+	if len(fvar) < 16 {
+		return nil, fmt.Errorf("glsymbol: fvar table is truncated")
+	}
+	offset := int(be.Uint16(fvar[4:]))
+	count := int(be.Uint16(fvar[8:]))
+	size := int(be.Uint16(fvar[10:]))
+	if size < 20 || len(fvar) < offset+count*size {
+		return nil, fmt.Errorf("glsymbol: fvar axes are out of table")
+	}
+	fixed := func(b []byte) float64 { return float64(int32(be.Uint32(b))) / 0x10000 }
+	axes := make([]FontAxis, count)
+	for i := range axes {
+		a := fvar[offset+i*size:]
+		axes[i] = FontAxis{
+			Tag:     string(a[:4]),
+			Min:     fixed(a[4:]),
+			Default: fixed(a[8:]),
+			Max:     fixed(a[12:]),
+		}
+	}
+	return axes, nil
+}
+
+// sfntTable returns the table with the tag from the table directory of
+// a TrueType or OpenType font, or nil if there is no such table.
+func sfntTable(data []byte, tag string) ([]byte, error) {
+	be := binary.BigEndian
+	if len(data) < 12 {
+		return nil, fmt.Errorf("glsymbol: font table directory is truncated")
+	}
+	n := int(be.Uint16(data[4:]))
+	if len(data) < 12+16*n {
+		return nil, fmt.Errorf("glsymbol: font table directory is truncated")
+	}
+	for i := 0; i < n; i++ {
+		rec := data[12+16*i:]
+		if !bytes.Equal(rec[:4], []byte(tag)) {
+			continue
+		}
+		offset, length := int64(be.Uint32(rec[8:])), int64(be.Uint32(rec[12:]))
+		if int64(len(data)) < offset+length {
+			return nil, fmt.Errorf("glsymbol: font table %q is out of file", tag)
+		}
+		return data[offset : offset+length], nil
+	}
+	return nil, nil
+}
+
+// TruetypeOptions are options of LoadTruetypeOptions.
+type TruetypeOptions struct {
+	// Variations selects values of design axes of a variable font by
+	// axis tags, for example {"wght": 700}. Axes not listed keep their
+	// default values, see FontAxes.
+	//
+	// The rasterizers draw the default instance of a variable font only,
+	// so values other than defaults are reported as errors instead of
+	// silently drawing the default outlines.
+	Variations map[string]float64
+
+	// Padding is the amount of blank pixels around every glyph cell of
+	// the sprite sheet, in addition to the blank row and column between
+	// cells. Padding keeps neighbour glyphs out of samples
+	// of textures drawn with linear filtering at fractional scales or
+	// with mipmaps, which average several pixels of the sheet.
+	Padding int
+}
+
+// LoadTruetypeOptions loads a truetype or OpenType font as LoadTruetype
+// with the options, which may be nil.
+func LoadTruetypeOptions(r io.Reader, scale int32, low, high rune, opts *TruetypeOptions) (*Font, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &TruetypeOptions{}
+	}
+	if len(opts.Variations) != 0 {
+		if err := checkVariations(data, opts.Variations); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Padding < 0 {
+		return nil, fmt.Errorf("glsymbol: negative padding %d", opts.Padding)
+	}
+	return loadTruetypeData(context.Background(), data, scale, []RuneRange{{low, high}}, opts.Padding)
+}
+
+// checkVariations checks the axis values against axes of the font.
+func checkVariations(data []byte, variations map[string]float64) error {
+	axes, err := FontAxes(data)
+	if err != nil {
+		return err
+	}
+	for tag, v := range variations {
+		var axis *FontAxis
+		for i := range axes {
+			if axes[i].Tag == tag {
+				axis = &axes[i]
+			}
+		}
+		switch {
+		case axis == nil:
+			return fmt.Errorf("glsymbol: font has no variation axis %q", tag)
+		case v < axis.Min || axis.Max < v:
+			return fmt.Errorf("glsymbol: value %v of axis %q is out of range %v..%v",
+				v, tag, axis.Min, axis.Max)
+		case v != axis.Default:
+			return fmt.Errorf("glsymbol: value %v of axis %q is not the default %v, variable font instances are not supported",
+				v, tag, axis.Default)
+		}
+	}
+	return nil
+}
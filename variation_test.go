@@ -0,0 +1,68 @@
+package glsymbol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// testVariableFont returns a table directory with an fvar table of
+// the axis wght 100..900, default 400.
+func testVariableFont() []byte {
+	be := binary.BigEndian
+	var fvar bytes.Buffer
+	binary.Write(&fvar, be, []uint16{1, 0, 16, 2, 1, 20, 0, 0})
+	fvar.WriteString("wght")
+	binary.Write(&fvar, be, []int32{100 << 16, 400 << 16, 900 << 16})
+	binary.Write(&fvar, be, []uint16{0, 256})
+
+	var data bytes.Buffer
+	binary.Write(&data, be, []uint32{0x10000})
+	binary.Write(&data, be, []uint16{1, 0, 0, 0})
+	data.WriteString("fvar")
+	binary.Write(&data, be, []uint32{0, 28, uint32(fvar.Len())})
+	data.Write(fvar.Bytes())
+	return data.Bytes()
+}
+
+func TestFontAxes(t *testing.T) {
+	axes, err := FontAxes(testVariableFont())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(axes) != 1 || axes[0] != (FontAxis{Tag: "wght", Min: 100, Default: 400, Max: 900}) {
+		t.Errorf("axes %+v", axes)
+	}
+	if axes, err := FontAxes(goregular.TTF); err != nil || axes != nil {
+		t.Errorf("axes of static font %v, %v", axes, err)
+	}
+	if _, err := FontAxes([]byte("true")); err == nil {
+		t.Errorf("no error for truncated font")
+	}
+}
+
+func TestCheckVariations(t *testing.T) {
+	data := testVariableFont()
+	for _, tc := range []struct {
+		variations map[string]float64
+		ok         bool
+	}{
+		{map[string]float64{"wght": 400}, true},
+		{map[string]float64{"wght": 700}, false},
+		{map[string]float64{"wght": 1000}, false},
+		{map[string]float64{"wdth": 100}, false},
+	} {
+		if err := checkVariations(data, tc.variations); (err == nil) != tc.ok {
+			t.Errorf("%v: %v", tc.variations, err)
+		}
+	}
+}
+
+func TestTruetypeOptionsPadding(t *testing.T) {
+	_, err := LoadTruetypeOptions(bytes.NewReader(goregular.TTF), 16, 32, 126, &TruetypeOptions{Padding: -1})
+	if err == nil || err.Error() != "glsymbol: negative padding -1" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
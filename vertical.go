@@ -0,0 +1,84 @@
+package glsymbol
+
+import "unicode"
+
+// upright reports whether the glyph of the rune stays upright in vertical
+// text: ideographs, kana, hangul and other runes of East Asian scripts
+// and their punctuation and full width forms. Other runes, Latin letters
+// and digits for example, are turned sideways, see Font.Sideways.
+func upright(r rune) bool {
+	switch {
+	case 0x3000 <= r && r <= 0x303f, // CJK symbols and punctuation
+		0xff00 <= r && r <= 0xff60, 0xffe0 <= r && r <= 0xffe6, // full width forms
+		0xfe30 <= r && r <= 0xfe4f: // CJK compatibility forms
+		return true
+	}
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana,
+		unicode.Hangul, unicode.Bopomofo, unicode.Yi)
+}
+
+// turned returns the glyph turned a quarter turn clockwise, so the left
+// edge of the glyph is at the top and its baseline on the left. Turned
+// glyphs are made on first use and kept by the font.
+func (f *Font) turned(g *Glyph) *Glyph {
+	if t, ok := f.turnedGlyphs[g]; ok {
+		return t
+	}
+	t := &Glyph{
+		X: g.X, Y: g.Y,
+		Width: g.Height, Height: g.Width,
+		LeftBearing: g.BottomBearing, RightBearing: g.TopBearing,
+		TopBearing: g.LeftBearing, BottomBearing: g.RightBearing,
+		upright: g,
+	}
+	if t.TopBearing < 0 {
+		t.TopBearing = 0
+	}
+	if t.BottomBearing < 0 {
+		t.BottomBearing = 0
+	}
+	if len(g.BitmapData) != 0 {
+		t.BitmapData = turnBitmap(g.BitmapData, int(g.Width), int(g.Height))
+	}
+	if f.turnedGlyphs == nil {
+		f.turnedGlyphs = make(map[*Glyph]*Glyph)
+	}
+	f.turnedGlyphs[g] = t
+	return t
+}
+
+// turnBitmap returns the bitmap of the width and the height in the format
+// of gl.Bitmap, rows from the bottom up, turned a quarter turn clockwise.
+// The turned bitmap is h pixels wide and w pixels high.
+func turnBitmap(src []uint8, w, h int) []uint8 {
+	srcRow := (w + 7) / 8
+	dstRow := (h + 7) / 8
+	dst := make([]uint8, dstRow*(w+1))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if src[y*srcRow+x/8]&(0x80>>(x%8)) == 0 {
+				continue
+			}
+			// the top row turns into the right column, the left
+			// column into the top row
+			dx, dy := y, w-1-x
+			dst[dy*dstRow+dx/8] |= 0x80 >> (dx % 8)
+		}
+	}
+	return dst
+}
+
+// turnSideways places the glyph of a TopToBottom column turned sideways
+// and reports true if the font turns glyphs of its rune. The cell of a
+// turned glyph is centered in the column, the pen moves down over it by
+// its step.
+func (f *Font) turnSideways(g *PlacedGlyph) bool {
+	if !f.Sideways || upright(g.Rune) {
+		return false
+	}
+	u := g.Glyph
+	g.Glyph = f.turned(u)
+	g.X = (f.MaxGlyphWidth-u.cell())/2 + g.Glyph.LeftBearing
+	g.Rotated = true
+	return true
+}
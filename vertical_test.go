@@ -0,0 +1,52 @@
+package glsymbol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTurnBitmap(t *testing.T) {
+	// rows from the bottom up: an L of the width 3 and the height 2
+	//	X..
+	//	XXX
+	src := []uint8{0xe0, 0x80}
+	// turned clockwise: the width 2 and the height 3
+	//	XX
+	//	X.
+	//	X.
+	got := turnBitmap(src, 3, 2)
+	expect := []uint8{0x80, 0x80, 0xc0, 0}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("bitmap %08b, expect %08b", got, expect)
+	}
+}
+
+func TestSideways(t *testing.T) {
+	f := testFont()
+	f.MaxGlyphWidth = 16
+	f.Direction = TopToBottom
+	f.Sideways = true
+	i := &f.Config.Glyphs['i'-f.Config.Low]
+	i.LeftBearing, i.RightBearing = 1, 2
+	f.AddGlyph('日', nil, GlyphMetrics{Advance: 16})
+
+	var got []PlacedGlyph
+	pen, _ := f.place("日ia", func(g PlacedGlyph) bool {
+		got = append(got, g)
+		return true
+	})
+	if len(got) != 3 || got[0].Rotated || !got[1].Rotated || !got[2].Rotated {
+		t.Fatalf("glyphs %+v", got)
+	}
+	// the cell of the turned 'i' is 16 pixels wide and 7 pixels high
+	turned := got[1].Glyph
+	if turned.Width != 16 || turned.Height != 4 || turned.TopBearing != 1 || turned.BottomBearing != 2 {
+		t.Errorf("turned glyph %+v", turned)
+	}
+	if got[1].X != 0 || got[1].Y != -16 || got[1].Step != 7 || got[2].Y != -23 || pen != 31 {
+		t.Errorf("glyphs at %d,%d and %d, pen %d", got[1].X, got[1].Y, got[2].Y, pen)
+	}
+	if f.turned(i) != turned {
+		t.Errorf("turned glyphs are not kept")
+	}
+}
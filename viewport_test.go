@@ -0,0 +1,23 @@
+package glsymbol
+
+import "testing"
+
+func TestSetDisplaySize(t *testing.T) {
+	defer SetDisplaySize(-1, -1)
+
+	SetDisplaySize(640, 480)
+	if w, h := viewportSize(); w != 640 || h != 480 {
+		t.Errorf("unexpected size: %d %d", w, h)
+	}
+	if !viewportVisible() {
+		t.Errorf("viewport is not visible")
+	}
+	SetDisplaySize(0, 480)
+	if viewportVisible() {
+		t.Errorf("empty viewport is visible")
+	}
+	SetDisplaySize(-1, -1)
+	if display.known {
+		t.Errorf("display size is not forgotten")
+	}
+}